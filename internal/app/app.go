@@ -2,11 +2,13 @@ package app
 
 import (
 	"context"
-	"github.com/cybericebox/lib/pkg/ipam"
 	"github.com/cybericebox/lib/pkg/wgKeyGen"
 	"github.com/cybericebox/wireguard/internal/config"
 	"github.com/cybericebox/wireguard/internal/delivery/controller"
+	dnsServer "github.com/cybericebox/wireguard/internal/delivery/dns"
+	"github.com/cybericebox/wireguard/internal/delivery/kernel"
 	"github.com/cybericebox/wireguard/internal/delivery/repository"
+	"github.com/cybericebox/wireguard/internal/delivery/webhook"
 	"github.com/cybericebox/wireguard/internal/service"
 	"github.com/rs/zerolog/log"
 	"os"
@@ -20,21 +22,26 @@ func Run() {
 
 	repo := repository.NewRepository(repository.Dependencies{Config: &cfg.Repository})
 
-	ipaManager, err := ipam.NewIPAManager(ipam.Dependencies{
-		PostgresConfig: ipam.PostgresConfig(cfg.Repository.Postgres),
-		CIDR:           cfg.Service.VPN.CIDR,
-	})
+	ipaManager, err := repo.NewIPAManager(cfg.Service.VPN.CIDR)
 	if err != nil {
 		log.Fatal().Err(err).Msg("Failed to create IPAManager")
 	}
 
 	keyGen := wgKeyGen.NewKeyGenerator()
 
+	var peerBackend service.PeerBackend = kernel.NewWireGuard(kernel.WireGuardDependencies{Config: &cfg.Service.VPN})
+	if cfg.Service.VPN.DataPlane == config.VPNDataPlaneUserspace {
+		peerBackend = kernel.NewUserspaceWireGuard(kernel.UserspaceWireGuardDependencies{Config: &cfg.Service.VPN})
+	}
+
 	wgService := service.NewService(service.Dependencies{
-		Repository:   repo,
-		IPAManager:   ipaManager,
-		KeyGenerator: keyGen,
-		Config:       &cfg.Service.VPN,
+		Repository:    repo,
+		IPAManager:    ipaManager,
+		PeerBackend:   peerBackend,
+		Firewall:      kernel.NewIPTables(kernel.IPTablesDependencies{}),
+		WebhookSender: webhook.NewSender(webhook.Dependencies{Config: &cfg.Service.VPN.Webhooks}),
+		KeyGenerator:  keyGen,
+		Config:        &cfg.Service.VPN,
 	})
 
 	ctx := context.Background()
@@ -47,6 +54,20 @@ func Run() {
 		log.Fatal().Err(err).Msg("Failed to init server users")
 	}
 
+	if err = wgService.InitNode(ctx); err != nil {
+		log.Fatal().Err(err).Msg("Failed to init node")
+	}
+
+	backgroundCtx, stopBackground := context.WithCancel(ctx)
+	go wgService.RunNodeHeartbeat(backgroundCtx)
+	go wgService.RunReconcile(backgroundCtx)
+	go wgService.RunHandshakeSync(backgroundCtx)
+	go wgService.RunSessionSampler(backgroundCtx)
+	// the nodes without webhooks neither queue nor deliver the events
+	if len(cfg.Service.VPN.Webhooks.URLs) > 0 {
+		go wgService.RunWebhookDelivery(backgroundCtx)
+	}
+
 	ctrl := controller.NewController(controller.Dependencies{
 		Config:  &cfg.Controller,
 		Service: wgService,
@@ -54,8 +75,26 @@ func Run() {
 
 	ctrl.Start()
 
-	if _, err = os.Create("/ready"); err != nil {
-		log.Fatal().Err(err).Msg("Failed to create ready file")
+	// the DNS server is bound to the server address, so it starts after the interface is up
+	var (
+		dnsSrv *dnsServer.Server
+		// dnsErrs is nil without the DNS server, so it never stops the application
+		dnsErrs <-chan error
+	)
+	if cfg.Service.VPN.DNS.Enabled {
+		if err = wgService.SyncDNSRecords(ctx); err != nil {
+			log.Fatal().Err(err).Msg("Failed to load DNS records")
+		}
+		go wgService.RunDNSRecordSync(backgroundCtx)
+
+		dnsSrv = dnsServer.NewServer(dnsServer.Dependencies{
+			Config:  &cfg.Service.VPN,
+			Service: wgService,
+		})
+		if err = dnsSrv.Start(); err != nil {
+			log.Fatal().Err(err).Msg("Failed to start DNS server")
+		}
+		dnsErrs = dnsSrv.Errors()
 	}
 
 	log.Info().Msg("Application started")
@@ -64,11 +103,34 @@ func Run() {
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGTERM, syscall.SIGINT)
 
-	<-quit
+	// the application is stopped gracefully if the DNS server fails
+	select {
+	case <-quit:
+	case err = <-dnsErrs:
+		log.Error().Err(err).Msg("DNS server failed, stopping application")
+	}
 
 	// Stop the controller
-	ctrl.Stop()
+	stopCtx, cancel := context.WithTimeout(ctx, cfg.Controller.GRPC.ShutdownTimeout)
+	defer cancel()
+	ctrl.Stop(stopCtx)
 	log.Info().Msg("Controller stopped")
+	// Stop the DNS server
+	if dnsSrv != nil {
+		dnsSrv.Stop(stopCtx)
+		log.Info().Msg("DNS server stopped")
+	}
+	// Stop the node heartbeat, reconcile and handshake sync
+	stopBackground()
+	log.Info().Msg("Background jobs stopped")
+	// Shutdown the server
+	// the controller drain and the teardown fit into the termination grace period of the pod
+	shutdownCtx, cancelShutdown := context.WithTimeout(ctx, cfg.Service.VPN.ShutdownTimeout)
+	defer cancelShutdown()
+	if err = wgService.Shutdown(shutdownCtx); err != nil {
+		log.Error().Err(err).Msg("Failed to shutdown server")
+	}
+	log.Info().Msg("Server stopped")
 	// Stop the repository
 	repo.Close()
 	log.Info().Msg("Repository closed")
@@ -4,14 +4,55 @@ import (
 	"flag"
 	"fmt"
 	"github.com/cybericebox/lib/pkg/wgKeyGen"
+	"github.com/cybericebox/wireguard/internal/model"
 	"github.com/ilyakaznacheev/cleanenv"
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
 	"os"
+	"slices"
+	"time"
 )
 
 const (
 	VPNKeyPair = "vpn-keypair"
+	// VPNInterface is the name of the server interface
+	VPNInterface = "wg0"
+)
+
+// Data planes
+const (
+	// VPNDataPlaneKernel runs the interface by the kernel module, it is managed with wg and wg-quick
+	VPNDataPlaneKernel = "kernel"
+	// VPNDataPlaneUserspace runs the interface by the embedded wireguard-go on the TUN device, the kernel module is not needed
+	VPNDataPlaneUserspace = "userspace"
+)
+
+// Nodes
+const (
+	DefaultNodeID = "default"
+
+	// NodeAssignmentGroup assigns new clients to the node which already serves their group
+	NodeAssignmentGroup = "group"
+	// NodeAssignmentLoad assigns new clients to the node with the least clients
+	NodeAssignmentLoad = "load"
+)
+
+// Repository drivers
+const (
+	// RepositoryDriverPostgres stores the data and the addresses in Postgres, it is required by multiple nodes
+	RepositoryDriverPostgres = "postgres"
+	// RepositoryDriverSQLite stores the data and the addresses in the embedded SQLite database of the single node
+	RepositoryDriverSQLite = "sqlite"
+)
+
+// Authentication modes
+const (
+	// AuthModeJWT authenticates the requests by the tokens
+	AuthModeJWT = "jwt"
+	// AuthModeMTLS authenticates the requests by the client certificates
+	AuthModeMTLS = "mtls"
+	// AuthModeAny authenticates the requests by the tokens if they are present and by the client certificates otherwise
+	AuthModeAny = "any"
 )
 
 // Environments
@@ -33,13 +74,20 @@ type (
 
 	ControllerConfig struct {
 		GRPC GRPCConfig `yaml:"grpc"`
+		HTTP HTTPConfig `yaml:"http"`
+	}
+
+	HTTPConfig struct {
+		Host string `yaml:"host" env:"WG_HTTP_HOST" env-default:"0.0.0.0" env-description:"Host of HTTP health server"`
+		Port string `yaml:"port" env:"WG_HTTP_PORT" env-default:"8080" env-description:"Port of HTTP health server"`
 	}
 
 	GRPCConfig struct {
-		Host string     `yaml:"host" env:"WG_GRPC_HOST" env-default:"0.0.0.0" env-description:"Host of GRPC server"`
-		Port string     `yaml:"port" env:"WG_GRPC_PORT" env-default:"5454" env-description:"Port of GRPC server"`
-		Auth AuthConfig `yaml:"auth"`
-		TLS  TLSConfig  `yaml:"tls"`
+		Host            string        `yaml:"host" env:"WG_GRPC_HOST" env-default:"0.0.0.0" env-description:"Host of GRPC server"`
+		Port            string        `yaml:"port" env:"WG_GRPC_PORT" env-default:"5454" env-description:"Port of GRPC server"`
+		ShutdownTimeout time.Duration `yaml:"shutdownTimeout" env:"WG_GRPC_SHUTDOWN_TIMEOUT" env-default:"30s" env-description:"Time to drain in-flight RPCs of GRPC server on shutdown"`
+		Auth            AuthConfig    `yaml:"auth"`
+		TLS             TLSConfig     `yaml:"tls"`
 	}
 
 	TLSConfig struct {
@@ -50,8 +98,22 @@ type (
 	}
 
 	AuthConfig struct {
-		AuthKey string `yaml:"authKey" env:"WG_GRPC_AUTH_KEY" env-description:"Auth key of GRPC server"`
-		SignKey string `yaml:"signKey" env:"WG_GRPC_SIGN_KEY" env-description:"Sign key of GRPC server"`
+		Mode               string            `yaml:"mode" env:"WG_GRPC_AUTH_MODE" env-default:"jwt" env-description:"Authentication mode of GRPC server (jwt, mtls or any)"`
+		CertIdentitiesFile string            `yaml:"certIdentitiesFile" env:"WG_GRPC_AUTH_CERT_IDENTITIES_FILE" env-default:"" env-description:"YAML file mapping client certificate subjects and SANs to roles"`
+		AuthKey            string            `yaml:"authKey" env:"WG_GRPC_AUTH_KEY" env-description:"Auth key of GRPC server"`
+		SignKey            string            `yaml:"signKey" env:"WG_GRPC_SIGN_KEY" env-description:"Sign key of GRPC server tokens without key ID"`
+		SignKeys           map[string]string `yaml:"signKeys" env:"WG_GRPC_SIGN_KEYS" env-description:"Active sign keys of GRPC server by key ID (kid1:key1,kid2:key2)"`
+		Issuer             string            `yaml:"issuer" env:"WG_GRPC_AUTH_ISSUER" env-default:"" env-description:"Required issuer of GRPC server tokens, not checked if empty"`
+		Audience           string            `yaml:"audience" env:"WG_GRPC_AUTH_AUDIENCE" env-default:"wireguard" env-description:"Required audience of GRPC server tokens, not checked if empty"`
+		MaxTokenTTL        time.Duration     `yaml:"maxTokenTTL" env:"WG_GRPC_AUTH_MAX_TOKEN_TTL" env-default:"1h" env-description:"Maximum lifetime of GRPC server tokens"`
+		// asymmetric signatures are verified with the public keys, the sign keys are not needed then
+		PublicKeyFile      string        `yaml:"publicKeyFile" env:"WG_GRPC_AUTH_PUBLIC_KEY_FILE" env-default:"" env-description:"PEM file with public keys of GRPC server tokens without key ID"`
+		JWKSFile           string        `yaml:"jwksFile" env:"WG_GRPC_AUTH_JWKS_FILE" env-default:"" env-description:"JWKS file with public keys of GRPC server tokens"`
+		KeysReloadInterval time.Duration `yaml:"keysReloadInterval" env:"WG_GRPC_AUTH_KEYS_RELOAD_INTERVAL" env-default:"1m" env-description:"Interval of reloading public keys of GRPC server tokens"`
+		// the requests forwarded between the nodes carry the caller identity signed by the node key instead of the caller credentials,
+		// so they are authenticated in every mode without listing the node certificates in the identities file.
+		// The forwarded requests are sent only over TLS, the server does not start with the node key and without TLS
+		NodeKey string `yaml:"nodeKey" env:"WG_GRPC_NODE_KEY" env-default:"" env-description:"Key shared by VPN nodes to sign forwarded requests, requires TLS, requests are not forwarded if empty"`
 	}
 
 	ServiceConfig struct {
@@ -59,15 +121,60 @@ type (
 	}
 
 	RepositoryConfig struct {
+		Driver   string         `yaml:"driver" env:"REPOSITORY_DRIVER" env-default:"postgres" env-description:"Driver of repository (postgres, sqlite)"`
 		Postgres PostgresConfig `yaml:"postgres"`
+		SQLite   SQLiteConfig   `yaml:"sqlite"`
 	}
 
 	VPNConfig struct {
-		Endpoint string `yaml:"endpoint" env:"VPN_ENDPOINT" env-default:"" env-description:"VPN server endpoint"`
-		CIDR     string `yaml:"cidr" env:"VPN_CIDR" env-default:"10.128.0.0/16" env-description:"VPN clients CIDR"`
-		Address  string
-		Port     string `yaml:"port" env:"VPN_PORT" env-default:"51820" env-description:"VPN server listen port"`
-		KeyPair  *wgKeyGen.KeyPair
+		Endpoint              string `yaml:"endpoint" env:"VPN_ENDPOINT" env-default:"" env-description:"VPN server endpoint"`
+		CIDR                  string `yaml:"cidr" env:"VPN_CIDR" env-default:"10.128.0.0/16" env-description:"VPN clients CIDR"`
+		Address               string
+		Port                  string        `yaml:"port" env:"VPN_PORT" env-default:"51820" env-description:"VPN server listen port"`
+		DataPlane             string        `yaml:"dataPlane" env:"VPN_DATA_PLANE" env-default:"kernel" env-description:"VPN data plane (kernel or userspace)"`
+		KeepPeersOnShutdown   bool          `yaml:"keepPeersOnShutdown" env:"VPN_KEEP_PEERS_ON_SHUTDOWN" env-default:"false" env-description:"Keep VPN interface, peers and rules on shutdown"`
+		ShutdownTimeout       time.Duration `yaml:"shutdownTimeout" env:"VPN_SHUTDOWN_TIMEOUT" env-default:"10s" env-description:"Time to remove VPN rules and interface on shutdown"`
+		ReconcileInterval     time.Duration `yaml:"reconcileInterval" env:"VPN_RECONCILE_INTERVAL" env-default:"1m" env-description:"Interval of reconciling VPN peers and rules with clients"`
+		HandshakeSyncInterval time.Duration `yaml:"handshakeSyncInterval" env:"VPN_HANDSHAKE_SYNC_INTERVAL" env-default:"30s" env-description:"Interval of storing last handshakes of VPN peers"`
+		SessionSampleInterval time.Duration `yaml:"sessionSampleInterval" env:"VPN_SESSION_SAMPLE_INTERVAL" env-default:"30s" env-description:"Interval of sampling VPN peers to record connection sessions"`
+		SessionTimeout        time.Duration `yaml:"sessionTimeout" env:"VPN_SESSION_TIMEOUT" env-default:"3m" env-description:"Time since the last handshake after which VPN peer is offline and its session ends"`
+		KeyPair               *wgKeyGen.KeyPair
+		Node                  NodeConfig     `yaml:"node"`
+		DNS                   DNSConfig      `yaml:"dns"`
+		Webhooks              WebhooksConfig `yaml:"webhooks"`
+	}
+
+	NodeConfig struct {
+		ID                string        `yaml:"id" env:"VPN_NODE_ID" env-default:"default" env-description:"Unique ID of the VPN node"`
+		GRPCEndpoint      string        `yaml:"grpcEndpoint" env:"VPN_NODE_GRPC_ENDPOINT" env-default:"" env-description:"GRPC endpoint of the VPN node reachable by other nodes"`
+		Assignment        string        `yaml:"assignment" env:"VPN_NODE_ASSIGNMENT" env-default:"group" env-description:"Strategy of assigning clients to nodes (group or load)"`
+		HeartbeatInterval time.Duration `yaml:"heartbeatInterval" env:"VPN_NODE_HEARTBEAT_INTERVAL" env-default:"10s" env-description:"Interval of VPN node heartbeats"`
+		HeartbeatTimeout  time.Duration `yaml:"heartbeatTimeout" env:"VPN_NODE_HEARTBEAT_TIMEOUT" env-default:"30s" env-description:"Time after which VPN node without heartbeat is considered dead"`
+	}
+
+	// DNSConfig is the configuration of the DNS forwarder bound to the VPN server address
+	DNSConfig struct {
+		Enabled   bool          `yaml:"enabled" env:"VPN_DNS_ENABLED" env-default:"false" env-description:"Serve DNS of lab hostnames on VPN server address"`
+		Port      string        `yaml:"port" env:"VPN_DNS_PORT" env-default:"53" env-description:"Port of DNS server"`
+		Upstreams []string      `yaml:"upstreams" env:"VPN_DNS_UPSTREAMS" env-default:"1.1.1.1:53,8.8.8.8:53" env-description:"Upstream DNS servers of other names (host:port)"`
+		Timeout   time.Duration `yaml:"timeout" env:"VPN_DNS_TIMEOUT" env-default:"2s" env-description:"Timeout of upstream DNS queries"`
+		TTL       uint32        `yaml:"ttl" env:"VPN_DNS_TTL" env-default:"60" env-description:"TTL of lab hostname answers in seconds"`
+		// the records are created on any node, so every node reloads them
+		SyncInterval time.Duration `yaml:"syncInterval" env:"VPN_DNS_SYNC_INTERVAL" env-default:"30s" env-description:"Interval of reloading lab hostnames from db"`
+	}
+
+	// WebhooksConfig is the configuration of the outbound webhooks of the client events
+	WebhooksConfig struct {
+		URLs   []string `yaml:"urls" env:"VPN_WEBHOOK_URLS" env-default:"" env-description:"URLs the client events are delivered to, webhooks are disabled if empty"`
+		Secret string   `yaml:"secret" env:"VPN_WEBHOOK_SECRET" env-default:"" env-description:"Secret of HMAC-SHA256 signatures of webhook requests"`
+		Events []string `yaml:"events" env:"VPN_WEBHOOK_EVENTS" env-default:"" env-description:"Client events delivered by webhooks, all events if empty"`
+		// the events are queued in db, so they are delivered by any node and survive restarts
+		Interval     time.Duration `yaml:"interval" env:"VPN_WEBHOOK_INTERVAL" env-default:"5s" env-description:"Interval of delivering queued webhook events"`
+		Timeout      time.Duration `yaml:"timeout" env:"VPN_WEBHOOK_TIMEOUT" env-default:"10s" env-description:"Timeout of webhook requests"`
+		BatchSize    int32         `yaml:"batchSize" env:"VPN_WEBHOOK_BATCH_SIZE" env-default:"100" env-description:"Number of webhook events delivered at once"`
+		MaxAttempts  int32         `yaml:"maxAttempts" env:"VPN_WEBHOOK_MAX_ATTEMPTS" env-default:"10" env-description:"Number of attempts to deliver webhook event"`
+		RetryBackoff time.Duration `yaml:"retryBackoff" env:"VPN_WEBHOOK_RETRY_BACKOFF" env-default:"30s" env-description:"Delay of the first retry of webhook event, it doubles with every attempt"`
+		MaxBackoff   time.Duration `yaml:"maxBackoff" env:"VPN_WEBHOOK_MAX_BACKOFF" env-default:"1h" env-description:"Maximum delay of webhook event retries"`
 	}
 
 	// PostgresConfig is the configuration for the Postgres database
@@ -79,6 +186,11 @@ type (
 		Database string `yaml:"database" env:"POSTGRES_DB" env-description:"Database of Postgres"`
 		SSLMode  string `yaml:"sslMode" env:"POSTGRES_SSL_MODE" env-default:"require" env-description:"SSL mode of Postgres"`
 	}
+
+	// SQLiteConfig is the configuration for the embedded SQLite database
+	SQLiteConfig struct {
+		Path string `yaml:"path" env:"SQLITE_PATH" env-default:"wireguard.db" env-description:"Path to SQLite database file"`
+	}
 )
 
 func MustGetConfig() *Config {
@@ -118,6 +230,60 @@ func MustGetConfig() *Config {
 		MigrationPath = "internal/delivery/repository/postgres/migrations"
 	}
 
+	// use hostname as node gRPC endpoint if it is not set
+	if instance.Service.VPN.Node.GRPCEndpoint == "" {
+		hostname, err := os.Hostname()
+		if err != nil {
+			log.Fatal().Err(err).Msg("Failed to get hostname")
+			return nil
+		}
+		instance.Service.VPN.Node.GRPCEndpoint = fmt.Sprintf("%s:%s", hostname, instance.Controller.GRPC.Port)
+	}
+
+	if instance.Service.VPN.Node.Assignment != NodeAssignmentGroup && instance.Service.VPN.Node.Assignment != NodeAssignmentLoad {
+		log.Fatal().Str("assignment", instance.Service.VPN.Node.Assignment).Msg("Invalid node assignment strategy")
+		return nil
+	}
+
+	if instance.Service.VPN.DataPlane != VPNDataPlaneKernel && instance.Service.VPN.DataPlane != VPNDataPlaneUserspace {
+		log.Fatal().Str("dataPlane", instance.Service.VPN.DataPlane).Msg("Invalid VPN data plane")
+		return nil
+	}
+
+	if instance.Service.VPN.DNS.Enabled && len(instance.Service.VPN.DNS.Upstreams) == 0 {
+		log.Fatal().Msg("DNS server requires at least one upstream")
+		return nil
+	}
+
+	if len(instance.Service.VPN.Webhooks.URLs) > 0 && instance.Service.VPN.Webhooks.Secret == "" {
+		log.Fatal().Msg("Webhooks require secret of signatures")
+		return nil
+	}
+
+	for _, event := range instance.Service.VPN.Webhooks.Events {
+		if !slices.Contains(model.WebhookEventTypes, event) {
+			log.Fatal().Str("event", event).Msg("Invalid webhook event")
+			return nil
+		}
+	}
+
+	if instance.Repository.Driver != RepositoryDriverPostgres && instance.Repository.Driver != RepositoryDriverSQLite {
+		log.Fatal().Str("driver", instance.Repository.Driver).Msg("Invalid repository driver")
+		return nil
+	}
+
+	switch instance.Controller.GRPC.Auth.Mode {
+	case AuthModeJWT:
+	case AuthModeMTLS, AuthModeAny:
+		if !instance.Controller.GRPC.TLS.Enabled || instance.Controller.GRPC.Auth.CertIdentitiesFile == "" {
+			log.Fatal().Str("mode", instance.Controller.GRPC.Auth.Mode).Msg("Authentication by client certificates requires TLS and certificate identities file")
+			return nil
+		}
+	default:
+		log.Fatal().Str("mode", instance.Controller.GRPC.Auth.Mode).Msg("Invalid authentication mode")
+		return nil
+	}
+
 	// create VPN key pair
 	instance.Service.VPN.KeyPair = &wgKeyGen.KeyPair{}
 
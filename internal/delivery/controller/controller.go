@@ -1,20 +1,37 @@
 package controller
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"github.com/cybericebox/wireguard/internal/config"
 	grpcController "github.com/cybericebox/wireguard/internal/delivery/controller/grpc"
+	httpController "github.com/cybericebox/wireguard/internal/delivery/controller/http"
+	"github.com/cybericebox/wireguard/pkg/controller/grpc/protobuf"
 	"github.com/rs/zerolog/log"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 	"net"
+	"net/http"
+	"time"
 )
 
+// healthInterval is the interval of updating the gRPC health status
+const healthInterval = 10 * time.Second
+
 type (
 	// Controller is the API for the application
 	Controller struct {
-		config *config.ControllerConfig
+		config  *config.ControllerConfig
+		service Service
 		// grpcController is the controller for the grpc server
 		grpcController *grpc.Server
+		// httpController is the controller for the health endpoints
+		httpController *http.Server
+		// health is the gRPC health service
+		health     *health.Server
+		stopHealth context.CancelFunc
 	}
 
 	// Service is the API for the service layer
@@ -22,6 +39,9 @@ type (
 
 		// IService is dependencies for the grpc controller
 		grpcController.IService
+
+		// IService is dependencies for the http controller
+		httpController.IService
 	}
 
 	// Dependencies for the controller
@@ -33,16 +53,25 @@ type (
 
 // NewController creates a new controller
 func NewController(deps Dependencies) *Controller {
+	healthServer := health.NewServer()
+
 	grpcCont, err := grpcController.New(grpcController.Dependencies{
 		Config:  &deps.Config.GRPC,
 		Service: deps.Service,
+		Health:  healthServer,
 	})
 	if err != nil {
 		log.Fatal().Err(err).Msg("Failed to setup grpc server")
 	}
 	return &Controller{
 		grpcController: grpcCont,
-		config:         deps.Config,
+		httpController: httpController.New(httpController.Dependencies{
+			Config:  &deps.Config.HTTP,
+			Service: deps.Service,
+		}),
+		health:  healthServer,
+		config:  deps.Config,
+		service: deps.Service,
 	}
 }
 
@@ -58,9 +87,60 @@ func (c *Controller) Start() {
 		}
 	}()
 	log.Info().Msgf("gRPC server is running at %s...\n", fmt.Sprintf("%s:%s", c.config.GRPC.Host, c.config.GRPC.Port))
+
+	go func() {
+		if err := c.httpController.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Fatal().Err(err).Msg("Failed to serve health endpoints")
+		}
+	}()
+	log.Info().Msgf("HTTP health server is running at %s...\n", c.httpController.Addr)
+
+	var ctx context.Context
+	ctx, c.stopHealth = context.WithCancel(context.Background())
+	go c.watchHealth(ctx)
+}
+
+// watchHealth keeps the gRPC health status in sync with the health checks of the service
+func (c *Controller) watchHealth(ctx context.Context) {
+	ticker := time.NewTicker(healthInterval)
+	defer ticker.Stop()
+
+	for {
+		status := healthpb.HealthCheckResponse_SERVING
+		if !c.service.CheckHealth(ctx).Ready {
+			status = healthpb.HealthCheckResponse_NOT_SERVING
+		}
+		c.health.SetServingStatus("", status)
+		c.health.SetServingStatus(protobuf.Wireguard_ServiceDesc.ServiceName, status)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
 }
 
-// Stop stops the controller
-func (c *Controller) Stop() {
-	c.grpcController.GracefulStop()
+// Stop stops the controller, in-flight RPCs are drained until the context is done
+func (c *Controller) Stop(ctx context.Context) {
+	// stop reporting health and report not serving to drain the clients
+	c.stopHealth()
+	c.health.Shutdown()
+
+	stopped := make(chan struct{})
+	go func() {
+		c.grpcController.GracefulStop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+	case <-ctx.Done():
+		log.Warn().Msg("Failed to drain gRPC server in time, closing remaining connections")
+		c.grpcController.Stop()
+	}
+
+	if err := c.httpController.Shutdown(ctx); err != nil {
+		log.Error().Err(err).Msg("Failed to shutdown HTTP health server")
+	}
 }
@@ -7,40 +7,240 @@ import (
 	"github.com/cybericebox/wireguard/pkg/controller/grpc/protobuf"
 	"github.com/gofrs/uuid"
 	"github.com/rs/zerolog/log"
+	"strconv"
+	"time"
 )
 
 type IActionsService interface {
-	GetClients(ctx context.Context, userID, groupID uuid.UUID) ([]*model.Client, error)
+	ListClients(ctx context.Context, query model.ClientsQuery) (*model.ClientsPage, error)
 	GetClientConfig(ctx context.Context, userID, groupID uuid.UUID, destCIDR string) (string, error)
 	DeleteClients(ctx context.Context, userID, groupID uuid.UUID) (int64, error)
-	BanClients(ctx context.Context, userID, groupID uuid.UUID) (int64, error)
+	GetClientDetails(ctx context.Context, userID, groupID uuid.UUID) (*model.ClientDetails, error)
+	BanClients(ctx context.Context, userID, groupID uuid.UUID, reason string) (int64, error)
 	UnBanClients(ctx context.Context, userID, groupID uuid.UUID) (int64, error)
+	SetClientLimits(ctx context.Context, userID, groupID uuid.UUID, expiresAt time.Time, transferQuota int64) (int64, error)
+	PlanClientsOperation(ctx context.Context, operation string, userID, groupID uuid.UUID) ([]*model.Client, []*model.PlannedChange)
+	PlanClientConfig(ctx context.Context, userID, groupID uuid.UUID, destCIDR string) ([]*model.PlannedChange, error)
 }
 
-func (w *Wireguard) GetClients(ctx context.Context, request *protobuf.ClientsRequest) (*protobuf.ClientsResponse, error) {
-	log.Debug().Str("userID", request.GetUserID()).Str("groupID", request.GetGroupID()).Msg("Getting clients")
-	clients, err := w.service.GetClients(ctx, uuid.FromStringOrNil(request.GetUserID()), uuid.FromStringOrNil(request.GetGroupID()))
+// parseClientsRequest parses the user and group IDs strictly, empty IDs are nil
+func parseClientsRequest(request clientsTarget) (userID, groupID uuid.UUID, err error) {
+	if request.GetUserID() != "" {
+		if userID, err = uuid.FromString(request.GetUserID()); err != nil {
+			return uuid.Nil, uuid.Nil, appError.ErrClientInvalidUserID.WithError(err).Err()
+		}
+	}
+
+	if request.GetGroupID() != "" {
+		if groupID, err = uuid.FromString(request.GetGroupID()); err != nil {
+			return uuid.Nil, uuid.Nil, appError.ErrClientInvalidGroupID.WithError(err).Err()
+		}
+	}
+
+	return userID, groupID, nil
+}
+
+// parseScopedClientsRequest parses the request of the operation which changes clients.
+// The operation on all clients has to be confirmed with AllClients.
+func parseScopedClientsRequest(request *protobuf.ClientsRequest) (userID, groupID uuid.UUID, err error) {
+	userID, groupID, err = parseClientsRequest(request)
 	if err != nil {
-		log.Error().Err(err).Msg("Getting clients")
-		return &protobuf.ClientsResponse{}, err
+		return uuid.Nil, uuid.Nil, err
 	}
-	log.Debug().Str("userID", request.GetUserID()).Str("groupID", request.GetGroupID()).Msg("Returning clients")
+
+	unscoped := userID.IsNil() && groupID.IsNil()
+	if unscoped && !request.GetAllClients() {
+		return uuid.Nil, uuid.Nil, appError.ErrClientUnscopedOperation.Err()
+	}
+
+	if !unscoped && request.GetAllClients() {
+		return uuid.Nil, uuid.Nil, appError.ErrClientAmbiguousScope.Err()
+	}
+
+	return userID, groupID, nil
+}
+
+// clientsRequestParameters are the audit parameters of the operation on clients
+func clientsRequestParameters(request *protobuf.ClientsRequest) map[string]string {
+	return map[string]string{
+		"allClients": strconv.FormatBool(request.GetAllClients()),
+		"dryRun":     strconv.FormatBool(request.GetDryRun()),
+	}
+}
+
+func protobufPlan(plan []*model.PlannedChange) []*protobuf.PlannedChange {
+	pPlan := make([]*protobuf.PlannedChange, 0, len(plan))
+	for _, change := range plan {
+		pPlan = append(pPlan, &protobuf.PlannedChange{
+			UserID:   change.UserID.String(),
+			GroupID:  change.GroupID.String(),
+			NodeID:   change.NodeID,
+			Resource: change.Resource,
+			Action:   change.Action,
+			Target:   change.Target,
+		})
+	}
+	return pPlan
+}
+
+func protobufClients(clients []*model.Client) []*protobuf.Client {
 	pClients := make([]*protobuf.Client, 0, len(clients))
 	for _, client := range clients {
-		pClients = append(pClients, &protobuf.Client{
+		pClient := &protobuf.Client{
 			UserID:   client.UserID.String(),
 			GroupID:  client.GroupID.String(),
 			Banned:   client.Banned,
 			LastSeen: client.LastSeen,
-		})
+			Address:  client.Address,
+			NodeID:   client.NodeID,
+		}
+		pClient.CreatedAt = unixOrZero(client.CreatedAt)
+		pClients = append(pClients, pClient)
+	}
+	return pClients
+}
+
+// unixOrZero returns the unix timestamp of the time, the zero time is zero
+func unixOrZero(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
 	}
+	return t.Unix()
+}
+
+// dryRunClients returns the clients of all nodes the operation would change and the changes it would make
+func (w *Wireguard) dryRunClients(ctx context.Context, operation string, userID, groupID uuid.UUID, remote func(ctx context.Context, c protobuf.WireguardClient) (*protobuf.ClientsAffectedResponse, error)) (*protobuf.ClientsAffectedResponse, error) {
+	log.Debug().Str("operation", operation).Str("userID", userID.String()).Str("groupID", groupID.String()).Msg("Dry running clients operation")
+	clients, plan := w.service.PlanClientsOperation(ctx, operation, userID, groupID)
+	pClients, pPlan := protobufClients(clients), protobufPlan(plan)
+
+	if err := w.forEachRemoteNode(ctx, func(ctx context.Context, c protobuf.WireguardClient) error {
+		resp, err := remote(ctx, c)
+		if err != nil {
+			return err
+		}
+		pClients = append(pClients, resp.GetClients()...)
+		pPlan = append(pPlan, resp.GetPlan()...)
+		return nil
+	}); err != nil {
+		return &protobuf.ClientsAffectedResponse{}, err
+	}
+
+	return &protobuf.ClientsAffectedResponse{
+		ClientsAffected: int64(len(pClients)),
+		Clients:         pClients,
+		Plan:            pPlan,
+	}, nil
+}
 
+// GetClients returns the page of clients of all nodes from the database, so the other nodes are not asked
+func (w *Wireguard) GetClients(ctx context.Context, request *protobuf.GetClientsRequest) (*protobuf.ClientsResponse, error) {
+	log.Debug().Str("userID", request.GetUserID()).Str("groupID", request.GetGroupID()).Msg("Getting clients")
+	userID, groupID, err := parseClientsRequest(request)
+	if err != nil {
+		log.Error().Err(err).Msg("Parsing clients request")
+		return &protobuf.ClientsResponse{}, err
+	}
+
+	query := model.ClientsQuery{
+		UserID:         userID,
+		GroupID:        groupID,
+		OnlineWithin:   time.Duration(request.GetOnlineWithin()) * time.Second,
+		AddressCIDR:    request.GetAddressCIDR(),
+		SortBy:         request.GetSortBy(),
+		SortDescending: request.GetSortDescending(),
+		PageSize:       request.GetPageSize(),
+		PageToken:      request.GetPageToken(),
+	}
+
+	if request.Banned != nil {
+		banned := request.GetBanned()
+		query.Banned = &banned
+	}
+
+	if request.GetCreatedAfter() > 0 {
+		query.CreatedAfter = time.Unix(request.GetCreatedAfter(), 0)
+	}
+
+	page, err := w.service.ListClients(ctx, query)
+	if err != nil {
+		log.Error().Err(err).Msg("Getting clients")
+		return &protobuf.ClientsResponse{}, err
+	}
+
+	log.Debug().Str("userID", request.GetUserID()).Str("groupID", request.GetGroupID()).Msg("Returning clients")
 	return &protobuf.ClientsResponse{
-		Clients: pClients,
+		Clients:       protobufClients(page.Clients),
+		NextPageToken: page.NextPageToken,
+	}, nil
+}
+
+// GetClient returns the details of the client from its node, which knows the state of the client peer
+func (w *Wireguard) GetClient(ctx context.Context, request *protobuf.ClientRequest) (*protobuf.ClientDetailsResponse, error) {
+	log.Debug().Str("userID", request.GetUserID()).Str("groupID", request.GetGroupID()).Msg("Getting client")
+
+	userID, err := uuid.FromString(request.GetUserID())
+	if err != nil {
+		log.Error().Err(err).Msg("Parsing user ID")
+		return &protobuf.ClientDetailsResponse{}, appError.ErrClientInvalidUserID.Err()
+	}
+
+	groupID, err := uuid.FromString(request.GetGroupID())
+	if err != nil {
+		log.Error().Err(err).Msg("Parsing group ID")
+		return &protobuf.ClientDetailsResponse{}, appError.ErrClientInvalidGroupID.Err()
+	}
+
+	owner, ctx, err := w.clientOwner(ctx, userID, groupID)
+	if err != nil {
+		log.Error().Err(err).Msg("Getting client node")
+		return &protobuf.ClientDetailsResponse{}, err
+	}
+
+	if owner != nil {
+		return owner.GetClient(ctx, request)
+	}
+
+	details, err := w.service.GetClientDetails(ctx, userID, groupID)
+	if err != nil {
+		log.Error().Err(err).Msg("Getting client details")
+		return &protobuf.ClientDetailsResponse{}, err
+	}
+
+	log.Debug().Str("userID", request.GetUserID()).Str("groupID", request.GetGroupID()).Msg("Returning client")
+	return &protobuf.ClientDetailsResponse{
+		Client: &protobuf.ClientDetails{
+			UserID:          details.UserID.String(),
+			GroupID:         details.GroupID.String(),
+			NodeID:          details.NodeID,
+			Address:         details.Address,
+			DNS:             details.DNS,
+			PublicKey:       details.PublicKey,
+			LaboratoryCIDR:  details.AllowedIPs,
+			ServerEndpoint:  details.ServerEndpoint,
+			Banned:          details.Banned,
+			BanReason:       details.BanReason,
+			CreatedAt:       unixOrZero(details.CreatedAt),
+			UpdatedAt:       unixOrZero(details.UpdatedAt),
+			LastHandshakeAt: unixOrZero(details.LastHandshakeAt),
+			LastSeen:        details.LastSeen,
+			Endpoint:        details.PeerEndpoint,
+			TransferRx:      details.TransferRx,
+			TransferTx:      details.TransferTx,
+			ExpiresAt:       unixOrZero(details.ExpiresAt),
+			TransferQuota:   details.TransferQuota,
+		},
 	}, nil
 }
 
-func (w *Wireguard) GetClientConfig(ctx context.Context, request *protobuf.ClientConfigRequest) (*protobuf.ConfigResponse, error) {
+func (w *Wireguard) GetClientConfig(ctx context.Context, request *protobuf.ClientConfigRequest) (_ *protobuf.ConfigResponse, err error) {
+	defer func(ctx context.Context) {
+		w.audit(ctx, model.AuditActionGetClientConfig, request, map[string]string{
+			"destCIDR": request.GetDestCIDR(),
+			"dryRun":   strconv.FormatBool(request.GetDryRun()),
+		}, 0, err)
+	}(ctx)
+
 	log.Info().Str("userID", request.GetUserID()).Str("groupID", request.GetGroupID()).Str("destCIDR", request.GetDestCIDR()).Msg("Get client config")
 
 	log.Debug().Str("userID", request.GetUserID()).Msg("Parsing user ID")
@@ -57,6 +257,26 @@ func (w *Wireguard) GetClientConfig(ctx context.Context, request *protobuf.Clien
 		return &protobuf.ConfigResponse{}, appError.ErrClientInvalidGroupID.Err()
 	}
 
+	owner, ctx, err := w.clientOwner(ctx, userID, groupID)
+	if err != nil {
+		log.Error().Err(err).Msg("Getting client node")
+		return &protobuf.ConfigResponse{}, err
+	}
+
+	if owner != nil {
+		return owner.GetClientConfig(ctx, request)
+	}
+
+	if request.GetDryRun() {
+		log.Debug().Str("destCIDR", request.GetDestCIDR()).Msg("Planning client config")
+		plan, err := w.service.PlanClientConfig(ctx, userID, groupID, request.GetDestCIDR())
+		if err != nil {
+			log.Error().Err(err).Msg("Planning client config")
+			return &protobuf.ConfigResponse{}, err
+		}
+		return &protobuf.ConfigResponse{Plan: protobufPlan(plan)}, nil
+	}
+
 	log.Debug().Str("destCIDR", request.GetDestCIDR()).Msg("Getting client config")
 	config, err := w.service.GetClientConfig(ctx, userID, groupID, request.GetDestCIDR())
 	if err != nil {
@@ -67,41 +287,198 @@ func (w *Wireguard) GetClientConfig(ctx context.Context, request *protobuf.Clien
 	return &protobuf.ConfigResponse{Config: config}, nil
 }
 
-func (w *Wireguard) DeleteClients(ctx context.Context, request *protobuf.ClientsRequest) (*protobuf.ClientsAffectedResponse, error) {
+func (w *Wireguard) DeleteClients(ctx context.Context, request *protobuf.ClientsRequest) (_ *protobuf.ClientsAffectedResponse, err error) {
+	var affected int64
+	defer func() {
+		w.audit(ctx, model.AuditActionDeleteClients, request, clientsRequestParameters(request), affected, err)
+	}()
+
+	userID, groupID, err := parseScopedClientsRequest(request)
+	if err != nil {
+		log.Error().Err(err).Msg("Parsing clients request")
+		return &protobuf.ClientsAffectedResponse{}, err
+	}
+
+	if request.GetDryRun() {
+		resp, err := w.dryRunClients(ctx, model.ClientsOperationDelete, userID, groupID, func(ctx context.Context, c protobuf.WireguardClient) (*protobuf.ClientsAffectedResponse, error) {
+			return c.DeleteClients(ctx, request)
+		})
+		affected = resp.GetClientsAffected()
+		return resp, err
+	}
+
 	log.Debug().Str("userID", request.GetUserID()).Str("groupID", request.GetGroupID()).Msg("Deleting clients")
-	affected, err := w.service.DeleteClients(ctx, uuid.FromStringOrNil(request.GetUserID()), uuid.FromStringOrNil(request.GetGroupID()))
+	affected, err = w.service.DeleteClients(ctx, userID, groupID)
 	if err != nil {
 		log.Error().Err(err).Msg("Deleting clients")
 		return &protobuf.ClientsAffectedResponse{}, err
 	}
+
+	if err = w.forEachRemoteNode(ctx, func(ctx context.Context, c protobuf.WireguardClient) error {
+		resp, err := c.DeleteClients(ctx, request)
+		if err != nil {
+			return err
+		}
+		affected += resp.GetClientsAffected()
+		return nil
+	}); err != nil {
+		log.Error().Err(err).Msg("Deleting clients on other nodes")
+		return &protobuf.ClientsAffectedResponse{}, err
+	}
+
 	log.Debug().Str("userID", request.GetUserID()).Str("groupID", request.GetGroupID()).Msg("Clients are deleted")
 	return &protobuf.ClientsAffectedResponse{
 		ClientsAffected: affected,
 	}, nil
 }
 
-func (w *Wireguard) BanClients(ctx context.Context, request *protobuf.ClientsRequest) (*protobuf.ClientsAffectedResponse, error) {
+func (w *Wireguard) BanClients(ctx context.Context, request *protobuf.ClientsRequest) (_ *protobuf.ClientsAffectedResponse, err error) {
+	var affected int64
+	defer func() {
+		parameters := clientsRequestParameters(request)
+		parameters["banReason"] = request.GetBanReason()
+		w.audit(ctx, model.AuditActionBanClients, request, parameters, affected, err)
+	}()
+
+	userID, groupID, err := parseScopedClientsRequest(request)
+	if err != nil {
+		log.Error().Err(err).Msg("Parsing clients request")
+		return &protobuf.ClientsAffectedResponse{}, err
+	}
+
+	if request.GetDryRun() {
+		resp, err := w.dryRunClients(ctx, model.ClientsOperationBan, userID, groupID, func(ctx context.Context, c protobuf.WireguardClient) (*protobuf.ClientsAffectedResponse, error) {
+			return c.BanClients(ctx, request)
+		})
+		affected = resp.GetClientsAffected()
+		return resp, err
+	}
+
 	log.Debug().Str("userID", request.GetUserID()).Str("groupID", request.GetGroupID()).Msg("Banning clients")
-	affected, err := w.service.BanClients(ctx, uuid.FromStringOrNil(request.GetUserID()), uuid.FromStringOrNil(request.GetGroupID()))
+	affected, err = w.service.BanClients(ctx, userID, groupID, request.GetBanReason())
 	if err != nil {
 		log.Error().Err(err).Msg("Banning clients")
 		return &protobuf.ClientsAffectedResponse{}, err
 	}
+
+	if err = w.forEachRemoteNode(ctx, func(ctx context.Context, c protobuf.WireguardClient) error {
+		resp, err := c.BanClients(ctx, request)
+		if err != nil {
+			return err
+		}
+		affected += resp.GetClientsAffected()
+		return nil
+	}); err != nil {
+		log.Error().Err(err).Msg("Banning clients on other nodes")
+		return &protobuf.ClientsAffectedResponse{}, err
+	}
+
 	log.Debug().Str("userID", request.GetUserID()).Str("groupID", request.GetGroupID()).Msg("Clients are banned")
 	return &protobuf.ClientsAffectedResponse{
 		ClientsAffected: affected,
 	}, nil
 }
 
-func (w *Wireguard) UnBanClients(ctx context.Context, request *protobuf.ClientsRequest) (*protobuf.ClientsAffectedResponse, error) {
+func (w *Wireguard) UnBanClients(ctx context.Context, request *protobuf.ClientsRequest) (_ *protobuf.ClientsAffectedResponse, err error) {
+	var affected int64
+	defer func() {
+		w.audit(ctx, model.AuditActionUnBanClients, request, clientsRequestParameters(request), affected, err)
+	}()
+
+	userID, groupID, err := parseScopedClientsRequest(request)
+	if err != nil {
+		log.Error().Err(err).Msg("Parsing clients request")
+		return &protobuf.ClientsAffectedResponse{}, err
+	}
+
+	if request.GetDryRun() {
+		resp, err := w.dryRunClients(ctx, model.ClientsOperationUnBan, userID, groupID, func(ctx context.Context, c protobuf.WireguardClient) (*protobuf.ClientsAffectedResponse, error) {
+			return c.UnBanClients(ctx, request)
+		})
+		affected = resp.GetClientsAffected()
+		return resp, err
+	}
+
 	log.Debug().Str("userID", request.GetUserID()).Str("groupID", request.GetGroupID()).Msg("Unbanning clients")
-	affected, err := w.service.UnBanClients(ctx, uuid.FromStringOrNil(request.GetUserID()), uuid.FromStringOrNil(request.GetGroupID()))
+	affected, err = w.service.UnBanClients(ctx, userID, groupID)
 	if err != nil {
 		log.Error().Err(err).Msg("Unbanning clients")
 		return &protobuf.ClientsAffectedResponse{}, err
 	}
+
+	if err = w.forEachRemoteNode(ctx, func(ctx context.Context, c protobuf.WireguardClient) error {
+		resp, err := c.UnBanClients(ctx, request)
+		if err != nil {
+			return err
+		}
+		affected += resp.GetClientsAffected()
+		return nil
+	}); err != nil {
+		log.Error().Err(err).Msg("Unbanning clients on other nodes")
+		return &protobuf.ClientsAffectedResponse{}, err
+	}
+
 	log.Debug().Str("userID", request.GetUserID()).Str("groupID", request.GetGroupID()).Msg("Clients are unbanned")
 	return &protobuf.ClientsAffectedResponse{
 		ClientsAffected: affected,
 	}, nil
 }
+
+func (w *Wireguard) SetClientLimits(ctx context.Context, request *protobuf.ClientsRequest) (_ *protobuf.ClientsAffectedResponse, err error) {
+	var affected int64
+	defer func() {
+		parameters := clientsRequestParameters(request)
+		parameters["expiresAt"] = strconv.FormatInt(request.GetExpiresAt(), 10)
+		parameters["transferQuota"] = strconv.FormatInt(request.GetTransferQuota(), 10)
+		w.audit(ctx, model.AuditActionSetClientLimits, request, parameters, affected, err)
+	}()
+
+	userID, groupID, err := parseScopedClientsRequest(request)
+	if err != nil {
+		log.Error().Err(err).Msg("Parsing clients request")
+		return &protobuf.ClientsAffectedResponse{}, err
+	}
+
+	if request.GetExpiresAt() < 0 || request.GetTransferQuota() < 0 {
+		err = appError.ErrClientInvalidLimits.WithContext("expiresAt", request.GetExpiresAt()).WithContext("transferQuota", request.GetTransferQuota()).Err()
+		log.Error().Err(err).Msg("Parsing client limits")
+		return &protobuf.ClientsAffectedResponse{}, err
+	}
+
+	if request.GetDryRun() {
+		resp, err := w.dryRunClients(ctx, model.ClientsOperationSetLimits, userID, groupID, func(ctx context.Context, c protobuf.WireguardClient) (*protobuf.ClientsAffectedResponse, error) {
+			return c.SetClientLimits(ctx, request)
+		})
+		affected = resp.GetClientsAffected()
+		return resp, err
+	}
+
+	var expiresAt time.Time
+	if request.GetExpiresAt() > 0 {
+		expiresAt = time.Unix(request.GetExpiresAt(), 0)
+	}
+
+	log.Debug().Str("userID", request.GetUserID()).Str("groupID", request.GetGroupID()).Msg("Setting client limits")
+	affected, err = w.service.SetClientLimits(ctx, userID, groupID, expiresAt, request.GetTransferQuota())
+	if err != nil {
+		log.Error().Err(err).Msg("Setting client limits")
+		return &protobuf.ClientsAffectedResponse{}, err
+	}
+
+	if err = w.forEachRemoteNode(ctx, func(ctx context.Context, c protobuf.WireguardClient) error {
+		resp, err := c.SetClientLimits(ctx, request)
+		if err != nil {
+			return err
+		}
+		affected += resp.GetClientsAffected()
+		return nil
+	}); err != nil {
+		log.Error().Err(err).Msg("Setting client limits on other nodes")
+		return &protobuf.ClientsAffectedResponse{}, err
+	}
+
+	log.Debug().Str("userID", request.GetUserID()).Str("groupID", request.GetGroupID()).Msg("Client limits are set")
+	return &protobuf.ClientsAffectedResponse{
+		ClientsAffected: affected,
+	}, nil
+}
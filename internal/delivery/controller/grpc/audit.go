@@ -0,0 +1,134 @@
+package grpc
+
+import (
+	"context"
+	"github.com/cybericebox/wireguard/internal/model"
+	"github.com/cybericebox/wireguard/pkg/appError"
+	"github.com/cybericebox/wireguard/pkg/controller/grpc/protobuf"
+	"github.com/gofrs/uuid"
+	"github.com/rs/zerolog/log"
+	"google.golang.org/grpc/metadata"
+	"time"
+)
+
+type (
+	IAuditService interface {
+		RecordAuditEntry(ctx context.Context, entry *model.AuditEntry) error
+		GetAuditLog(ctx context.Context, filter model.AuditFilter) ([]*model.AuditEntry, error)
+	}
+
+	// clientsTarget is the request which targets the clients by user and group
+	clientsTarget interface {
+		GetUserID() string
+		GetGroupID() string
+	}
+)
+
+func (w *Wireguard) GetAuditLog(ctx context.Context, request *protobuf.AuditLogRequest) (*protobuf.AuditLogResponse, error) {
+	log.Debug().Str("subject", request.GetSubject()).Str("action", request.GetAction()).Msg("Getting audit log")
+
+	filter := model.AuditFilter{
+		Subject: request.GetSubject(),
+		Action:  request.GetAction(),
+		Limit:   request.GetLimit(),
+	}
+
+	if request.GetUserID() != "" {
+		userID, err := uuid.FromString(request.GetUserID())
+		if err != nil {
+			log.Error().Err(err).Msg("Parsing user ID")
+			return &protobuf.AuditLogResponse{}, appError.ErrClientInvalidUserID.Err()
+		}
+		filter.UserID = userID
+	}
+
+	if request.GetGroupID() != "" {
+		groupID, err := uuid.FromString(request.GetGroupID())
+		if err != nil {
+			log.Error().Err(err).Msg("Parsing group ID")
+			return &protobuf.AuditLogResponse{}, appError.ErrClientInvalidGroupID.Err()
+		}
+		filter.GroupID = groupID
+	}
+
+	if request.GetSince() > 0 {
+		filter.Since = time.Unix(request.GetSince(), 0)
+	}
+
+	if request.GetUntil() > 0 {
+		filter.Until = time.Unix(request.GetUntil(), 0)
+	}
+
+	entries, err := w.service.GetAuditLog(ctx, filter)
+	if err != nil {
+		log.Error().Err(err).Msg("Getting audit log")
+		return &protobuf.AuditLogResponse{}, err
+	}
+
+	pEntries := make([]*protobuf.AuditLogEntry, 0, len(entries))
+	for _, e := range entries {
+		pEntry := &protobuf.AuditLogEntry{
+			ID:         e.ID,
+			Subject:    e.Subject,
+			Action:     e.Action,
+			Parameters: e.Parameters,
+			Outcome:    e.Outcome,
+			Error:      e.Error,
+			Affected:   e.Affected,
+			NodeID:     e.NodeID,
+			CreatedAt:  e.CreatedAt.Unix(),
+		}
+		if e.UserID != uuid.Nil {
+			pEntry.UserID = e.UserID.String()
+		}
+		if e.GroupID != uuid.Nil {
+			pEntry.GroupID = e.GroupID.String()
+		}
+		pEntries = append(pEntries, pEntry)
+	}
+
+	log.Debug().Int("entries", len(pEntries)).Msg("Returning audit log")
+	return &protobuf.AuditLogResponse{
+		Entries: pEntries,
+	}, nil
+}
+
+// audit records the action in the audit log. The requests forwarded by another node are recorded by the node which received them,
+// the requests claiming to be forwarded without the node signature are recorded with the claimed node.
+// Failures to record are logged and do not fail the action, it has already been done.
+func (w *Wireguard) audit(ctx context.Context, action string, target clientsTarget, parameters map[string]string, affected int64, actionErr error) {
+	if isForwarded(ctx) {
+		return
+	}
+
+	if parameters == nil {
+		parameters = make(map[string]string)
+	}
+	parameters["userID"] = target.GetUserID()
+	parameters["groupID"] = target.GetGroupID()
+
+	if md, ok := metadata.FromIncomingContext(ctx); ok && len(md.Get(forwardedByKey)) > 0 {
+		parameters["forwarded"] = "unverified"
+		parameters["forwardedBy"] = firstOrEmpty(md.Get(forwardedByKey))
+	}
+
+	entry := &model.AuditEntry{
+		Subject:    subjectFromContext(ctx),
+		Action:     action,
+		UserID:     uuid.FromStringOrNil(target.GetUserID()),
+		GroupID:    uuid.FromStringOrNil(target.GetGroupID()),
+		Parameters: parameters,
+		Outcome:    model.AuditOutcomeSuccess,
+		Affected:   affected,
+	}
+
+	if actionErr != nil {
+		entry.Outcome = model.AuditOutcomeFailure
+		entry.Error = actionErr.Error()
+	}
+
+	// the entry has to be recorded even if the caller is gone
+	if err := w.service.RecordAuditEntry(context.WithoutCancel(ctx), entry); err != nil {
+		log.Error().Err(err).Str("subject", entry.Subject).Str("action", action).Msg("Failed to record audit entry")
+	}
+}
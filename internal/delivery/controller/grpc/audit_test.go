@@ -0,0 +1,80 @@
+package grpc_test
+
+import (
+	"context"
+	"github.com/cybericebox/wireguard/pkg/controller/grpc/client"
+	"github.com/cybericebox/wireguard/pkg/controller/grpc/protobuf"
+	"github.com/cybericebox/wireguard/pkg/wgtest"
+	"github.com/gofrs/uuid"
+	"google.golang.org/grpc/metadata"
+	"os"
+	"testing"
+)
+
+// TestMain runs the tests from the module root as the application runs,
+// the errors trim the working directory from the paths of the files they are created in
+func TestMain(m *testing.M) {
+	if err := os.Chdir("../../../.."); err != nil {
+		panic(err)
+	}
+	os.Exit(m.Run())
+}
+
+func TestAuditRecordsUnverifiedForwardedRequests(t *testing.T) {
+	h := wgtest.NewHarness(t)
+	c := h.Client(t, []string{client.RoleAdmin}, "")
+
+	userID, groupID := uuid.Must(uuid.NewV4()).String(), uuid.Must(uuid.NewV4()).String()
+	ctx := context.Background()
+	if _, err := c.GetClientConfig(ctx, &protobuf.ClientConfigRequest{UserID: userID, GroupID: groupID, DestCIDR: "10.0.0.0/24"}); err != nil {
+		t.Fatalf("get client config: %v", err)
+	}
+
+	// the caller claims the request was forwarded by another node, it has no node signature
+	forwarded := metadata.AppendToOutgoingContext(ctx, "x-forwarded-by", "other-node")
+	for name, call := range map[string]func() error{
+		"BanClients": func() error {
+			_, err := c.BanClients(forwarded, &protobuf.ClientsRequest{UserID: userID, GroupID: groupID})
+			return err
+		},
+		"UnBanClients": func() error {
+			_, err := c.UnBanClients(forwarded, &protobuf.ClientsRequest{UserID: userID, GroupID: groupID})
+			return err
+		},
+		"GetClientConfig": func() error {
+			_, err := c.GetClientConfig(forwarded, &protobuf.ClientConfigRequest{UserID: userID, GroupID: groupID, DestCIDR: "10.0.0.0/24"})
+			return err
+		},
+		"DeleteClients": func() error {
+			_, err := c.DeleteClients(forwarded, &protobuf.ClientsRequest{UserID: userID, GroupID: groupID})
+			return err
+		},
+	} {
+		if err := call(); err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+	}
+
+	resp, err := c.GetAuditLog(ctx, &protobuf.AuditLogRequest{UserID: userID, GroupID: groupID})
+	if err != nil {
+		t.Fatalf("get audit log: %v", err)
+	}
+
+	// the first config request and the four claimed forwarded ones
+	if len(resp.GetEntries()) != 5 {
+		t.Fatalf("expected 5 audit entries, got %d", len(resp.GetEntries()))
+	}
+
+	flagged := 0
+	for _, e := range resp.GetEntries() {
+		if e.GetParameters()["forwarded"] == "unverified" {
+			if e.GetParameters()["forwardedBy"] != "other-node" {
+				t.Errorf("%s: unexpected forwarding node %q", e.GetAction(), e.GetParameters()["forwardedBy"])
+			}
+			flagged++
+		}
+	}
+	if flagged != 4 {
+		t.Fatalf("expected 4 entries flagged as forwarded, got %d", flagged)
+	}
+}
@@ -2,65 +2,275 @@ package grpc
 
 import (
 	"context"
+	"github.com/cybericebox/wireguard/internal/config"
 	"github.com/cybericebox/wireguard/pkg/appError"
+	"time"
 
 	"github.com/cybericebox/wireguard/pkg/controller/grpc/client"
 	"github.com/golang-jwt/jwt"
 	"google.golang.org/grpc/metadata"
 )
 
+const (
+	// unknownSubject is recorded for the tokens without subject
+	unknownSubject = "unknown"
+	// tokenLeeway is the allowed clock skew between the token issuer and the server
+	tokenLeeway = 30 * time.Second
+)
+
+// validMethods are the signing methods of the accepted tokens
+var validMethods = []string{
+	jwt.SigningMethodHS256.Alg(), jwt.SigningMethodHS384.Alg(), jwt.SigningMethodHS512.Alg(),
+	jwt.SigningMethodRS256.Alg(), jwt.SigningMethodRS384.Alg(), jwt.SigningMethodRS512.Alg(),
+	jwt.SigningMethodPS256.Alg(), jwt.SigningMethodPS384.Alg(), jwt.SigningMethodPS512.Alg(),
+	jwt.SigningMethodES256.Alg(), jwt.SigningMethodES384.Alg(), jwt.SigningMethodES512.Alg(),
+	jwt.SigningMethodEdDSA.Alg(),
+}
+
 type Authenticator interface {
-	AuthenticateContext(context.Context) error
+	// AuthenticateContext authenticates the request, req is the message of the unary request and nil for the streams
+	AuthenticateContext(ctx context.Context, req interface{}) (context.Context, error)
 }
 
+type (
+	// identity is the caller the request was authenticated as
+	identity struct {
+		subject string
+		roles   []string
+		// groupID scopes the caller to the group if it is set
+		groupID string
+		// forwardedBy is the node which authenticated the caller and forwarded the request
+		forwardedBy string
+	}
+
+	// identityKey is the context key of the authenticated identity
+	identityKey struct{}
+)
+
 type auth struct {
-	signKey string // Sign Key
-	authKey string // Auth Key
+	mode           string
+	certIdentities *certIdentities   // Identities of client certificates
+	signKey        string            // Sign Key of tokens without key ID
+	signKeys       map[string]string // Sign Keys by key ID
+	authKey        string            // Auth Key
+	publicKeys     *publicKeys       // Public Keys of asymmetric signatures
+	issuer         string
+	audience       string
+	maxTokenTTL    time.Duration
+	nodeKey        string      // Node Key of forwarded requests
+	nonces         *nonceCache // Nonces of accepted node signatures
 }
 
-func NewAuthenticator(SignKey, AuthKey string) Authenticator {
-	return &auth{signKey: SignKey, authKey: AuthKey}
+func NewAuthenticator(conf *config.AuthConfig) (Authenticator, error) {
+	keys, err := newPublicKeys(conf.PublicKeyFile, conf.JWKSFile, conf.KeysReloadInterval)
+	if err != nil {
+		return nil, appError.ErrGRPC.WithError(err).WithMessage("Failed to create authenticator").Err()
+	}
+
+	identities, err := newCertIdentities(conf.CertIdentitiesFile)
+	if err != nil {
+		return nil, appError.ErrGRPC.WithError(err).WithMessage("Failed to create authenticator").Err()
+	}
+
+	return &auth{
+		mode:           conf.Mode,
+		certIdentities: identities,
+		signKey:        conf.SignKey,
+		signKeys:       conf.SignKeys,
+		authKey:        conf.AuthKey,
+		publicKeys:     keys,
+		issuer:         conf.Issuer,
+		audience:       conf.Audience,
+		maxTokenTTL:    conf.MaxTokenTTL,
+		nodeKey:        conf.NodeKey,
+		nonces:         newNonceCache(),
+	}, nil
+}
+
+func (a *auth) AuthenticateContext(ctx context.Context, req interface{}) (context.Context, error) {
+	// the forwarded requests are authenticated by the node signature in every mode
+	if md, ok := metadata.FromIncomingContext(ctx); ok && len(md.Get(nodeSignatureKey)) > 0 {
+		return a.authenticateNode(ctx, md, req)
+	}
+
+	switch a.mode {
+	case config.AuthModeMTLS:
+		return a.authenticatePeer(ctx)
+	case config.AuthModeAny:
+		if md, ok := metadata.FromIncomingContext(ctx); !ok || len(md["token"]) == 0 || md["token"][0] == "" {
+			return a.authenticatePeer(ctx)
+		}
+	}
+
+	return a.authenticateToken(ctx)
 }
 
-func (a *auth) AuthenticateContext(ctx context.Context) error {
+// authenticateToken authenticates the request by the token of its metadata
+func (a *auth) authenticateToken(ctx context.Context) (context.Context, error) {
 	md, ok := metadata.FromIncomingContext(ctx)
 	if !ok {
-		return appError.ErrGRPCMissingKey.Err()
+		return ctx, appError.ErrGRPCMissingKey.Err()
 	}
 
 	if len(md["token"]) == 0 {
-		return appError.ErrGRPCMissingKey.Err()
+		return ctx, appError.ErrGRPCMissingKey.Err()
 	}
 
 	token := md["token"][0]
 	if token == "" {
-		return appError.ErrGRPCMissingKey.Err()
+		return ctx, appError.ErrGRPCMissingKey.Err()
 	}
 
-	jwtToken, err := jwt.Parse(token, func(token *jwt.Token) (interface{}, error) {
-		if _, ok = token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return ctx, appError.ErrGRPCInvalidTokenFormat.Err()
-		}
+	// claims are validated below to allow the clock skew
+	parser := &jwt.Parser{
+		ValidMethods:         validMethods,
+		SkipClaimsValidation: true,
+	}
+
+	unverified, _, err := parser.ParseUnverified(token, jwt.MapClaims{})
+	if err != nil {
+		return ctx, appError.ErrGRPCInvalidTokenFormat.WithError(err).Err()
+	}
 
-		return []byte(a.signKey), nil
-	})
+	keys, err := a.verificationKeys(unverified)
 	if err != nil {
-		return err
+		return ctx, err
+	}
+
+	// several keys can be active for the same key ID while they are rotated
+	var jwtToken *jwt.Token
+	for _, key := range keys {
+		if jwtToken, err = parser.Parse(token, func(*jwt.Token) (interface{}, error) { return key, nil }); err == nil {
+			break
+		}
+	}
+	if err != nil {
+		return ctx, err
 	}
 
 	claims, ok := jwtToken.Claims.(jwt.MapClaims)
 	if !ok || !jwtToken.Valid {
-		return appError.ErrGRPCInvalidTokenFormat.Err()
+		return ctx, appError.ErrGRPCInvalidTokenFormat.Err()
+	}
+
+	if err = a.validateClaims(claims); err != nil {
+		return ctx, err
 	}
 
 	authKey, ok := claims[client.AuthKey].(string)
 	if !ok {
-		return appError.ErrGRPCInvalidTokenFormat.Err()
+		return ctx, appError.ErrGRPCInvalidTokenFormat.Err()
 	}
 
 	if authKey != a.authKey {
-		return appError.ErrGRPCInvalidKey.Err()
+		return ctx, appError.ErrGRPCInvalidKey.Err()
 	}
 
+	subject, _ := claims[client.Subject].(string)
+	groupID, _ := claims[client.GroupID].(string)
+
+	return context.WithValue(ctx, identityKey{}, &identity{
+		subject: subject,
+		roles:   stringsClaim(claims[client.Roles]),
+		groupID: groupID,
+	}), nil
+}
+
+// stringsClaim returns the claim which is either a single string or an array of strings
+func stringsClaim(claim interface{}) []string {
+	switch v := claim.(type) {
+	case string:
+		return []string{v}
+	case []interface{}:
+		result := make([]string, 0, len(v))
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				result = append(result, s)
+			}
+		}
+		return result
+	}
 	return nil
 }
+
+// verificationKeys returns the keys which can verify the token by its signing method and key ID.
+// Tokens without key ID are verified with the sign key or the public keys without key ID.
+func (a *auth) verificationKeys(token *jwt.Token) ([]interface{}, error) {
+	kid, _ := token.Header[client.KeyID].(string)
+
+	if _, ok := token.Method.(*jwt.SigningMethodHMAC); ok {
+		key := a.signKey
+		if kid != "" {
+			key = a.signKeys[kid]
+		}
+		if key == "" {
+			return nil, appError.ErrGRPCUnknownSigningKey.WithContext("kid", kid).Err()
+		}
+		return []interface{}{[]byte(key)}, nil
+	}
+
+	if !a.publicKeys.enabled() {
+		return nil, appError.ErrGRPCInvalidTokenFormat.Err()
+	}
+
+	var keys []interface{}
+	for _, key := range a.publicKeys.get(kid) {
+		if keyMatchesMethod(key, token.Method) {
+			keys = append(keys, key)
+		}
+	}
+
+	if len(keys) == 0 {
+		return nil, appError.ErrGRPCUnknownSigningKey.WithContext("kid", kid).Err()
+	}
+
+	return keys, nil
+}
+
+// validateClaims requires the token to expire within the maximum lifetime and checks its issuer and audience if they are configured
+func (a *auth) validateClaims(claims jwt.MapClaims) error {
+	now := time.Now()
+
+	if _, ok := claims["exp"]; !ok {
+		return appError.ErrGRPCInvalidTokenClaims.WithMessage("Token has no expiration").Err()
+	}
+
+	if !claims.VerifyExpiresAt(now.Add(-tokenLeeway).Unix(), true) {
+		return appError.ErrGRPCTokenExpired.Err()
+	}
+
+	if claims.VerifyExpiresAt(now.Add(a.maxTokenTTL+tokenLeeway).Unix()+1, true) {
+		return appError.ErrGRPCInvalidTokenClaims.WithMessage("Token lifetime exceeds maximum").Err()
+	}
+
+	if !claims.VerifyNotBefore(now.Add(tokenLeeway).Unix(), false) {
+		return appError.ErrGRPCInvalidTokenClaims.WithMessage("Token is not valid yet").Err()
+	}
+
+	if !claims.VerifyIssuedAt(now.Add(tokenLeeway).Unix(), false) {
+		return appError.ErrGRPCInvalidTokenClaims.WithMessage("Token is issued in the future").Err()
+	}
+
+	if a.issuer != "" && !claims.VerifyIssuer(a.issuer, true) {
+		return appError.ErrGRPCInvalidTokenClaims.WithMessage("Invalid token issuer").Err()
+	}
+
+	if a.audience != "" && !claims.VerifyAudience(a.audience, true) {
+		return appError.ErrGRPCInvalidTokenClaims.WithMessage("Invalid token audience").Err()
+	}
+
+	return nil
+}
+
+// subjectFromContext returns the subject of the token the request was authenticated with
+func subjectFromContext(ctx context.Context) string {
+	if id := identityFromContext(ctx); id != nil && id.subject != "" {
+		return id.subject
+	}
+	return unknownSubject
+}
+
+func identityFromContext(ctx context.Context) *identity {
+	id, _ := ctx.Value(identityKey{}).(*identity)
+	return id
+}
@@ -0,0 +1,142 @@
+package grpc
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"github.com/cybericebox/wireguard/internal/config"
+	"github.com/cybericebox/wireguard/pkg/appError"
+	"github.com/rs/zerolog/log"
+	"os"
+	"sync"
+	"time"
+)
+
+// certReloader keeps the certificate and the CA of the server up to date with the files.
+// The files are reloaded on the next handshake after they change, so short-lived certificates are rotated without a restart.
+type certReloader struct {
+	m        sync.Mutex
+	config   *config.TLSConfig
+	modTimes [3]time.Time
+
+	certificate *tls.Certificate
+	certPool    *x509.CertPool
+}
+
+func newCertReloader(conf *config.TLSConfig) (*certReloader, error) {
+	r := &certReloader{config: conf}
+
+	modTimes, err := r.fileModTimes()
+	if err != nil {
+		return nil, appError.ErrGRPC.WithError(err).WithMessage("Failed to stat certificates").Err()
+	}
+
+	if err = r.load(modTimes); err != nil {
+		return nil, appError.ErrGRPC.WithError(err).WithMessage("Failed to load certificates").Err()
+	}
+
+	return r, nil
+}
+
+// current returns the certificate and the CA pool, reloading them if the files changed.
+// The previous ones are kept if the new files can not be loaded, e.g. while they are being written.
+func (r *certReloader) current() (*tls.Certificate, *x509.CertPool) {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	modTimes, err := r.fileModTimes()
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to stat certificates, keeping previous certificates")
+		return r.certificate, r.certPool
+	}
+
+	if modTimes != r.modTimes {
+		log.Info().Msg("Certificates changed, reloading")
+		if err = r.load(modTimes); err != nil {
+			log.Error().Err(err).Msg("Failed to reload certificates, keeping previous certificates")
+		}
+	}
+
+	return r.certificate, r.certPool
+}
+
+func (r *certReloader) fileModTimes() ([3]time.Time, error) {
+	var modTimes [3]time.Time
+	for i, file := range []string{r.config.CertFile, r.config.CertKey, r.config.CAFile} {
+		info, err := os.Stat(file)
+		if err != nil {
+			return modTimes, err
+		}
+		modTimes[i] = info.ModTime()
+	}
+	return modTimes, nil
+}
+
+func (r *certReloader) load(modTimes [3]time.Time) error {
+	certificate, err := tls.LoadX509KeyPair(r.config.CertFile, r.config.CertKey)
+	if err != nil {
+		return appError.ErrGRPC.WithError(err).WithMessage("Failed to load certificates").Err()
+	}
+
+	// Create a certificate pool from the certificate authority
+	certPool := x509.NewCertPool()
+	ca, err := os.ReadFile(r.config.CAFile)
+	if err != nil {
+		return appError.ErrGRPC.WithError(err).WithMessage("Failed to read CA file").Err()
+	}
+	// CA file for let's encrypt is located under domain conf as `chain.pem`
+	// pass chain.pem location
+	// Append the client certificates from the CA
+	if ok := certPool.AppendCertsFromPEM(ca); !ok {
+		return appError.ErrGRPC.WithMessage("Failed to append client certificates").Err()
+	}
+
+	r.certificate, r.certPool, r.modTimes = &certificate, certPool, modTimes
+	return nil
+}
+
+// serverConfig returns the TLS config of the server which requires the client certificates signed by the current CA
+func (r *certReloader) serverConfig() *tls.Config {
+	return &tls.Config{
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			certificate, certPool := r.current()
+			return &tls.Config{
+				ClientAuth:   tls.RequireAndVerifyClientCert,
+				Certificates: []tls.Certificate{*certificate},
+				ClientCAs:    certPool,
+			}, nil
+		},
+	}
+}
+
+// clientConfig returns the TLS config of the connections to the other nodes.
+// The server certificate is verified against the current CA on every handshake, so the connections survive the CA rotation.
+func (r *certReloader) clientConfig() *tls.Config {
+	return &tls.Config{
+		GetClientCertificate: func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+			certificate, _ := r.current()
+			return certificate, nil
+		},
+		// the chain is verified by VerifyConnection with the current CA
+		InsecureSkipVerify: true,
+		VerifyConnection: func(state tls.ConnectionState) error {
+			if len(state.PeerCertificates) == 0 {
+				return appError.ErrGRPC.WithMessage("Node presented no certificate").Err()
+			}
+
+			_, certPool := r.current()
+			opts := x509.VerifyOptions{
+				Roots:         certPool,
+				DNSName:       state.ServerName,
+				Intermediates: x509.NewCertPool(),
+			}
+			for _, cert := range state.PeerCertificates[1:] {
+				opts.Intermediates.AddCert(cert)
+			}
+
+			if _, err := state.PeerCertificates[0].Verify(opts); err != nil {
+				return appError.ErrGRPC.WithError(err).WithMessage("Failed to verify node certificate").Err()
+			}
+			return nil
+		},
+	}
+}
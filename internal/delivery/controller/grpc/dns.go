@@ -0,0 +1,145 @@
+package grpc
+
+import (
+	"context"
+	"github.com/cybericebox/wireguard/internal/model"
+	"github.com/cybericebox/wireguard/pkg/appError"
+	"github.com/cybericebox/wireguard/pkg/controller/grpc/protobuf"
+	"github.com/gofrs/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+type (
+	IDNSService interface {
+		CreateDNSRecord(ctx context.Context, groupID uuid.UUID, name, address string) (*model.DNSRecord, error)
+		ListDNSRecords(ctx context.Context, groupID uuid.UUID) ([]*model.DNSRecord, error)
+		DeleteDNSRecord(ctx context.Context, groupID, id uuid.UUID) error
+	}
+
+	// groupScope is the audit target of the requests which target only the group
+	groupScope string
+)
+
+func (g groupScope) GetUserID() string {
+	return ""
+}
+
+func (g groupScope) GetGroupID() string {
+	return string(g)
+}
+
+// The records are stored in the database and every node reloads them, so the requests are not forwarded to other nodes
+
+func (w *Wireguard) CreateDNSRecord(ctx context.Context, request *protobuf.CreateDNSRecordRequest) (*protobuf.DNSRecordResponse, error) {
+	log.Debug().Str("groupID", request.GetGroupID()).Str("name", request.GetName()).Msg("Creating DNS record")
+
+	groupID, err := parseDNSGroupID(request.GetGroupID())
+	if err != nil {
+		log.Error().Err(err).Msg("Parsing group ID")
+		return &protobuf.DNSRecordResponse{}, err
+	}
+
+	record, err := w.service.CreateDNSRecord(ctx, groupID, request.GetName(), request.GetAddress())
+	w.audit(ctx, model.AuditActionCreateDNSRecord, groupScope(request.GetGroupID()), map[string]string{
+		"name":    request.GetName(),
+		"address": request.GetAddress(),
+	}, affectedRecords(err), err)
+	if err != nil {
+		log.Error().Err(err).Msg("Creating DNS record")
+		return &protobuf.DNSRecordResponse{}, err
+	}
+
+	log.Debug().Str("groupID", request.GetGroupID()).Str("id", record.ID.String()).Msg("Returning created DNS record")
+	return &protobuf.DNSRecordResponse{
+		Record: protobufDNSRecord(record),
+	}, nil
+}
+
+func (w *Wireguard) ListDNSRecords(ctx context.Context, request *protobuf.DNSRecordsRequest) (*protobuf.DNSRecordsResponse, error) {
+	log.Debug().Str("groupID", request.GetGroupID()).Msg("Listing DNS records")
+
+	var groupID uuid.UUID
+	if request.GetGroupID() != "" {
+		var err error
+		if groupID, err = uuid.FromString(request.GetGroupID()); err != nil {
+			log.Error().Err(err).Msg("Parsing group ID")
+			return &protobuf.DNSRecordsResponse{}, appError.ErrClientInvalidGroupID.WithError(err).Err()
+		}
+	}
+
+	records, err := w.service.ListDNSRecords(ctx, groupID)
+	if err != nil {
+		log.Error().Err(err).Msg("Listing DNS records")
+		return &protobuf.DNSRecordsResponse{}, err
+	}
+
+	pRecords := make([]*protobuf.DNSRecord, 0, len(records))
+	for _, record := range records {
+		pRecords = append(pRecords, protobufDNSRecord(record))
+	}
+
+	log.Debug().Int("records", len(pRecords)).Msg("Returning DNS records")
+	return &protobuf.DNSRecordsResponse{
+		Records: pRecords,
+	}, nil
+}
+
+func (w *Wireguard) DeleteDNSRecord(ctx context.Context, request *protobuf.DNSRecordRequest) (*protobuf.EmptyResponse, error) {
+	log.Debug().Str("groupID", request.GetGroupID()).Str("id", request.GetID()).Msg("Deleting DNS record")
+
+	groupID, err := parseDNSGroupID(request.GetGroupID())
+	if err != nil {
+		log.Error().Err(err).Msg("Parsing group ID")
+		return &protobuf.EmptyResponse{}, err
+	}
+
+	id, err := uuid.FromString(request.GetID())
+	if err != nil {
+		log.Error().Err(err).Msg("Parsing DNS record ID")
+		return &protobuf.EmptyResponse{}, appError.ErrDNSInvalidRecordID.WithError(err).Err()
+	}
+
+	err = w.service.DeleteDNSRecord(ctx, groupID, id)
+	w.audit(ctx, model.AuditActionDeleteDNSRecord, groupScope(request.GetGroupID()), map[string]string{
+		"id": request.GetID(),
+	}, affectedRecords(err), err)
+	if err != nil {
+		log.Error().Err(err).Msg("Deleting DNS record")
+		return &protobuf.EmptyResponse{}, err
+	}
+
+	return &protobuf.EmptyResponse{}, nil
+}
+
+// parseDNSGroupID parses the group ID of the record, the records are always scoped to a group
+func parseDNSGroupID(groupID string) (uuid.UUID, error) {
+	if groupID == "" {
+		return uuid.Nil, appError.ErrDNSMissingGroupID.Err()
+	}
+
+	id, err := uuid.FromString(groupID)
+	if err != nil {
+		return uuid.Nil, appError.ErrClientInvalidGroupID.WithError(err).Err()
+	}
+
+	return id, nil
+}
+
+// affectedRecords is the audited number of the records changed by the single record request
+func affectedRecords(err error) int64 {
+	if err != nil {
+		return 0
+	}
+	return 1
+}
+
+func protobufDNSRecord(record *model.DNSRecord) *protobuf.DNSRecord {
+	return &protobuf.DNSRecord{
+		ID:             record.ID.String(),
+		GroupID:        record.GroupID.String(),
+		Name:           record.Name,
+		Address:        record.Address,
+		CreatedAt:      unixOrZero(record.CreatedAt),
+		LaboratoryCIDR: record.LaboratoryCIDR,
+	}
+}
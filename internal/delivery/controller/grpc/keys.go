@@ -0,0 +1,258 @@
+package grpc
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"github.com/cybericebox/wireguard/pkg/appError"
+	"github.com/golang-jwt/jwt"
+	"github.com/rs/zerolog/log"
+	"math/big"
+	"os"
+	"sync"
+	"time"
+)
+
+type (
+	// publicKeys are the keys verifying the asymmetric token signatures.
+	// They are loaded from the PEM file and the JWKS document and reloaded when they are older than the reload interval.
+	publicKeys struct {
+		m              sync.Mutex
+		pemFile        string
+		jwksFile       string
+		reloadInterval time.Duration
+		loadedAt       time.Time
+
+		// byKID are the JWKS keys by key ID
+		byKID map[string][]crypto.PublicKey
+		// withoutKID are the PEM keys and the JWKS keys without key ID
+		withoutKID []crypto.PublicKey
+	}
+
+	jwks struct {
+		Keys []jwk `json:"keys"`
+	}
+
+	jwk struct {
+		Kty string `json:"kty"`
+		Kid string `json:"kid"`
+		Use string `json:"use"`
+		Crv string `json:"crv"`
+		N   string `json:"n"`
+		E   string `json:"e"`
+		X   string `json:"x"`
+		Y   string `json:"y"`
+	}
+)
+
+func newPublicKeys(pemFile, jwksFile string, reloadInterval time.Duration) (*publicKeys, error) {
+	k := &publicKeys{
+		pemFile:        pemFile,
+		jwksFile:       jwksFile,
+		reloadInterval: reloadInterval,
+	}
+
+	if err := k.load(); err != nil {
+		return nil, appError.ErrGRPC.WithError(err).WithMessage("Failed to load public keys").Err()
+	}
+
+	return k, nil
+}
+
+// enabled reports whether any source of the public keys is configured
+func (k *publicKeys) enabled() bool {
+	return k.pemFile != "" || k.jwksFile != ""
+}
+
+// get returns the keys of the key ID, the keys without key ID are returned for the empty key ID
+func (k *publicKeys) get(kid string) []crypto.PublicKey {
+	k.m.Lock()
+	defer k.m.Unlock()
+
+	if k.reloadInterval > 0 && time.Since(k.loadedAt) >= k.reloadInterval {
+		// keep the previous keys if the files are broken
+		if err := k.loadLocked(); err != nil {
+			log.Error().Err(err).Msg("Failed to reload public keys, keeping previous keys")
+			k.loadedAt = time.Now()
+		}
+	}
+
+	if kid == "" {
+		return k.withoutKID
+	}
+	return k.byKID[kid]
+}
+
+func (k *publicKeys) load() error {
+	k.m.Lock()
+	defer k.m.Unlock()
+
+	return k.loadLocked()
+}
+
+func (k *publicKeys) loadLocked() error {
+	byKID := make(map[string][]crypto.PublicKey)
+	var withoutKID []crypto.PublicKey
+
+	if k.pemFile != "" {
+		data, err := os.ReadFile(k.pemFile)
+		if err != nil {
+			return appError.ErrGRPC.WithError(err).WithMessage("Failed to read public key file").Err()
+		}
+
+		keys, err := parsePEMPublicKeys(data)
+		if err != nil {
+			return appError.ErrGRPC.WithError(err).WithMessage("Failed to parse public key file").Err()
+		}
+		withoutKID = append(withoutKID, keys...)
+	}
+
+	if k.jwksFile != "" {
+		data, err := os.ReadFile(k.jwksFile)
+		if err != nil {
+			return appError.ErrGRPC.WithError(err).WithMessage("Failed to read JWKS file").Err()
+		}
+
+		var set jwks
+		if err = json.Unmarshal(data, &set); err != nil {
+			return appError.ErrGRPC.WithError(err).WithMessage("Failed to parse JWKS file").Err()
+		}
+
+		for _, key := range set.Keys {
+			if key.Use != "" && key.Use != "sig" {
+				continue
+			}
+
+			publicKey, err := key.publicKey()
+			if err != nil {
+				return appError.ErrGRPC.WithError(err).WithMessage("Failed to parse JWKS key").WithContext("kid", key.Kid).Err()
+			}
+
+			if key.Kid == "" {
+				withoutKID = append(withoutKID, publicKey)
+				continue
+			}
+			byKID[key.Kid] = append(byKID[key.Kid], publicKey)
+		}
+	}
+
+	k.byKID, k.withoutKID, k.loadedAt = byKID, withoutKID, time.Now()
+	log.Debug().Int("keysWithKID", len(byKID)).Int("keysWithoutKID", len(withoutKID)).Msg("Public keys loaded")
+
+	return nil
+}
+
+// parsePEMPublicKeys parses all public keys and certificates of the PEM data
+func parsePEMPublicKeys(data []byte) ([]crypto.PublicKey, error) {
+	var keys []crypto.PublicKey
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			break
+		}
+
+		switch block.Type {
+		case "PUBLIC KEY":
+			key, err := x509.ParsePKIXPublicKey(block.Bytes)
+			if err != nil {
+				return nil, err
+			}
+			keys = append(keys, key)
+		case "RSA PUBLIC KEY":
+			key, err := x509.ParsePKCS1PublicKey(block.Bytes)
+			if err != nil {
+				return nil, err
+			}
+			keys = append(keys, key)
+		case "CERTIFICATE":
+			cert, err := x509.ParseCertificate(block.Bytes)
+			if err != nil {
+				return nil, err
+			}
+			keys = append(keys, cert.PublicKey)
+		}
+	}
+
+	if len(keys) == 0 {
+		return nil, appError.ErrGRPC.WithMessage("No public keys found").Err()
+	}
+
+	return keys, nil
+}
+
+func (k jwk) publicKey() (crypto.PublicKey, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil {
+			return nil, err
+		}
+		return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, appError.ErrGRPC.WithMessage("Unsupported curve").WithContext("crv", k.Crv).Err()
+		}
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := base64.RawURLEncoding.DecodeString(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		key := &ecdsa.PublicKey{Curve: curve, X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}
+		if !curve.IsOnCurve(key.X, key.Y) {
+			return nil, appError.ErrGRPC.WithMessage("Point is not on curve").WithContext("crv", k.Crv).Err()
+		}
+		return key, nil
+	case "OKP":
+		if k.Crv != "Ed25519" {
+			return nil, appError.ErrGRPC.WithMessage("Unsupported curve").WithContext("crv", k.Crv).Err()
+		}
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil {
+			return nil, err
+		}
+		if len(x) != ed25519.PublicKeySize {
+			return nil, appError.ErrGRPC.WithMessage("Invalid Ed25519 key size").Err()
+		}
+		return ed25519.PublicKey(x), nil
+	}
+
+	return nil, appError.ErrGRPC.WithMessage("Unsupported key type").WithContext("kty", k.Kty).Err()
+}
+
+// keyMatchesMethod reports whether the key can verify the signatures of the signing method
+func keyMatchesMethod(key crypto.PublicKey, method jwt.SigningMethod) bool {
+	switch method.(type) {
+	case *jwt.SigningMethodRSA, *jwt.SigningMethodRSAPSS:
+		_, ok := key.(*rsa.PublicKey)
+		return ok
+	case *jwt.SigningMethodECDSA:
+		_, ok := key.(*ecdsa.PublicKey)
+		return ok
+	case *jwt.SigningMethodEd25519:
+		_, ok := key.(ed25519.PublicKey)
+		return ok
+	}
+	return false
+}
@@ -4,19 +4,31 @@ import (
 	"context"
 	"errors"
 	"github.com/cybericebox/wireguard/internal/model"
+	"github.com/cybericebox/wireguard/pkg/appError"
 	"github.com/cybericebox/wireguard/pkg/controller/grpc/protobuf"
-	"github.com/gofrs/uuid"
 	"github.com/rs/zerolog/log"
 	"io"
+	"strings"
 )
 
 type (
 	IMonitoringService interface {
-		GetClients(ctx context.Context, userID, groupID uuid.UUID) ([]*model.Client, error)
+		CheckHealth(ctx context.Context) *model.Health
 	}
 )
 
-func (w *Wireguard) Ping(_ context.Context, _ *protobuf.EmptyRequest) (*protobuf.EmptyResponse, error) {
+func (w *Wireguard) Ping(ctx context.Context, _ *protobuf.EmptyRequest) (*protobuf.EmptyResponse, error) {
+	health := w.service.CheckHealth(ctx)
+	if !health.Ready {
+		failed := make([]string, 0, len(health.Checks))
+		for _, check := range health.Checks {
+			if !check.Healthy {
+				failed = append(failed, check.Name)
+			}
+		}
+		return &protobuf.EmptyResponse{}, appError.ErrPlatformNotReady.WithContext("failedChecks", strings.Join(failed, ",")).Err()
+	}
+
 	return &protobuf.EmptyResponse{}, nil
 }
 
@@ -40,26 +52,35 @@ func (w *Wireguard) Monitoring(stream protobuf.Wireguard_MonitoringServer) error
 			continue
 		}
 
-		clients, err := w.service.GetClients(stream.Context(), uuid.Nil, uuid.Nil)
+		// get clients of all nodes
+		clients, err := w.allClients(stream.Context())
 		if err != nil {
 			log.Error().Err(err).Msg("Failed to get clients")
 			continue
 		}
 
-		pClients := make([]*protobuf.Client, 0, len(clients))
-		for _, client := range clients {
-			pClients = append(pClients, &protobuf.Client{
-				UserID:   client.UserID.String(),
-				GroupID:  client.GroupID.String(),
-				Banned:   client.Banned,
-				LastSeen: client.LastSeen,
-			})
-		}
-
 		if err = stream.Send(&protobuf.MonitoringResponse{
-			Clients: pClients,
+			Clients: protobufClients(clients),
 		}); err != nil {
 			log.Error().Err(err).Msg("Failed to send monitoring response")
 		}
 	}
 }
+
+// allClients returns the clients of all nodes page by page
+func (w *Wireguard) allClients(ctx context.Context) ([]*model.Client, error) {
+	var clients []*model.Client
+	query := model.ClientsQuery{}
+	for {
+		page, err := w.service.ListClients(ctx, query)
+		if err != nil {
+			return nil, err
+		}
+		clients = append(clients, page.Clients...)
+
+		if page.NextPageToken == "" {
+			return clients, nil
+		}
+		query.PageToken = page.NextPageToken
+	}
+}
@@ -0,0 +1,143 @@
+package grpc
+
+import (
+	"context"
+	"crypto/x509"
+	"github.com/cybericebox/wireguard/pkg/appError"
+	"github.com/rs/zerolog/log"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+	"gopkg.in/yaml.v3"
+	"os"
+	"sync"
+	"time"
+)
+
+type (
+	// certIdentities map the client certificates to the identities.
+	// The file is reloaded on the next lookup after it changes.
+	certIdentities struct {
+		m       sync.Mutex
+		file    string
+		modTime time.Time
+		byName  map[string]certIdentity
+	}
+
+	// certIdentity is the entry of the identities file. The name is matched against
+	// the full subject, the subject common name and the DNS, email, URI and IP SANs of the certificate.
+	certIdentity struct {
+		Name    string   `yaml:"name"`
+		Roles   []string `yaml:"roles"`
+		GroupID string   `yaml:"groupID"`
+	}
+)
+
+func newCertIdentities(file string) (*certIdentities, error) {
+	c := &certIdentities{file: file}
+
+	if file == "" {
+		return c, nil
+	}
+
+	info, err := os.Stat(file)
+	if err != nil {
+		return nil, appError.ErrGRPC.WithError(err).WithMessage("Failed to stat certificate identities file").Err()
+	}
+
+	if err = c.load(info.ModTime()); err != nil {
+		return nil, appError.ErrGRPC.WithError(err).WithMessage("Failed to load certificate identities").Err()
+	}
+
+	return c, nil
+}
+
+func (c *certIdentities) load(modTime time.Time) error {
+	data, err := os.ReadFile(c.file)
+	if err != nil {
+		return appError.ErrGRPC.WithError(err).WithMessage("Failed to read certificate identities file").Err()
+	}
+
+	var entries []certIdentity
+	if err = yaml.Unmarshal(data, &entries); err != nil {
+		return appError.ErrGRPC.WithError(err).WithMessage("Failed to parse certificate identities file").Err()
+	}
+
+	byName := make(map[string]certIdentity, len(entries))
+	for _, e := range entries {
+		byName[e.Name] = e
+	}
+
+	c.byName, c.modTime = byName, modTime
+	log.Debug().Int("identities", len(byName)).Msg("Certificate identities loaded")
+
+	return nil
+}
+
+// lookup returns the identity of the certificate, the previous identities are kept if the changed file can not be loaded
+func (c *certIdentities) lookup(cert *x509.Certificate) (*identity, bool) {
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	if c.file == "" {
+		return nil, false
+	}
+
+	if info, err := os.Stat(c.file); err != nil {
+		log.Error().Err(err).Msg("Failed to stat certificate identities file, keeping previous identities")
+	} else if !info.ModTime().Equal(c.modTime) {
+		log.Info().Msg("Certificate identities changed, reloading")
+		if err = c.load(info.ModTime()); err != nil {
+			log.Error().Err(err).Msg("Failed to reload certificate identities, keeping previous identities")
+		}
+	}
+
+	for _, name := range certNames(cert) {
+		if e, ok := c.byName[name]; ok {
+			return &identity{
+				subject: name,
+				roles:   e.Roles,
+				groupID: e.GroupID,
+			}, true
+		}
+	}
+
+	return nil, false
+}
+
+// certNames returns the names of the certificate in the order they are matched
+func certNames(cert *x509.Certificate) []string {
+	names := []string{cert.Subject.String()}
+	if cert.Subject.CommonName != "" {
+		names = append(names, cert.Subject.CommonName)
+	}
+	names = append(names, cert.DNSNames...)
+	names = append(names, cert.EmailAddresses...)
+	for _, uri := range cert.URIs {
+		names = append(names, uri.String())
+	}
+	for _, ip := range cert.IPAddresses {
+		names = append(names, ip.String())
+	}
+	return names
+}
+
+// authenticatePeer authenticates the request by the verified client certificate
+func (a *auth) authenticatePeer(ctx context.Context) (context.Context, error) {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return ctx, appError.ErrGRPCMissingCertificate.Err()
+	}
+
+	tlsInfo, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok || len(tlsInfo.State.VerifiedChains) == 0 || len(tlsInfo.State.VerifiedChains[0]) == 0 {
+		return ctx, appError.ErrGRPCMissingCertificate.Err()
+	}
+
+	cert := tlsInfo.State.VerifiedChains[0][0]
+	id, ok := a.certIdentities.lookup(cert)
+	if !ok {
+		return ctx, appError.ErrGRPCUnknownCertificate.WithContext("subject", cert.Subject.String()).Err()
+	}
+
+	return context.WithValue(ctx, identityKey{}, id), nil
+}
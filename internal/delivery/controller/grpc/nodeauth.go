@@ -0,0 +1,177 @@
+package grpc
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"github.com/cybericebox/wireguard/pkg/appError"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/proto"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Metadata keys of the requests forwarded by another node.
+// The node authenticates the caller and forwards its identity signed by the node key,
+// so the caller credentials are not needed by the node which handles the request.
+const (
+	// forwardedByKey is the ID of the node which forwarded the request
+	forwardedByKey      = "x-forwarded-by"
+	forwardedAtKey      = "x-forwarded-at"
+	forwardedNonceKey   = "x-forwarded-nonce"
+	forwardedSubjectKey = "x-forwarded-subject"
+	forwardedRolesKey   = "x-forwarded-roles"
+	forwardedGroupKey   = "x-forwarded-group"
+	// nodeSignatureKey is the hex HMAC-SHA256 of the method, the request and the forwarded metadata by the node key
+	nodeSignatureKey = "x-node-signature"
+
+	// nodeSignatureTTL is the time the signature is valid for, the requests are signed right before they are sent,
+	// so it only allows the clock skew between the nodes
+	nodeSignatureTTL = 5 * time.Second
+)
+
+type (
+	// signedRequest is the forwarded request the node signature is computed of
+	signedRequest struct {
+		method  string
+		nodeID  string
+		at      string
+		nonce   string
+		digest  string
+		subject string
+		groupID string
+		roles   []string
+	}
+
+	// nonceCache remembers the nonces of the accepted node signatures until they expire, so every signature is accepted once
+	nonceCache struct {
+		m    sync.Mutex
+		seen map[string]time.Time
+	}
+)
+
+// sign returns the signature of the request by the key.
+// The metadata values can not contain new lines, so they separate the fields and the roles are the last ones.
+func (r signedRequest) sign(key string) string {
+	mac := hmac.New(sha256.New, []byte(key))
+	mac.Write([]byte(strings.Join(append([]string{r.method, r.nodeID, r.at, r.nonce, r.digest, r.subject, r.groupID}, r.roles...), "\n")))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// requestDigest returns the hex SHA-256 of the deterministically marshaled request message
+func requestDigest(req interface{}) (string, error) {
+	message, ok := req.(proto.Message)
+	if !ok {
+		return "", fmt.Errorf("request %T is not a protobuf message", req)
+	}
+
+	data, err := proto.MarshalOptions{Deterministic: true}.Marshal(message)
+	if err != nil {
+		return "", err
+	}
+
+	digest := sha256.Sum256(data)
+	return hex.EncodeToString(digest[:]), nil
+}
+
+// newNonce returns the random hex nonce of the signature
+func newNonce() (string, error) {
+	nonce := make([]byte, 16)
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(nonce), nil
+}
+
+func newNonceCache() *nonceCache {
+	return &nonceCache{seen: make(map[string]time.Time)}
+}
+
+// use reports whether the nonce was not used before and remembers it until it expires
+func (c *nonceCache) use(nonce string, expiresAt time.Time) bool {
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	now := time.Now()
+	for n, expiration := range c.seen {
+		if now.After(expiration) {
+			delete(c.seen, n)
+		}
+	}
+
+	if _, ok := c.seen[nonce]; ok {
+		return false
+	}
+	c.seen[nonce] = expiresAt
+	return true
+}
+
+// authenticateNode authenticates the request forwarded by another node as the identity of its caller.
+// The signature is bound to the method and the request message, it expires with the signature TTL and is accepted once,
+// so it can not be reused for other requests. The streams are never forwarded, so they have no request and are rejected.
+func (a *auth) authenticateNode(ctx context.Context, md metadata.MD, req interface{}) (context.Context, error) {
+	if a.nodeKey == "" {
+		return ctx, appError.ErrGRPCInvalidNodeSignature.WithMessage("Node key is not configured").Err()
+	}
+
+	signed := signedRequest{
+		nodeID:  firstOrEmpty(md.Get(forwardedByKey)),
+		at:      firstOrEmpty(md.Get(forwardedAtKey)),
+		nonce:   firstOrEmpty(md.Get(forwardedNonceKey)),
+		subject: firstOrEmpty(md.Get(forwardedSubjectKey)),
+		groupID: firstOrEmpty(md.Get(forwardedGroupKey)),
+		roles:   md.Get(forwardedRolesKey),
+	}
+	if signed.nodeID == "" {
+		return ctx, appError.ErrGRPCInvalidNodeSignature.WithMessage("Forwarding node is missing").Err()
+	}
+	if signed.nonce == "" {
+		return ctx, appError.ErrGRPCInvalidNodeSignature.WithMessage("Node signature nonce is missing").WithContext("nodeID", signed.nodeID).Err()
+	}
+
+	unix, err := strconv.ParseInt(signed.at, 10, 64)
+	if err != nil {
+		return ctx, appError.ErrGRPCInvalidNodeSignature.WithError(err).WithMessage("Invalid forwarding time").Err()
+	}
+
+	signedAt := time.Unix(unix, 0)
+	if age := time.Since(signedAt); age > nodeSignatureTTL || age < -nodeSignatureTTL {
+		return ctx, appError.ErrGRPCInvalidNodeSignature.WithMessage("Node signature is expired").WithContext("nodeID", signed.nodeID).Err()
+	}
+
+	if req == nil {
+		return ctx, appError.ErrGRPCInvalidNodeSignature.WithMessage("Forwarded request is missing").WithContext("nodeID", signed.nodeID).Err()
+	}
+	if signed.digest, err = requestDigest(req); err != nil {
+		return ctx, appError.ErrGRPCInvalidNodeSignature.WithError(err).WithMessage("Failed to digest forwarded request").Err()
+	}
+
+	signed.method, _ = grpc.Method(ctx)
+	if !hmac.Equal([]byte(signed.sign(a.nodeKey)), []byte(firstOrEmpty(md.Get(nodeSignatureKey)))) {
+		return ctx, appError.ErrGRPCInvalidNodeSignature.WithContext("nodeID", signed.nodeID).Err()
+	}
+
+	if !a.nonces.use(signed.nonce, signedAt.Add(nodeSignatureTTL)) {
+		return ctx, appError.ErrGRPCInvalidNodeSignature.WithMessage("Node signature is already used").WithContext("nodeID", signed.nodeID).Err()
+	}
+
+	return context.WithValue(ctx, identityKey{}, &identity{
+		subject:     signed.subject,
+		roles:       signed.roles,
+		groupID:     signed.groupID,
+		forwardedBy: signed.nodeID,
+	}), nil
+}
+
+// isForwarded reports whether the request was forwarded by another node.
+// Only the requests authenticated by the node signature are forwarded, the metadata of other callers is ignored.
+func isForwarded(ctx context.Context) bool {
+	id := identityFromContext(ctx)
+	return id != nil && id.forwardedBy != ""
+}
@@ -0,0 +1,173 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"github.com/cybericebox/wireguard/internal/config"
+	"github.com/cybericebox/wireguard/internal/model"
+	"github.com/cybericebox/wireguard/pkg/appError"
+	"github.com/cybericebox/wireguard/pkg/controller/grpc/client"
+	"github.com/cybericebox/wireguard/pkg/controller/grpc/protobuf"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"slices"
+	"strconv"
+	"testing"
+	"time"
+)
+
+const (
+	testNodeKey = "node-key"
+	testMethod  = "/wireguard.Wireguard/DeleteClients"
+)
+
+// methodStream is the server transport stream of the method, so grpc.Method works on the test contexts
+type methodStream struct {
+	grpc.ServerTransportStream
+	method string
+}
+
+func (s methodStream) Method() string {
+	return s.method
+}
+
+func newTestAuth(t *testing.T) *auth {
+	t.Helper()
+	a, err := NewAuthenticator(&config.AuthConfig{Mode: config.AuthModeJWT, AuthKey: "auth-key", SignKey: "sign-key", NodeKey: testNodeKey})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return a.(*auth)
+}
+
+// testRequest is the request of the forwarded calls
+var testRequest = &protobuf.ClientsRequest{UserID: "user", GroupID: "group"}
+
+// forwardedContext returns the incoming context of the request signed by the node router with the key
+func forwardedContext(t *testing.T, key string, tamper func(md metadata.MD)) context.Context {
+	t.Helper()
+	caller := context.WithValue(context.Background(), identityKey{}, &identity{
+		subject: "operator",
+		roles:   []string{client.RoleOperator},
+		groupID: "group",
+	})
+
+	router := newNodeRouter(nil, key)
+	var md metadata.MD
+	capture := func(ctx context.Context, _ string, _, _ interface{}, _ *grpc.ClientConn, _ ...grpc.CallOption) error {
+		md, _ = metadata.FromOutgoingContext(ctx)
+		return nil
+	}
+	if err := router.signRequest(router.outgoingContext(caller, "node-a"), testMethod, testRequest, nil, nil, capture); err != nil {
+		t.Fatalf("sign request: %v", err)
+	}
+
+	md = md.Copy()
+	if tamper != nil {
+		tamper(md)
+	}
+
+	ctx := metadata.NewIncomingContext(context.Background(), md)
+	return grpc.NewContextWithServerTransportStream(ctx, methodStream{method: testMethod})
+}
+
+// resign signs the metadata again by the test key, so only the changed field is checked
+func resign(md metadata.MD) {
+	md.Set(nodeSignatureKey, signedRequest{
+		method:  testMethod,
+		nodeID:  firstOrEmpty(md.Get(forwardedByKey)),
+		at:      firstOrEmpty(md.Get(forwardedAtKey)),
+		nonce:   firstOrEmpty(md.Get(forwardedNonceKey)),
+		digest:  mustDigest(testRequest),
+		subject: firstOrEmpty(md.Get(forwardedSubjectKey)),
+		groupID: firstOrEmpty(md.Get(forwardedGroupKey)),
+		roles:   md.Get(forwardedRolesKey),
+	}.sign(testNodeKey))
+}
+
+func mustDigest(req interface{}) string {
+	digest, err := requestDigest(req)
+	if err != nil {
+		panic(err)
+	}
+	return digest
+}
+
+func TestAuthenticateNode(t *testing.T) {
+	a := newTestAuth(t)
+
+	signed := forwardedContext(t, testNodeKey, nil)
+	ctx, err := a.AuthenticateContext(signed, testRequest)
+	if err != nil {
+		t.Fatalf("signed request: %v", err)
+	}
+	if !isForwarded(ctx) {
+		t.Fatal("signed request is not forwarded")
+	}
+	id := identityFromContext(ctx)
+	if id.subject != "operator" || id.groupID != "group" || !slices.Equal(id.roles, []string{client.RoleOperator}) || id.forwardedBy != "node-a" {
+		t.Fatalf("unexpected identity %+v", id)
+	}
+
+	type rejectedRequest struct {
+		ctx context.Context
+		req interface{}
+	}
+	rejected := map[string]rejectedRequest{
+		"replayed":  {ctx: signed, req: testRequest},
+		"wrong key": {ctx: forwardedContext(t, "other-key", nil), req: testRequest},
+		"expired": {ctx: forwardedContext(t, testNodeKey, func(md metadata.MD) {
+			md.Set(forwardedAtKey, strconv.FormatInt(time.Now().Add(-2*nodeSignatureTTL).Unix(), 10))
+			resign(md)
+		}), req: testRequest},
+		"missing nonce": {ctx: forwardedContext(t, testNodeKey, func(md metadata.MD) {
+			md.Delete(forwardedNonceKey)
+			resign(md)
+		}), req: testRequest},
+		"escalated roles": {ctx: forwardedContext(t, testNodeKey, func(md metadata.MD) {
+			md.Set(forwardedRolesKey, client.RoleAdmin)
+		}), req: testRequest},
+		"other group": {ctx: forwardedContext(t, testNodeKey, func(md metadata.MD) {
+			md.Set(forwardedGroupKey, "other")
+		}), req: testRequest},
+		"other request": {ctx: forwardedContext(t, testNodeKey, nil), req: &protobuf.ClientsRequest{UserID: "other-user", GroupID: "group"}},
+		"stream":        {ctx: forwardedContext(t, testNodeKey, nil)},
+		"other method":  {ctx: grpc.NewContextWithServerTransportStream(forwardedContext(t, testNodeKey, nil), methodStream{method: "/wireguard.Wireguard/BanClients"}), req: testRequest},
+	}
+	for name, r := range rejected {
+		if _, err = a.AuthenticateContext(r.ctx, r.req); !errors.Is(err, appError.ErrGRPCInvalidNodeSignature.Err()) {
+			t.Errorf("%s: expected invalid node signature, got %v", name, err)
+		}
+	}
+}
+
+func TestForwardedHeaderWithoutSignatureIsIgnored(t *testing.T) {
+	a := newTestAuth(t)
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(forwardedByKey, "node-a"))
+	if _, err := a.AuthenticateContext(ctx, testRequest); err == nil {
+		t.Fatal("forwarded header without signature authenticated the request")
+	}
+
+	if isForwarded(ctx) {
+		t.Fatal("forwarded header without signature marks the request as forwarded")
+	}
+}
+
+func TestNodeRouterRequiresNodeKey(t *testing.T) {
+	r := newNodeRouter(nil, "")
+	if _, err := r.client(&model.Node{ID: "node-b", GRPCEndpoint: "node-b:5454"}); !errors.Is(err, appError.ErrNodeMissingKey.Err()) {
+		t.Fatalf("expected missing node key, got %v", err)
+	}
+}
+
+func TestNodeForwardingRequiresTLS(t *testing.T) {
+	r := newNodeRouter(nil, testNodeKey)
+	if _, err := r.client(&model.Node{ID: "node-b", GRPCEndpoint: "node-b:5454"}); !errors.Is(err, appError.ErrNodeMissingTLS.Err()) {
+		t.Fatalf("expected missing TLS, got %v", err)
+	}
+
+	if _, err := New(Dependencies{Config: &config.GRPCConfig{Auth: config.AuthConfig{Mode: config.AuthModeJWT, NodeKey: testNodeKey}}}); !errors.Is(err, appError.ErrNodeMissingTLS.Err()) {
+		t.Fatalf("expected the server with the node key and without TLS to fail, got %v", err)
+	}
+}
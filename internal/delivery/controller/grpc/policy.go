@@ -0,0 +1,96 @@
+package grpc
+
+import (
+	"context"
+	"github.com/cybericebox/wireguard/pkg/appError"
+	"github.com/cybericebox/wireguard/pkg/controller/grpc/client"
+	"github.com/cybericebox/wireguard/pkg/controller/grpc/protobuf"
+)
+
+// roleLevels orders the roles, every role is allowed to do what the lower roles do
+var roleLevels = map[string]int{
+	client.RoleViewer:   1,
+	client.RoleOperator: 2,
+	client.RoleAdmin:    3,
+}
+
+// policy is the role required to call the method, methods which are not listed require the admin role
+var policy = map[string]string{
+	protobuf.Wireguard_Ping_FullMethodName:             client.RoleViewer,
+	protobuf.Wireguard_Monitoring_FullMethodName:       client.RoleViewer,
+	protobuf.Wireguard_GetClients_FullMethodName:       client.RoleViewer,
+	protobuf.Wireguard_GetClientConfig_FullMethodName:  client.RoleOperator,
+	protobuf.Wireguard_ProvisionClients_FullMethodName: client.RoleOperator,
+	protobuf.Wireguard_BanClients_FullMethodName:       client.RoleOperator,
+	protobuf.Wireguard_UnBanClients_FullMethodName:     client.RoleOperator,
+	protobuf.Wireguard_SetClientLimits_FullMethodName:  client.RoleOperator,
+	protobuf.Wireguard_DeleteClients_FullMethodName:    client.RoleAdmin,
+	protobuf.Wireguard_GetClient_FullMethodName:        client.RoleAdmin,
+	protobuf.Wireguard_GetAuditLog_FullMethodName:      client.RoleAdmin,
+	protobuf.Wireguard_ListDNSRecords_FullMethodName:   client.RoleViewer,
+	protobuf.Wireguard_CreateDNSRecord_FullMethodName:  client.RoleOperator,
+	protobuf.Wireguard_DeleteDNSRecord_FullMethodName:  client.RoleOperator,
+	// the sessions expose the client endpoints as the client details do
+	protobuf.Wireguard_GetClientSessions_FullMethodName: client.RoleAdmin,
+	protobuf.Wireguard_ReplayWebhooks_FullMethodName:    client.RoleAdmin,
+}
+
+// groupFreeMethods can be called by group-scoped tokens although their requests do not target a group
+var groupFreeMethods = map[string]bool{
+	protobuf.Wireguard_Ping_FullMethodName: true,
+}
+
+// groupTarget is the request which targets a group
+type groupTarget interface {
+	GetGroupID() string
+}
+
+// authorize checks that the authenticated caller has the role the method requires
+// and that group-scoped callers target only their own group
+func authorize(ctx context.Context, fullMethod string, req interface{}) error {
+	id := identityFromContext(ctx)
+	if id == nil {
+		return appError.ErrGRPCUnauthenticated.Err()
+	}
+
+	required, ok := policy[fullMethod]
+	if !ok {
+		required = client.RoleAdmin
+	}
+
+	if id.level() < roleLevels[required] {
+		return appError.ErrGRPCInsufficientRole.WithContext("method", fullMethod).WithContext("requiredRole", required).Err()
+	}
+
+	if id.groupID == "" || groupFreeMethods[fullMethod] {
+		return nil
+	}
+
+	// every client of the batch has to be of the caller group
+	if batch, ok := req.(*protobuf.ProvisionClientsRequest); ok {
+		for _, c := range batch.GetClients() {
+			if c.GetGroupID() != id.groupID {
+				return appError.ErrGRPCGroupForbidden.WithContext("method", fullMethod).WithContext("groupID", id.groupID).Err()
+			}
+		}
+		return nil
+	}
+
+	target, ok := req.(groupTarget)
+	if !ok || target.GetGroupID() != id.groupID {
+		return appError.ErrGRPCGroupForbidden.WithContext("method", fullMethod).WithContext("groupID", id.groupID).Err()
+	}
+
+	return nil
+}
+
+// level returns the level of the highest known role of the identity
+func (id *identity) level() int {
+	level := 0
+	for _, role := range id.roles {
+		if l := roleLevels[role]; l > level {
+			level = l
+		}
+	}
+	return level
+}
@@ -0,0 +1,163 @@
+package grpc
+
+import (
+	"context"
+	"github.com/cybericebox/wireguard/internal/model"
+	"github.com/cybericebox/wireguard/pkg/appError"
+	"github.com/cybericebox/wireguard/pkg/controller/grpc/protobuf"
+	"github.com/gofrs/uuid"
+	"github.com/rs/zerolog/log"
+	"strconv"
+)
+
+// maxProvisionClients limits the batch, so the peers of the batch fit into a single wg call
+const maxProvisionClients = 500
+
+type IProvisionService interface {
+	ProvisionClients(ctx context.Context, items []*model.ProvisionItem) []*model.ProvisionResult
+}
+
+// nodeBatch is the part of the batch owned by the node with the positions of its clients in the batch
+type nodeBatch struct {
+	node      *model.Node
+	request   *protobuf.ProvisionClientsRequest
+	positions []int
+}
+
+// ProvisionClients returns the configs of the clients creating the missing ones in bulk on their nodes.
+// The clients are returned in the order of the request with their own errors.
+func (w *Wireguard) ProvisionClients(ctx context.Context, request *protobuf.ProvisionClientsRequest) (_ *protobuf.ProvisionClientsResponse, err error) {
+	var provisioned int64
+	defer func() {
+		w.audit(ctx, model.AuditActionProvisionClients, provisionTarget(request), map[string]string{
+			"clients": strconv.Itoa(len(request.GetClients())),
+		}, provisioned, err)
+	}()
+
+	log.Info().Int("clients", len(request.GetClients())).Msg("Provisioning clients")
+	if len(request.GetClients()) > maxProvisionClients {
+		return &protobuf.ProvisionClientsResponse{}, appError.ErrClientBatchTooLarge.WithContext("maxClients", maxProvisionClients).Err()
+	}
+
+	results := make([]*protobuf.ProvisionedClient, len(request.GetClients()))
+	items := make([]*model.ProvisionItem, 0, len(request.GetClients()))
+	positions := make([]int, 0, len(request.GetClients()))
+
+	for i, c := range request.GetClients() {
+		results[i] = &protobuf.ProvisionedClient{
+			UserID:  c.GetUserID(),
+			GroupID: c.GetGroupID(),
+		}
+
+		userID, err := uuid.FromString(c.GetUserID())
+		if err != nil {
+			results[i].Error = appError.ErrClientInvalidUserID.Err().Error()
+			continue
+		}
+
+		groupID, err := uuid.FromString(c.GetGroupID())
+		if err != nil {
+			results[i].Error = appError.ErrClientInvalidGroupID.Err().Error()
+			continue
+		}
+
+		items = append(items, &model.ProvisionItem{
+			UserID:   userID,
+			GroupID:  groupID,
+			DestCIDR: c.GetDestCIDR(),
+		})
+		positions = append(positions, i)
+	}
+
+	local := items
+	localPositions := positions
+	remote := make(map[string]*nodeBatch)
+
+	// the forwarded clients are assigned to the current node by the forwarding node
+	if !isForwarded(ctx) {
+		local = make([]*model.ProvisionItem, 0, len(items))
+		localPositions = make([]int, 0, len(items))
+
+		// the nodes of the whole batch are planned at once, so the batch is spread by the load of the nodes
+		for j, clientNode := range w.service.GetClientsNodes(ctx, items) {
+			i := positions[j]
+			if clientNode.Err != nil {
+				results[i].Error = appError.ErrNode.WithError(clientNode.Err).WithMessage("Failed to get client node").Err().Error()
+				continue
+			}
+
+			if clientNode.Node.ID != w.service.NodeID() {
+				batch, ok := remote[clientNode.Node.ID]
+				if !ok {
+					batch = &nodeBatch{node: clientNode.Node, request: &protobuf.ProvisionClientsRequest{}}
+					remote[clientNode.Node.ID] = batch
+				}
+				batch.request.Clients = append(batch.request.Clients, request.GetClients()[i])
+				batch.positions = append(batch.positions, i)
+				continue
+			}
+
+			local = append(local, items[j])
+			localPositions = append(localPositions, i)
+		}
+	}
+
+	for j, result := range w.service.ProvisionClients(ctx, local) {
+		results[localPositions[j]].Config = result.Config
+		if result.Err != nil {
+			results[localPositions[j]].Error = result.Err.Error()
+		}
+	}
+
+	for _, batch := range remote {
+		w.provisionOnNode(ctx, batch, results)
+	}
+
+	for _, result := range results {
+		if result.GetError() == "" {
+			provisioned++
+		}
+	}
+
+	log.Info().Int("clients", len(results)).Int64("provisioned", provisioned).Msg("Clients provisioned")
+	return &protobuf.ProvisionClientsResponse{
+		Clients: results,
+	}, nil
+}
+
+// provisionOnNode forwards the part of the batch to its node, the failure of the node fails all its clients
+func (w *Wireguard) provisionOnNode(ctx context.Context, batch *nodeBatch, results []*protobuf.ProvisionedClient) {
+	resp, err := func() (*protobuf.ProvisionClientsResponse, error) {
+		c, err := w.router.client(batch.node)
+		if err != nil {
+			return nil, err
+		}
+
+		log.Debug().Str("nodeID", batch.node.ID).Int("clients", len(batch.positions)).Msg("Forwarding clients provisioning to node")
+		return c.ProvisionClients(w.router.outgoingContext(ctx, w.service.NodeID()), batch.request)
+	}()
+
+	if err == nil && len(resp.GetClients()) != len(batch.positions) {
+		err = appError.ErrNode.WithMessage("Node returned unexpected number of clients").WithContext("nodeID", batch.node.ID).Err()
+	}
+
+	for j, position := range batch.positions {
+		if err != nil {
+			results[position].Error = appError.ErrNodeUnavailable.WithError(err).WithContext("nodeID", batch.node.ID).Err().Error()
+			continue
+		}
+		results[position] = resp.GetClients()[j]
+	}
+}
+
+// provisionTarget is the audit target of the batch, the group is recorded only if all clients are of the same group
+func provisionTarget(request *protobuf.ProvisionClientsRequest) clientsTarget {
+	target := &protobuf.ClientRequest{}
+	for i, c := range request.GetClients() {
+		if i > 0 && c.GetGroupID() != target.GetGroupID() {
+			return &protobuf.ClientRequest{}
+		}
+		target.GroupID = c.GetGroupID()
+	}
+	return target
+}
@@ -0,0 +1,183 @@
+package grpc
+
+import (
+	"context"
+	"github.com/cybericebox/wireguard/internal/model"
+	"github.com/cybericebox/wireguard/pkg/appError"
+	"github.com/cybericebox/wireguard/pkg/controller/grpc/protobuf"
+	"github.com/gofrs/uuid"
+	"github.com/hashicorp/go-multierror"
+	"github.com/rs/zerolog/log"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"strconv"
+	"sync"
+	"time"
+)
+
+type (
+	INodeService interface {
+		NodeID() string
+		GetNodes(ctx context.Context) ([]*model.Node, error)
+		GetClientNode(ctx context.Context, userID, groupID uuid.UUID) (*model.Node, error)
+		GetClientsNodes(ctx context.Context, items []*model.ProvisionItem) []*model.ClientNode
+	}
+
+	// nodeRouter forwards requests to the nodes which own the clients
+	nodeRouter struct {
+		m sync.Mutex
+		// certs are the certificates of the server, the requests are not forwarded if they are not set
+		certs *certReloader
+		// nodeKey signs the forwarded requests, the requests are not forwarded if it is not set
+		nodeKey     string
+		connections map[string]*grpc.ClientConn
+	}
+)
+
+func newNodeRouter(certs *certReloader, nodeKey string) *nodeRouter {
+	return &nodeRouter{
+		certs:       certs,
+		nodeKey:     nodeKey,
+		connections: make(map[string]*grpc.ClientConn),
+	}
+}
+
+// client returns the client of the node, the connections are reused between requests
+func (r *nodeRouter) client(node *model.Node) (protobuf.WireguardClient, error) {
+	if r.nodeKey == "" {
+		return nil, appError.ErrNodeMissingKey.WithContext("nodeID", node.ID).Err()
+	}
+
+	if r.certs == nil {
+		return nil, appError.ErrNodeMissingTLS.WithContext("nodeID", node.ID).Err()
+	}
+
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	if conn, ok := r.connections[node.GRPCEndpoint]; ok {
+		return protobuf.NewWireguardClient(conn), nil
+	}
+
+	// the server certificate is the client certificate of the node
+	creds := credentials.NewTLS(r.certs.clientConfig())
+
+	log.Debug().Str("nodeID", node.ID).Str("grpcEndpoint", node.GRPCEndpoint).Msg("Connecting to node")
+	conn, err := grpc.NewClient(node.GRPCEndpoint, grpc.WithTransportCredentials(creds), grpc.WithUnaryInterceptor(r.signRequest))
+	if err != nil {
+		return nil, appError.ErrNodeUnavailable.WithError(err).WithContext("nodeID", node.ID).Err()
+	}
+
+	r.connections[node.GRPCEndpoint] = conn
+
+	return protobuf.NewWireguardClient(conn), nil
+}
+
+// outgoingContext passes the identity of the authenticated caller to the node and marks the request as forwarded.
+// The caller credentials are not passed, the request is authenticated by the node signature.
+func (r *nodeRouter) outgoingContext(ctx context.Context, nodeID string) context.Context {
+	md := metadata.Pairs(forwardedByKey, nodeID)
+	if id := identityFromContext(ctx); id != nil {
+		md.Set(forwardedSubjectKey, id.subject)
+		md.Set(forwardedGroupKey, id.groupID)
+		md.Set(forwardedRolesKey, id.roles...)
+	}
+
+	return metadata.NewOutgoingContext(ctx, md)
+}
+
+// signRequest signs the forwarded request with the node key at the time it is sent
+func (r *nodeRouter) signRequest(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	md, _ := metadata.FromOutgoingContext(ctx)
+	signed := signedRequest{
+		method:  method,
+		nodeID:  firstOrEmpty(md.Get(forwardedByKey)),
+		at:      strconv.FormatInt(time.Now().Unix(), 10),
+		subject: firstOrEmpty(md.Get(forwardedSubjectKey)),
+		groupID: firstOrEmpty(md.Get(forwardedGroupKey)),
+		roles:   md.Get(forwardedRolesKey),
+	}
+
+	var err error
+	if signed.nonce, err = newNonce(); err != nil {
+		return appError.ErrNode.WithError(err).WithMessage("Failed to generate node signature nonce").Err()
+	}
+	if signed.digest, err = requestDigest(req); err != nil {
+		return appError.ErrNode.WithError(err).WithMessage("Failed to digest forwarded request").Err()
+	}
+
+	return invoker(metadata.AppendToOutgoingContext(ctx,
+		forwardedAtKey, signed.at,
+		forwardedNonceKey, signed.nonce,
+		nodeSignatureKey, signed.sign(r.nodeKey),
+	), method, req, reply, cc, opts...)
+}
+
+func firstOrEmpty(values []string) string {
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
+
+// forEachRemoteNode calls the function for every alive node except the current one.
+// Requests forwarded by another node are never fanned out again.
+func (w *Wireguard) forEachRemoteNode(ctx context.Context, fn func(ctx context.Context, client protobuf.WireguardClient) error) error {
+	if isForwarded(ctx) {
+		return nil
+	}
+
+	nodes, err := w.service.GetNodes(ctx)
+	if err != nil {
+		return appError.ErrNode.WithError(err).WithMessage("Failed to get nodes").Err()
+	}
+
+	var errs error
+	for _, node := range nodes {
+		if node.ID == w.service.NodeID() {
+			continue
+		}
+
+		c, err := w.router.client(node)
+		if err != nil {
+			errs = multierror.Append(errs, err)
+			continue
+		}
+
+		log.Debug().Str("nodeID", node.ID).Msg("Forwarding request to node")
+		if err = fn(w.router.outgoingContext(ctx, w.service.NodeID()), c); err != nil {
+			errs = multierror.Append(errs, appError.ErrNodeUnavailable.WithError(err).WithContext("nodeID", node.ID).Err())
+		}
+	}
+
+	if errs != nil {
+		return appError.ErrNode.WithError(errs).WithMessage("Failed to forward request to nodes").Err()
+	}
+
+	return nil
+}
+
+// clientOwner returns the client of the node which owns the client or nil if the request has to be handled locally
+func (w *Wireguard) clientOwner(ctx context.Context, userID, groupID uuid.UUID) (protobuf.WireguardClient, context.Context, error) {
+	if isForwarded(ctx) {
+		return nil, ctx, nil
+	}
+
+	node, err := w.service.GetClientNode(ctx, userID, groupID)
+	if err != nil {
+		return nil, ctx, appError.ErrNode.WithError(err).WithMessage("Failed to get client node").Err()
+	}
+
+	if node.ID == w.service.NodeID() {
+		return nil, ctx, nil
+	}
+
+	c, err := w.router.client(node)
+	if err != nil {
+		return nil, ctx, err
+	}
+
+	log.Debug().Str("nodeID", node.ID).Str("userID", userID.String()).Str("groupID", groupID.String()).Msg("Forwarding request to client node")
+	return c, w.router.outgoingContext(ctx, w.service.NodeID()), nil
+}
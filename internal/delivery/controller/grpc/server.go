@@ -2,17 +2,16 @@ package grpc
 
 import (
 	"context"
-	"crypto/tls"
-	"crypto/x509"
 	"github.com/cybericebox/wireguard/internal/config"
 	"github.com/cybericebox/wireguard/pkg/appError"
 	"github.com/cybericebox/wireguard/pkg/controller/grpc/protobuf"
 	"github.com/rs/zerolog/log"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 	"google.golang.org/grpc/reflection"
-
-	"os"
+	"strings"
 )
 
 type (
@@ -20,79 +19,70 @@ type (
 		auth    Authenticator
 		config  *config.GRPCConfig
 		service IService
+		router  *nodeRouter
 		protobuf.UnimplementedWireguardServer
 	}
 
 	Dependencies struct {
 		Config  *config.GRPCConfig
 		Service IService
+		Health  *health.Server
 	}
 
 	IService interface {
 		IActionsService
 		IMonitoringService
+		INodeService
+		IAuditService
+		IProvisionService
+		IDNSService
+		ISessionService
+		IWebhookService
 	}
 )
 
-func getCredentials(conf *config.TLSConfig) (credentials.TransportCredentials, error) {
-	log.Debug().Msg("Preparing credentials for RPC")
-
-	certificate, err := tls.LoadX509KeyPair(conf.CertFile, conf.CertKey)
-	if err != nil {
-		return nil, appError.ErrGRPC.WithError(err).WithMessage("Failed to load certificates").Err()
-	}
-
-	// Create a certificate pool from the certificate authority
-	certPool := x509.NewCertPool()
-	ca, err := os.ReadFile(conf.CAFile)
-	if err != nil {
-		return nil, appError.ErrGRPC.WithError(err).WithMessage("Failed to read CA file").Err()
-	}
-	// CA file for let's encrypt is located under domain conf as `chain.pem`
-	// pass chain.pem location
-	// Append the client certificates from the CA
-	if ok := certPool.AppendCertsFromPEM(ca); !ok {
-		return nil, appError.ErrGRPC.WithMessage("Failed to append client certificates").Err()
+// SecureConn enables communication over secure channel
+func secureConn(certs *certReloader) []grpc.ServerOption {
+	if certs == nil {
+		return []grpc.ServerOption{}
 	}
 
-	// Create the TLS credentials
-	creds := credentials.NewTLS(&tls.Config{
-		ClientAuth:   tls.RequireAndVerifyClientCert,
-		Certificates: []tls.Certificate{certificate},
-		ClientCAs:    certPool,
-	})
-	return creds, nil
+	log.Debug().Msg("Server is running in secure mode!")
+	return []grpc.ServerOption{grpc.Creds(credentials.NewTLS(certs.serverConfig()))}
 }
 
-// SecureConn enables communication over secure channel
-func secureConn(conf *config.TLSConfig) ([]grpc.ServerOption, error) {
-	if conf.Enabled {
-		log.Debug().Msgf("Conf cert-file: %s, cert-key: %s ca: %s", conf.CertFile, conf.CertKey, conf.CAFile)
-		creds, err := getCredentials(conf)
-		if err != nil {
-			return []grpc.ServerOption{}, appError.ErrGRPC.WithError(err).WithMessage("Failed to get credentials").Err()
+func New(deps Dependencies) (*grpc.Server, error) {
+	// the forwarded requests carry the caller identity, so they are never sent over insecure connections
+	if deps.Config.Auth.NodeKey != "" && !deps.Config.TLS.Enabled {
+		return nil, appError.ErrNodeMissingTLS.WithMessage("Node forwarding requires TLS, enable TLS or unset the node key").Err()
+	}
+
+	var certs *certReloader
+	if deps.Config.TLS.Enabled {
+		log.Debug().Msgf("Conf cert-file: %s, cert-key: %s ca: %s", deps.Config.TLS.CertFile, deps.Config.TLS.CertKey, deps.Config.TLS.CAFile)
+		var err error
+		if certs, err = newCertReloader(&deps.Config.TLS); err != nil {
+			return nil, appError.ErrGRPC.WithError(err).WithMessage("Failed to get secure connection").Err()
 		}
+	}
 
-		log.Debug().Msg("Server is running in secure mode!")
-		return []grpc.ServerOption{grpc.Creds(creds)}, nil
+	authenticator, err := NewAuthenticator(&deps.Config.Auth)
+	if err != nil {
+		return nil, appError.ErrGRPC.WithError(err).WithMessage("Failed to get authenticator").Err()
 	}
-	return []grpc.ServerOption{}, nil
-}
 
-func New(deps Dependencies) (*grpc.Server, error) {
 	gRPCServer := &Wireguard{
-		auth:    NewAuthenticator(deps.Config.Auth.SignKey, deps.Config.Auth.AuthKey),
+		auth:    authenticator,
 		config:  deps.Config,
 		service: deps.Service,
+		router:  newNodeRouter(certs, deps.Config.Auth.NodeKey),
 	}
-	opts, err := secureConn(&deps.Config.TLS)
-	if err != nil {
-		return nil, appError.ErrGRPC.WithError(err).WithMessage("Failed to get secure connection").Err()
-	}
+	opts := secureConn(certs)
 
 	gRPCEndpoint := gRPCServer.addAuth(opts...)
 
 	reflection.Register(gRPCEndpoint)
+	healthpb.RegisterHealthServer(gRPCEndpoint, deps.Health)
 	protobuf.RegisterWireguardServer(gRPCEndpoint, gRPCServer)
 
 	return gRPCEndpoint, nil
@@ -101,16 +91,32 @@ func New(deps Dependencies) (*grpc.Server, error) {
 // AddAuth adds authentication to gRPC server
 func (w *Wireguard) addAuth(opts ...grpc.ServerOption) *grpc.Server {
 	streamInterceptor := func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
-		if err := w.auth.AuthenticateContext(stream.Context()); err != nil {
+		// health checks are used by probes without credentials
+		if isHealthMethod(info.FullMethod) {
+			return handler(srv, stream)
+		}
+		ctx, err := w.auth.AuthenticateContext(stream.Context(), nil)
+		if err != nil {
 			return appError.ErrGRPC.WithError(err).WithMessage("Failed to authenticate context").Err()
 		}
+		// streams carry no request to scope, group-scoped callers are allowed only to groupless methods
+		if err = authorize(ctx, info.FullMethod, nil); err != nil {
+			return appError.ErrGRPC.WithError(err).WithMessage("Failed to authorize context").Err()
+		}
 		return handler(srv, stream)
 	}
 
 	unaryInterceptor := func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
-		if err := w.auth.AuthenticateContext(ctx); err != nil {
+		if isHealthMethod(info.FullMethod) {
+			return handler(ctx, req)
+		}
+		ctx, err := w.auth.AuthenticateContext(ctx, req)
+		if err != nil {
 			return nil, appError.ErrGRPC.WithError(err).WithMessage("Failed to authenticate context").Err()
 		}
+		if err = authorize(ctx, info.FullMethod, req); err != nil {
+			return nil, appError.ErrGRPC.WithError(err).WithMessage("Failed to authorize context").Err()
+		}
 		return handler(ctx, req)
 	}
 
@@ -121,3 +127,7 @@ func (w *Wireguard) addAuth(opts ...grpc.ServerOption) *grpc.Server {
 	return grpc.NewServer(opts...)
 
 }
+
+func isHealthMethod(fullMethod string) bool {
+	return strings.HasPrefix(fullMethod, "/"+healthpb.Health_ServiceDesc.ServiceName+"/")
+}
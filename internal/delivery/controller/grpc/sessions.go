@@ -0,0 +1,74 @@
+package grpc
+
+import (
+	"context"
+	"github.com/cybericebox/wireguard/internal/model"
+	"github.com/cybericebox/wireguard/pkg/appError"
+	"github.com/cybericebox/wireguard/pkg/controller/grpc/protobuf"
+	"github.com/gofrs/uuid"
+	"github.com/rs/zerolog/log"
+	"time"
+)
+
+type ISessionService interface {
+	GetClientSessions(ctx context.Context, filter model.SessionsFilter) ([]*model.Session, error)
+}
+
+// The sessions of all nodes are stored in the database, so the request is not forwarded to the client node
+
+func (w *Wireguard) GetClientSessions(ctx context.Context, request *protobuf.ClientSessionsRequest) (*protobuf.ClientSessionsResponse, error) {
+	log.Debug().Str("userID", request.GetUserID()).Str("groupID", request.GetGroupID()).Msg("Getting client sessions")
+
+	userID, err := uuid.FromString(request.GetUserID())
+	if err != nil {
+		log.Error().Err(err).Msg("Parsing user ID")
+		return &protobuf.ClientSessionsResponse{}, appError.ErrClientInvalidUserID.WithError(err).Err()
+	}
+
+	groupID, err := uuid.FromString(request.GetGroupID())
+	if err != nil {
+		log.Error().Err(err).Msg("Parsing group ID")
+		return &protobuf.ClientSessionsResponse{}, appError.ErrClientInvalidGroupID.WithError(err).Err()
+	}
+
+	filter := model.SessionsFilter{
+		UserID:  userID,
+		GroupID: groupID,
+		Limit:   request.GetLimit(),
+	}
+
+	if request.GetSince() > 0 {
+		filter.Since = time.Unix(request.GetSince(), 0)
+	}
+
+	if request.GetUntil() > 0 {
+		filter.Until = time.Unix(request.GetUntil(), 0)
+	}
+
+	sessions, err := w.service.GetClientSessions(ctx, filter)
+	if err != nil {
+		log.Error().Err(err).Msg("Getting client sessions")
+		return &protobuf.ClientSessionsResponse{}, err
+	}
+
+	pSessions := make([]*protobuf.Session, 0, len(sessions))
+	for _, s := range sessions {
+		pSessions = append(pSessions, &protobuf.Session{
+			ID:           s.ID,
+			UserID:       s.UserID.String(),
+			GroupID:      s.GroupID.String(),
+			NodeID:       s.NodeID,
+			Endpoint:     s.Endpoint,
+			TransferRx:   s.TransferRx,
+			TransferTx:   s.TransferTx,
+			StartedAt:    s.StartedAt.Unix(),
+			LastActiveAt: s.LastActiveAt.Unix(),
+			EndedAt:      unixOrZero(s.EndedAt),
+		})
+	}
+
+	log.Debug().Int("sessions", len(pSessions)).Msg("Returning client sessions")
+	return &protobuf.ClientSessionsResponse{
+		Sessions: pSessions,
+	}, nil
+}
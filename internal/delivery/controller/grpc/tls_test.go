@@ -0,0 +1,277 @@
+package grpc
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"github.com/cybericebox/wireguard/internal/config"
+	"github.com/cybericebox/wireguard/internal/model"
+	"github.com/cybericebox/wireguard/pkg/controller/grpc/client"
+	"github.com/cybericebox/wireguard/pkg/controller/grpc/protobuf"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+const (
+	testServerName = "wireguard.test"
+	testClientName = "operator-cert"
+)
+
+// pingService is the service of the servers which are only pinged
+type pingService struct {
+	IService
+}
+
+func (pingService) CheckHealth(context.Context) *model.Health {
+	return &model.Health{Ready: true}
+}
+
+// testCerts are the PEM files of the generated CA and the server and client certificates signed by it
+type testCerts struct {
+	ca, serverCert, serverKey, clientCert, clientKey string
+}
+
+func generateTestCerts(t *testing.T) testCerts {
+	t.Helper()
+	dir := t.TempDir()
+
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	caTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "wgtest CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, &caKey.PublicKey, caKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ca, err := x509.ParseCertificate(caDER)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// issue writes the certificate signed by the CA and its key
+	issue := func(name string, serial int64, usage []x509.ExtKeyUsage, dnsNames []string) (string, string) {
+		key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		der, err := x509.CreateCertificate(rand.Reader, &x509.Certificate{
+			SerialNumber: big.NewInt(serial),
+			Subject:      pkix.Name{CommonName: name},
+			DNSNames:     dnsNames,
+			NotBefore:    time.Now().Add(-time.Hour),
+			NotAfter:     time.Now().Add(time.Hour),
+			KeyUsage:     x509.KeyUsageDigitalSignature,
+			ExtKeyUsage:  usage,
+		}, ca, &key.PublicKey, caKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		keyDER, err := x509.MarshalECPrivateKey(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return writePEM(t, dir, name+".crt", "CERTIFICATE", der), writePEM(t, dir, name+".key", "EC PRIVATE KEY", keyDER)
+	}
+
+	certs := testCerts{ca: writePEM(t, dir, "ca.crt", "CERTIFICATE", caDER)}
+	// the server certificate is also the client certificate of the node
+	certs.serverCert, certs.serverKey = issue(testServerName, 2, []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth}, []string{testServerName})
+	certs.clientCert, certs.clientKey = issue(testClientName, 3, []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}, nil)
+	return certs
+}
+
+func writePEM(t *testing.T, dir, name, blockType string, der []byte) string {
+	t.Helper()
+	file := filepath.Join(dir, name)
+	if err := os.WriteFile(file, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return file
+}
+
+// recordedCall is the identity and the token of the request authenticated by the server
+type recordedCall struct {
+	subject string
+	token   string
+}
+
+// startSecureServer serves the pings over the secure connection of the server with the authentication mode
+// and returns its address and the calls it authenticated
+func startSecureServer(t *testing.T, certs testCerts, mode string) (string, func() []recordedCall) {
+	t.Helper()
+
+	identities := filepath.Join(t.TempDir(), "identities.yaml")
+	if err := os.WriteFile(identities, []byte("- name: "+testClientName+"\n  roles: [viewer]\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := &config.GRPCConfig{
+		TLS: config.TLSConfig{
+			Enabled:  true,
+			CertFile: certs.serverCert,
+			CertKey:  certs.serverKey,
+			CAFile:   certs.ca,
+		},
+		Auth: config.AuthConfig{
+			Mode:               mode,
+			CertIdentitiesFile: identities,
+			AuthKey:            "auth-key",
+			SignKey:            "sign-key",
+			Audience:           client.DefaultAudience,
+			MaxTokenTTL:        time.Hour,
+		},
+	}
+
+	certReloader, err := newCertReloader(&cfg.TLS)
+	if err != nil {
+		t.Fatal(err)
+	}
+	authenticator, err := NewAuthenticator(&cfg.Auth)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var (
+		m     sync.Mutex
+		calls []recordedCall
+	)
+	// the chained interceptor runs after the authentication
+	record := func(ctx context.Context, req interface{}, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		md, _ := metadata.FromIncomingContext(ctx)
+		m.Lock()
+		calls = append(calls, recordedCall{subject: subjectFromContext(ctx), token: firstOrEmpty(md.Get("token"))})
+		m.Unlock()
+		return handler(ctx, req)
+	}
+
+	w := &Wireguard{auth: authenticator, config: cfg, service: pingService{}, router: newNodeRouter(certReloader, "")}
+	server := w.addAuth(append(secureConn(certReloader), grpc.ChainUnaryInterceptor(record))...)
+	protobuf.RegisterWireguardServer(server, w)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	go func() {
+		_ = server.Serve(listener)
+	}()
+	t.Cleanup(server.Stop)
+
+	return listener.Addr().String(), func() []recordedCall {
+		m.Lock()
+		defer m.Unlock()
+		return append([]recordedCall(nil), calls...)
+	}
+}
+
+func connect(t *testing.T, endpoint string, tlsConf client.TLS, ttl time.Duration) client.WireguardClient {
+	t.Helper()
+	c, err := client.NewWireguardConnection(client.Config{
+		Endpoint: endpoint,
+		Auth: client.Auth{
+			AuthKey:  "auth-key",
+			SignKey:  "sign-key",
+			Subject:  "operator-token",
+			Roles:    []string{client.RoleViewer},
+			TokenTTL: ttl,
+		},
+		TLS: tlsConf,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = c.Close() })
+	return c
+}
+
+func TestClientMTLS(t *testing.T) {
+	certs := generateTestCerts(t)
+	endpoint, calls := startSecureServer(t, certs, config.AuthModeMTLS)
+
+	c := connect(t, endpoint, client.TLS{
+		Enabled:    true,
+		CertFile:   certs.clientCert,
+		CertKey:    certs.clientKey,
+		CaFile:     certs.ca,
+		ServerName: testServerName,
+	}, 0)
+
+	if _, err := c.Ping(context.Background(), &protobuf.EmptyRequest{}); err != nil {
+		t.Fatalf("ping over mTLS: %v", err)
+	}
+
+	recorded := calls()
+	if len(recorded) != 1 || recorded[0].subject != testClientName {
+		t.Fatalf("expected the call authenticated by the client certificate, got %+v", recorded)
+	}
+
+	// the server requires the client certificate signed by its CA
+	anonymous := connect(t, endpoint, client.TLS{Enabled: true, CaFile: certs.ca, ServerName: testServerName}, 0)
+	if _, err := anonymous.Ping(context.Background(), &protobuf.EmptyRequest{}); err == nil {
+		t.Fatal("ping without client certificate succeeded")
+	}
+}
+
+func TestClientTokenRefresh(t *testing.T) {
+	certs := generateTestCerts(t)
+	endpoint, calls := startSecureServer(t, certs, config.AuthModeJWT)
+
+	ttl := 2 * time.Second
+	c := connect(t, endpoint, client.TLS{
+		Enabled:    true,
+		CertFile:   certs.clientCert,
+		CertKey:    certs.clientKey,
+		CaFile:     certs.ca,
+		ServerName: testServerName,
+	}, ttl)
+
+	ctx := context.Background()
+	if _, err := c.Ping(ctx, &protobuf.EmptyRequest{}); err != nil {
+		t.Fatalf("first ping: %v", err)
+	}
+	if _, err := c.Ping(ctx, &protobuf.EmptyRequest{}); err != nil {
+		t.Fatalf("second ping: %v", err)
+	}
+
+	// the token is refreshed when most of its lifetime is used up
+	time.Sleep(ttl - ttl/5 + 100*time.Millisecond)
+	if _, err := c.Ping(ctx, &protobuf.EmptyRequest{}); err != nil {
+		t.Fatalf("ping after refresh: %v", err)
+	}
+
+	recorded := calls()
+	if len(recorded) != 3 {
+		t.Fatalf("expected 3 calls, got %d", len(recorded))
+	}
+	for _, call := range recorded {
+		if call.subject != "operator-token" || call.token == "" {
+			t.Fatalf("expected the calls authenticated by the token, got %+v", call)
+		}
+	}
+	if recorded[0].token != recorded[1].token {
+		t.Fatal("token was refreshed before most of its lifetime was used up")
+	}
+	if recorded[1].token == recorded[2].token {
+		t.Fatal("token was not refreshed")
+	}
+}
@@ -0,0 +1,50 @@
+package grpc
+
+import (
+	"context"
+	"github.com/cybericebox/wireguard/internal/model"
+	"github.com/cybericebox/wireguard/pkg/controller/grpc/protobuf"
+	"github.com/rs/zerolog/log"
+	"strconv"
+	"time"
+)
+
+type IWebhookService interface {
+	ReplayWebhooks(ctx context.Context, filter model.WebhookReplayFilter) (int64, error)
+}
+
+// The events of all nodes are queued in the database and delivered by any node, so the request is not forwarded
+
+func (w *Wireguard) ReplayWebhooks(ctx context.Context, request *protobuf.ReplayWebhooksRequest) (*protobuf.ReplayWebhooksResponse, error) {
+	log.Debug().Str("eventType", request.GetEventType()).Bool("undeliveredOnly", request.GetUndeliveredOnly()).Msg("Replaying webhooks")
+
+	filter := model.WebhookReplayFilter{
+		EventType:       request.GetEventType(),
+		UndeliveredOnly: request.GetUndeliveredOnly(),
+	}
+
+	if request.GetSince() > 0 {
+		filter.Since = time.Unix(request.GetSince(), 0)
+	}
+
+	if request.GetUntil() > 0 {
+		filter.Until = time.Unix(request.GetUntil(), 0)
+	}
+
+	replayed, err := w.service.ReplayWebhooks(ctx, filter)
+	w.audit(ctx, model.AuditActionReplayWebhooks, groupScope(""), map[string]string{
+		"eventType":       request.GetEventType(),
+		"since":           strconv.FormatInt(request.GetSince(), 10),
+		"until":           strconv.FormatInt(request.GetUntil(), 10),
+		"undeliveredOnly": strconv.FormatBool(request.GetUndeliveredOnly()),
+	}, replayed, err)
+	if err != nil {
+		log.Error().Err(err).Msg("Replaying webhooks")
+		return &protobuf.ReplayWebhooksResponse{}, err
+	}
+
+	log.Debug().Int64("replayed", replayed).Msg("Returning replayed webhooks")
+	return &protobuf.ReplayWebhooksResponse{
+		Replayed: replayed,
+	}, nil
+}
@@ -0,0 +1,68 @@
+package http
+
+import (
+	"encoding/json"
+	"github.com/cybericebox/wireguard/internal/model"
+	"github.com/rs/zerolog/log"
+	"net/http"
+)
+
+type (
+	healthResponse struct {
+		Status string                `json:"status"`
+		Checks []healthCheckResponse `json:"checks"`
+	}
+
+	healthCheckResponse struct {
+		Name    string `json:"name"`
+		Healthy bool   `json:"healthy"`
+		Error   string `json:"error,omitempty"`
+	}
+)
+
+// Healthz reports whether the server is alive.
+// Only a broken interface makes the server not alive, because a restart recreates it.
+func (h *Health) Healthz(w http.ResponseWriter, r *http.Request) {
+	health := h.service.CheckHealth(r.Context())
+
+	alive := true
+	for _, check := range health.Checks {
+		if check.Name == model.HealthCheckInterface && !check.Healthy {
+			alive = false
+		}
+	}
+
+	writeHealth(w, health, alive)
+}
+
+// Readyz reports whether the server is ready to serve clients, all checks have to pass
+func (h *Health) Readyz(w http.ResponseWriter, r *http.Request) {
+	health := h.service.CheckHealth(r.Context())
+
+	writeHealth(w, health, health.Ready)
+}
+
+func writeHealth(w http.ResponseWriter, health *model.Health, ok bool) {
+	response := healthResponse{
+		Status: "ok",
+		Checks: make([]healthCheckResponse, 0, len(health.Checks)),
+	}
+	for _, check := range health.Checks {
+		response.Checks = append(response.Checks, healthCheckResponse{
+			Name:    check.Name,
+			Healthy: check.Healthy,
+			Error:   check.Error,
+		})
+	}
+
+	status := http.StatusOK
+	if !ok {
+		response.Status, status = "unavailable", http.StatusServiceUnavailable
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		log.Error().Err(err).Msg("Failed to write health response")
+	}
+}
@@ -0,0 +1,42 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"github.com/cybericebox/wireguard/internal/config"
+	"github.com/cybericebox/wireguard/internal/model"
+	"net/http"
+	"time"
+)
+
+const readHeaderTimeout = 5 * time.Second
+
+type (
+	Health struct {
+		service IService
+	}
+
+	Dependencies struct {
+		Config  *config.HTTPConfig
+		Service IService
+	}
+
+	IService interface {
+		CheckHealth(ctx context.Context) *model.Health
+	}
+)
+
+// New creates the HTTP server with the health endpoints
+func New(deps Dependencies) *http.Server {
+	h := &Health{service: deps.Service}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /healthz", h.Healthz)
+	mux.HandleFunc("GET /readyz", h.Readyz)
+
+	return &http.Server{
+		Addr:              fmt.Sprintf("%s:%s", deps.Config.Host, deps.Config.Port),
+		Handler:           mux,
+		ReadHeaderTimeout: readHeaderTimeout,
+	}
+}
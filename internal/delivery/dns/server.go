@@ -0,0 +1,180 @@
+package dns
+
+import (
+	"context"
+	"github.com/cybericebox/wireguard/internal/config"
+	"github.com/cybericebox/wireguard/internal/model"
+	"github.com/cybericebox/wireguard/pkg/appError"
+	"github.com/miekg/dns"
+	"github.com/rs/zerolog/log"
+	"net"
+	"net/netip"
+)
+
+type (
+	// Server is the DNS server of the lab hostnames bound to the VPN server address.
+	// The lab hostnames are resolved by the service for the group of the querying client, other names are forwarded to the upstreams.
+	Server struct {
+		config  *config.VPNConfig
+		service IService
+		servers []*dns.Server
+		errs    chan error
+	}
+
+	// IService is the API of the service for the DNS server
+	IService interface {
+		LookupLabHost(source, name string) model.DNSLookup
+	}
+
+	Dependencies struct {
+		// Config is read when the server starts, so the server address set by the service is used
+		Config  *config.VPNConfig
+		Service IService
+	}
+)
+
+func NewServer(deps Dependencies) *Server {
+	return &Server{
+		config:  deps.Config,
+		service: deps.Service,
+	}
+}
+
+// Start serves DNS over UDP and TCP on the VPN server address.
+// The listeners are bound before it returns, so the binding error is returned, the serving errors are sent to Errors.
+func (s *Server) Start() error {
+	address := net.JoinHostPort(s.config.Address, s.config.DNS.Port)
+
+	packetConn, err := net.ListenPacket("udp", address)
+	if err != nil {
+		return appError.ErrDNS.WithError(err).WithMessage("Failed to listen DNS over UDP").WithContext("address", address).Err()
+	}
+
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		_ = packetConn.Close()
+		return appError.ErrDNS.WithError(err).WithMessage("Failed to listen DNS over TCP").WithContext("address", address).Err()
+	}
+
+	s.servers = []*dns.Server{
+		{PacketConn: packetConn, Net: "udp", Handler: s},
+		{Listener: listener, Net: "tcp", Handler: s},
+	}
+	s.errs = make(chan error, len(s.servers))
+
+	for _, server := range s.servers {
+		go func() {
+			if err := server.ActivateAndServe(); err != nil {
+				s.errs <- appError.ErrDNS.WithError(err).WithMessage("Failed to serve DNS").WithContext("network", server.Net).Err()
+			}
+		}()
+	}
+
+	log.Info().Msgf("DNS server is running at %s...\n", address)
+
+	return nil
+}
+
+// Errors returns the errors of the servers which stopped serving before Stop was called
+func (s *Server) Errors() <-chan error {
+	return s.errs
+}
+
+// Stop stops the server, in-flight queries are answered until the context is done
+func (s *Server) Stop(ctx context.Context) {
+	for _, server := range s.servers {
+		if err := server.ShutdownContext(ctx); err != nil {
+			log.Error().Err(err).Str("network", server.Net).Msg("Failed to shutdown DNS server")
+		}
+	}
+}
+
+// ServeDNS answers the lab hostnames, refuses the lab hostnames of other groups and forwards other queries
+func (s *Server) ServeDNS(w dns.ResponseWriter, r *dns.Msg) {
+	if len(r.Question) != 1 {
+		s.reply(w, r, new(dns.Msg).SetRcode(r, dns.RcodeFormatError))
+		return
+	}
+	q := r.Question[0]
+
+	source, err := netip.ParseAddrPort(w.RemoteAddr().String())
+	if err != nil {
+		log.Error().Err(err).Str("remoteAddr", w.RemoteAddr().String()).Msg("Failed to parse DNS query source")
+		s.reply(w, r, new(dns.Msg).SetRcode(r, dns.RcodeServerFailure))
+		return
+	}
+
+	lookup := s.service.LookupLabHost(source.Addr().Unmap().String(), q.Name)
+
+	switch lookup.Result {
+	case model.DNSLookupFound:
+		s.reply(w, r, s.answer(r, lookup.Addresses))
+	case model.DNSLookupForward:
+		s.reply(w, r, s.forward(r, w.LocalAddr().Network()))
+	default:
+		s.reply(w, r, new(dns.Msg).SetRcode(r, dns.RcodeRefused))
+	}
+}
+
+// answer returns the addresses of the lab hostname of the requested type, the name without them has no data
+func (s *Server) answer(r *dns.Msg, addresses []string) *dns.Msg {
+	q := r.Question[0]
+
+	m := new(dns.Msg).SetReply(r)
+	m.Authoritative = true
+
+	for _, address := range addresses {
+		ip, err := netip.ParseAddr(address)
+		if err != nil {
+			log.Error().Err(err).Str("address", address).Msg("Failed to parse lab hostname address")
+			continue
+		}
+
+		header := dns.RR_Header{Name: q.Name, Class: dns.ClassINET, Ttl: s.config.DNS.TTL}
+		switch {
+		case q.Qtype == dns.TypeA && ip.Is4():
+			header.Rrtype = dns.TypeA
+			m.Answer = append(m.Answer, &dns.A{Hdr: header, A: ip.AsSlice()})
+		case q.Qtype == dns.TypeAAAA && ip.Is6():
+			header.Rrtype = dns.TypeAAAA
+			m.Answer = append(m.Answer, &dns.AAAA{Hdr: header, AAAA: ip.AsSlice()})
+		}
+	}
+
+	return m
+}
+
+// forward returns the response of the first upstream which answers the query over the network of the query
+func (s *Server) forward(r *dns.Msg, network string) *dns.Msg {
+	client := &dns.Client{
+		Net:     network,
+		Timeout: s.config.DNS.Timeout,
+	}
+
+	for _, upstream := range s.config.DNS.Upstreams {
+		resp, _, err := client.Exchange(r, upstream)
+		if err != nil {
+			log.Debug().Err(err).Str("upstream", upstream).Str("name", r.Question[0].Name).Msg("Failed to forward DNS query")
+			continue
+		}
+		return resp
+	}
+
+	log.Error().Str("name", r.Question[0].Name).Msg("No upstream answered DNS query")
+	return new(dns.Msg).SetRcode(r, dns.RcodeServerFailure)
+}
+
+// reply writes the response, it is truncated to the size the client accepts over UDP
+func (s *Server) reply(w dns.ResponseWriter, r *dns.Msg, m *dns.Msg) {
+	if w.LocalAddr().Network() == "udp" {
+		size := dns.MinMsgSize
+		if opt := r.IsEdns0(); opt != nil {
+			size = int(opt.UDPSize())
+		}
+		m.Truncate(size)
+	}
+
+	if err := w.WriteMsg(m); err != nil {
+		log.Debug().Err(err).Msg("Failed to write DNS response")
+	}
+}
@@ -0,0 +1,86 @@
+package dns
+
+import (
+	"context"
+	"github.com/cybericebox/wireguard/internal/config"
+	"github.com/cybericebox/wireguard/internal/model"
+	"github.com/miekg/dns"
+	"net"
+	"os"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// TestMain runs the tests from the module root as the application runs,
+// the errors trim the working directory from the paths of the files they are created in
+func TestMain(m *testing.M) {
+	if err := os.Chdir("../../.."); err != nil {
+		panic(err)
+	}
+	os.Exit(m.Run())
+}
+
+// labService resolves every name to the lab address
+type labService struct{}
+
+func (labService) LookupLabHost(_, _ string) model.DNSLookup {
+	return model.DNSLookup{Result: model.DNSLookupFound, Addresses: []string{"10.0.0.5"}}
+}
+
+func newTestServer(port int) *Server {
+	return NewServer(Dependencies{
+		Config: &config.VPNConfig{
+			Address: "127.0.0.1",
+			DNS:     config.DNSConfig{Port: strconv.Itoa(port), TTL: 60, Timeout: time.Second},
+		},
+		Service: labService{},
+	})
+}
+
+func TestStartReturnsListenError(t *testing.T) {
+	taken, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer taken.Close()
+
+	if err = newTestServer(taken.LocalAddr().(*net.UDPAddr).Port).Start(); err == nil {
+		t.Fatal("server started on the taken port")
+	}
+}
+
+func TestStartServes(t *testing.T) {
+	free, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	port := free.LocalAddr().(*net.UDPAddr).Port
+	_ = free.Close()
+
+	s := newTestServer(port)
+	if err = s.Start(); err != nil {
+		t.Fatalf("start: %v", err)
+	}
+
+	for _, network := range []string{"udp", "tcp"} {
+		resp, _, err := (&dns.Client{Net: network, Timeout: time.Second}).Exchange(new(dns.Msg).SetQuestion("lab.test.", dns.TypeA), net.JoinHostPort("127.0.0.1", strconv.Itoa(port)))
+		if err != nil {
+			t.Fatalf("query over %s: %v", network, err)
+		}
+		if len(resp.Answer) != 1 {
+			t.Fatalf("got answers %v over %s, want the lab address", resp.Answer, network)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	s.Stop(ctx)
+
+	// the stopped servers do not report errors
+	select {
+	case err = <-s.Errors():
+		t.Fatalf("stopped server reported %v", err)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
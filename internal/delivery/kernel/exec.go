@@ -0,0 +1,24 @@
+package kernel
+
+import (
+	"context"
+	"os/exec"
+)
+
+// shell returns the command run by the shell, it is run in the network namespace if it is set
+func shell(namespace, command string) *exec.Cmd {
+	if namespace == "" {
+		return exec.Command("/bin/sh", "-c", command)
+	}
+
+	return exec.Command("ip", "netns", "exec", namespace, "/bin/sh", "-c", command)
+}
+
+// shellContext returns the command run by the shell which is killed when the context is done
+func shellContext(ctx context.Context, namespace, command string) *exec.Cmd {
+	if namespace == "" {
+		return exec.CommandContext(ctx, "/bin/sh", "-c", command)
+	}
+
+	return exec.CommandContext(ctx, "ip", "netns", "exec", namespace, "/bin/sh", "-c", command)
+}
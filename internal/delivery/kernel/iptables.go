@@ -0,0 +1,278 @@
+package kernel
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"github.com/cybericebox/wireguard/internal/model"
+	"github.com/cybericebox/wireguard/pkg/appError"
+	"github.com/rs/zerolog/log"
+	"strings"
+)
+
+const (
+	iptablesNat = `iptables -t nat -%s POSTROUTING -o eth+ -s %s -d %s -j MASQUERADE -m comment --comment "client %s"`
+	blockRule   = `iptables -%s FORWARD -s %s -j DROP -m comment --comment "ban client %s"`
+	forwardRule = `iptables -%s FORWARD -%s %s -j ACCEPT`
+
+	natRuleComment   = "client "
+	blockRuleComment = "ban client "
+)
+
+type (
+	// IPTables is the firewall of the kernel, it is managed with iptables.
+	// The service-owned rules are recognized by their comments.
+	IPTables struct {
+		namespace string
+	}
+
+	IPTablesDependencies struct {
+		// Namespace is the network namespace the rules are managed in, the namespace of the process is used if it is empty
+		Namespace string
+	}
+)
+
+func NewIPTables(deps IPTablesDependencies) *IPTables {
+	return &IPTables{
+		namespace: deps.Namespace,
+	}
+}
+
+func (f *IPTables) AddNATRule(id, ip, destCidr string) error {
+	command := fmt.Sprintf(iptablesNat, "A", ip, destCidr, id)
+
+	log.Debug().Str("command", command).Msg("Adding NAT rule")
+
+	if err := shell(f.namespace, command).Run(); err != nil {
+		return appError.ErrIptables.WithError(err).WithMessage("Failed to add NAT rule").WithContext("command", command).Err()
+	}
+	return nil
+}
+
+// AddNATRules adds the NAT rules with a single iptables-restore call
+func (f *IPTables) AddNATRules(rules []model.FirewallRule) error {
+	if len(rules) == 0 {
+		return nil
+	}
+
+	var input strings.Builder
+	input.WriteString("*nat\n")
+	for _, r := range rules {
+		// iptables-restore takes the rule without the table
+		input.WriteString(strings.TrimPrefix(fmt.Sprintf(iptablesNat, "A", r.Source, r.Destination, r.ID), "iptables -t nat "))
+		input.WriteString("\n")
+	}
+	input.WriteString("COMMIT\n")
+
+	log.Debug().Int("rules", len(rules)).Msg("Adding NAT rules")
+
+	cmd := shell(f.namespace, "iptables-restore --noflush")
+	cmd.Stdin = strings.NewReader(input.String())
+	if err := cmd.Run(); err != nil {
+		return appError.ErrIptables.WithError(err).WithMessage("Failed to add NAT rules").WithContext("rules", len(rules)).Err()
+	}
+	return nil
+}
+
+func (f *IPTables) DeleteNATRule(id, ip, destCidr string) error {
+	command := fmt.Sprintf(iptablesNat, "D", ip, destCidr, id)
+
+	log.Debug().Str("command", command).Msg("Deleting NAT rule")
+
+	if err := shell(f.namespace, command).Run(); err != nil {
+		return appError.ErrIptables.WithError(err).WithMessage("Failed to delete NAT rule").WithContext("command", command).Err()
+	}
+	return nil
+}
+
+func (f *IPTables) AddBlockRule(id, ip string) error {
+	command := fmt.Sprintf(blockRule, "A", ip, id)
+
+	log.Debug().Str("command", command).Msg("Adding blocking rule")
+
+	if err := shell(f.namespace, command).Run(); err != nil {
+		return appError.ErrIptables.WithError(err).WithMessage("Failed to add blocking rule").WithContext("command", command).Err()
+	}
+	return nil
+}
+
+func (f *IPTables) DeleteBlockRule(id, ip string) error {
+	command := fmt.Sprintf(blockRule, "D", ip, id)
+
+	log.Debug().Str("command", command).Msg("Deleting blocking rule")
+
+	if err := shell(f.namespace, command).Run(); err != nil {
+		return appError.ErrIptables.WithError(err).WithMessage("Failed to delete blocking rule").WithContext("command", command).Err()
+	}
+	return nil
+}
+
+// DescribeNATRule returns the command adding or deleting the NAT rule by the planned action
+func (f *IPTables) DescribeNATRule(action, id, ip, destCidr string) string {
+	return fmt.Sprintf(iptablesNat, commandFlag(action), ip, destCidr, id)
+}
+
+// DescribeBlockRule returns the command adding or deleting the blocking rule by the planned action
+func (f *IPTables) DescribeBlockRule(action, id, ip string) string {
+	return fmt.Sprintf(blockRule, commandFlag(action), ip, id)
+}
+
+func commandFlag(action string) string {
+	if action == model.PlanActionDelete {
+		return "D"
+	}
+	return "A"
+}
+
+// GetRules returns the service-owned NAT and blocking rules
+func (f *IPTables) GetRules() (*model.FirewallRules, error) {
+	return f.getRules(context.Background())
+}
+
+func (f *IPTables) getRules(ctx context.Context) (*model.FirewallRules, error) {
+	nat, err := f.listRules(ctx, "iptables -t nat -S POSTROUTING", natRuleComment)
+	if err != nil {
+		return nil, appError.ErrIptables.WithError(err).WithMessage("Failed to list NAT rules").Err()
+	}
+
+	block, err := f.listRules(ctx, "iptables -S FORWARD", blockRuleComment)
+	if err != nil {
+		return nil, appError.ErrIptables.WithError(err).WithMessage("Failed to list blocking rules").Err()
+	}
+
+	return &model.FirewallRules{
+		NAT:   nat,
+		Block: block,
+	}, nil
+}
+
+// DeleteNATRuleSpec deletes the NAT rule exactly as it is listed
+func (f *IPTables) DeleteNATRuleSpec(r model.FirewallRule) error {
+	return f.deleteRuleSpec("iptables -t nat -D", r)
+}
+
+// DeleteBlockRuleSpec deletes the blocking rule exactly as it is listed
+func (f *IPTables) DeleteBlockRuleSpec(r model.FirewallRule) error {
+	return f.deleteRuleSpec("iptables -D", r)
+}
+
+func (f *IPTables) deleteRuleSpec(prefix string, r model.FirewallRule) error {
+	command := fmt.Sprintf("%s %s", prefix, r.Spec)
+
+	log.Debug().Str("command", command).Msg("Deleting rule")
+
+	if err := shell(f.namespace, command).Run(); err != nil {
+		return appError.ErrIptables.WithError(err).WithMessage("Failed to delete rule").WithContext("command", command).Err()
+	}
+	return nil
+}
+
+// DeleteRules deletes all service-owned NAT and blocking rules with a single iptables-restore call
+func (f *IPTables) DeleteRules(ctx context.Context) error {
+	rules, err := f.getRules(ctx)
+	if err != nil {
+		return err
+	}
+	if len(rules.NAT) == 0 && len(rules.Block) == 0 {
+		return nil
+	}
+
+	var input strings.Builder
+	for _, table := range []struct {
+		name  string
+		rules []model.FirewallRule
+	}{
+		{name: "nat", rules: rules.NAT},
+		{name: "filter", rules: rules.Block},
+	} {
+		input.WriteString("*" + table.name + "\n")
+		for _, r := range table.rules {
+			// the spec is the rule as it is listed with the chain
+			input.WriteString("-D " + r.Spec + "\n")
+		}
+		input.WriteString("COMMIT\n")
+	}
+
+	log.Debug().Int("natRules", len(rules.NAT)).Int("blockRules", len(rules.Block)).Msg("Deleting rules")
+
+	cmd := shellContext(ctx, f.namespace, "iptables-restore --noflush")
+	cmd.Stdin = strings.NewReader(input.String())
+	if err = cmd.Run(); err != nil {
+		return appError.ErrIptables.WithError(err).WithMessage("Failed to delete rules").WithContext("rules", len(rules.NAT)+len(rules.Block)).Err()
+	}
+	return nil
+}
+
+// listRules returns the rules of the chain which comments start with the prefix
+func (f *IPTables) listRules(ctx context.Context, command, commentPrefix string) ([]model.FirewallRule, error) {
+	log.Debug().Str("command", command).Msg("Listing rules")
+
+	out, err := shellContext(ctx, f.namespace, command).Output()
+	if err != nil {
+		return nil, appError.ErrIptables.WithError(err).WithMessage("Failed to list rules").WithContext("command", command).Err()
+	}
+
+	result := make([]model.FirewallRule, 0)
+	scanner := bufio.NewScanner(strings.NewReader(string(out)))
+	for scanner.Scan() {
+		spec, ok := strings.CutPrefix(scanner.Text(), "-A ")
+		if !ok {
+			continue
+		}
+
+		_, comment, ok := strings.Cut(spec, `--comment "`+commentPrefix)
+		if !ok {
+			continue
+		}
+
+		id, _, _ := strings.Cut(comment, `"`)
+		r := model.FirewallRule{
+			ID:   id,
+			Spec: spec,
+		}
+
+		fields := strings.Fields(spec)
+		for i := 0; i < len(fields)-1; i++ {
+			switch fields[i] {
+			case "-s":
+				r.Source = fields[i+1]
+			case "-d":
+				r.Destination = fields[i+1]
+			}
+		}
+
+		result = append(result, r)
+	}
+
+	return result, nil
+}
+
+// EnsureForwardRules adds the interface forward rules if they do not exist
+func (f *IPTables) EnsureForwardRules() error {
+	for _, direction := range []string{"i", "o"} {
+		check := fmt.Sprintf(forwardRule, "C", direction, nic)
+		if err := shell(f.namespace, check).Run(); err == nil {
+			continue
+		}
+
+		command := fmt.Sprintf(forwardRule, "A", direction, nic)
+
+		log.Debug().Str("command", command).Msg("Adding forward rule")
+
+		if err := shell(f.namespace, command).Run(); err != nil {
+			return appError.ErrIptables.WithError(err).WithMessage("Failed to add forward rule").WithContext("command", command).Err()
+		}
+	}
+	return nil
+}
+
+// CheckForwardRules checks that the interface forward rules exist
+func (f *IPTables) CheckForwardRules() error {
+	for _, direction := range []string{"i", "o"} {
+		command := fmt.Sprintf(forwardRule, "C", direction, nic)
+		if err := shell(f.namespace, command).Run(); err != nil {
+			return appError.ErrIptables.WithError(err).WithMessage("Forward rule is missing").WithContext("command", command).Err()
+		}
+	}
+	return nil
+}
@@ -0,0 +1,194 @@
+//go:build linux
+
+package kernel_test
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"github.com/cybericebox/lib/pkg/wgKeyGen"
+	"github.com/cybericebox/wireguard/internal/config"
+	"github.com/cybericebox/wireguard/internal/delivery/kernel"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+const (
+	// netnsEnv opts in the network namespace suite, the suite is skipped unless it is set
+	netnsEnv = "WGTEST_NETNS"
+
+	// capNetAdmin is the bit of CAP_NET_ADMIN in the capability sets
+	capNetAdmin = 12
+
+	netnsAddress = "10.128.0.1/16"
+	netnsPort    = "51820"
+	// userspaceTimeout is the time wireguard-go has to create the interface
+	userspaceTimeout = 5 * time.Second
+)
+
+type (
+	// netns is the throwaway network namespace with the server interface.
+	// peerBackend and firewall are the kernel backends of the application bound to the namespace,
+	// so the real peer and iptables commands run without touching the host.
+	netns struct {
+		name        string
+		peerBackend *kernel.WireGuard
+		firewall    *kernel.IPTables
+		// userspace is set if the interface is run by wireguard-go because the kernel module is not available
+		userspace bool
+		config    *config.VPNConfig
+	}
+)
+
+// newNetns creates the namespace with the configured interface and deletes it when the test ends.
+// The test is skipped if the suite is not opted in with netnsEnv, CAP_NET_ADMIN is missing
+// or neither the kernel module nor wireguard-go can create the interface.
+func newNetns(t testing.TB) *netns {
+	t.Helper()
+
+	if os.Getenv(netnsEnv) == "" {
+		t.Skipf("network namespace suite is not enabled, set %s=1 to run it", netnsEnv)
+	}
+
+	if ok, err := hasCapability(capNetAdmin); err != nil || !ok {
+		t.Skipf("CAP_NET_ADMIN is required: %v", err)
+	}
+
+	for _, bin := range []string{"ip", "wg", "iptables", "iptables-restore"} {
+		if _, err := exec.LookPath(bin); err != nil {
+			t.Skipf("%s is required: %v", bin, err)
+		}
+	}
+
+	suffix := make([]byte, 4)
+	if _, err := rand.Read(suffix); err != nil {
+		t.Fatalf("generate namespace name: %v", err)
+	}
+
+	keyPair, err := wgKeyGen.NewKeyGenerator().NewKeyPair()
+	if err != nil {
+		t.Fatalf("generate key pair: %v", err)
+	}
+
+	n := &netns{
+		name: "wgtest-" + hex.EncodeToString(suffix),
+		config: &config.VPNConfig{
+			Address: netnsAddress,
+			Port:    netnsPort,
+			KeyPair: keyPair,
+		},
+	}
+
+	if out, err := exec.Command("ip", "netns", "add", n.name).CombinedOutput(); err != nil {
+		t.Skipf("create network namespace: %v: %s", err, out)
+	}
+	t.Cleanup(func() {
+		if out, err := exec.Command("ip", "netns", "delete", n.name).CombinedOutput(); err != nil {
+			t.Errorf("delete network namespace %s: %v: %s", n.name, err, out)
+		}
+	})
+
+	n.createInterface(t)
+
+	n.execOK(t, "ip link set up dev lo")
+	n.execOK(t, fmt.Sprintf("ip -4 address add %s dev %s", netnsAddress, config.VPNInterface))
+	n.execOK(t, fmt.Sprintf("ip link set up dev %s", config.VPNInterface))
+	n.execInput(t, fmt.Sprintf("wg set %s listen-port %s private-key /dev/stdin", config.VPNInterface, netnsPort), keyPair.PrivateKey)
+
+	n.peerBackend = kernel.NewWireGuard(kernel.WireGuardDependencies{Config: n.config, Namespace: n.name})
+	n.firewall = kernel.NewIPTables(kernel.IPTablesDependencies{Namespace: n.name})
+
+	return n
+}
+
+// createInterface creates the interface with the kernel module and falls back to wireguard-go
+func (n *netns) createInterface(t testing.TB) {
+	t.Helper()
+
+	if _, err := n.exec(fmt.Sprintf("ip link add %s type wireguard", config.VPNInterface), ""); err == nil {
+		return
+	}
+
+	if _, err := exec.LookPath("wireguard-go"); err != nil {
+		t.Skipf("neither the kernel module nor wireguard-go can create the interface: %v", err)
+	}
+
+	// wireguard-go runs in the foreground until the test ends, the interface is removed with it
+	ctx, cancel := context.WithCancel(context.Background())
+	cmd := exec.CommandContext(ctx, "ip", "netns", "exec", n.name, "wireguard-go", "-f", config.VPNInterface)
+	if err := cmd.Start(); err != nil {
+		cancel()
+		t.Skipf("start wireguard-go: %v", err)
+	}
+	t.Cleanup(func() {
+		cancel()
+		_ = cmd.Wait()
+	})
+	n.userspace = true
+
+	deadline := time.Now().Add(userspaceTimeout)
+	for {
+		if _, err := n.exec(fmt.Sprintf("ip link show dev %s", config.VPNInterface), ""); err == nil {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("wireguard-go did not create the interface in %s", userspaceTimeout)
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+}
+
+// execOK runs the shell command in the namespace and returns its output, the test fails if the command fails
+func (n *netns) execOK(t testing.TB, command string) string {
+	t.Helper()
+
+	return n.execInput(t, command, "")
+}
+
+func (n *netns) execInput(t testing.TB, command, input string) string {
+	t.Helper()
+
+	out, err := n.exec(command, input)
+	if err != nil {
+		t.Fatalf("%s: %v: %s", command, err, out)
+	}
+	return out
+}
+
+func (n *netns) exec(command, input string) (string, error) {
+	cmd := exec.Command("ip", "netns", "exec", n.name, "/bin/sh", "-c", command)
+	cmd.Stdin = strings.NewReader(input)
+	out, err := cmd.CombinedOutput()
+	return string(out), err
+}
+
+// hasCapability reports whether the capability is in the effective set of the process
+func hasCapability(capability uint) (bool, error) {
+	file, err := os.Open("/proc/self/status")
+	if err != nil {
+		return false, err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		value, ok := strings.CutPrefix(scanner.Text(), "CapEff:")
+		if !ok {
+			continue
+		}
+
+		caps, err := strconv.ParseUint(strings.TrimSpace(value), 16, 64)
+		if err != nil {
+			return false, err
+		}
+		return caps&(1<<capability) != 0, nil
+	}
+
+	return false, fmt.Errorf("effective capabilities are not listed")
+}
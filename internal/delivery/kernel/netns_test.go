@@ -0,0 +1,216 @@
+//go:build linux
+
+package kernel_test
+
+import (
+	"context"
+	"fmt"
+	"github.com/cybericebox/lib/pkg/wgKeyGen"
+	"github.com/cybericebox/wireguard/internal/config"
+	"github.com/cybericebox/wireguard/internal/model"
+	"strings"
+	"testing"
+)
+
+const netnsLabCIDR = "192.168.5.0/24"
+
+// TestKernelBackends runs the peer and firewall commands of the application in the throwaway namespace and checks
+// the resulting peers, routes and rules. It is skipped unless it is opted in with WGTEST_NETNS, see newNetns.
+func TestKernelBackends(t *testing.T) {
+	runNetnsSuite(t, newNetns(t))
+}
+
+func runNetnsSuite(t *testing.T, n *netns) {
+	keys := make([]string, 3)
+	for i := range keys {
+		keyPair, err := wgKeyGen.NewKeyGenerator().NewKeyPair()
+		if err != nil {
+			t.Fatalf("generate key pair: %v", err)
+		}
+		keys[i] = keyPair.PublicKey
+	}
+	addresses := []string{"10.128.0.2/32", "10.128.0.3/32", "10.128.0.4/32"}
+	ids := []string{"client-0", "client-1", "client-2"}
+
+	t.Run("interface", func(t *testing.T) {
+		exists, err := n.peerBackend.InterfaceExists()
+		if err != nil || !exists {
+			t.Fatalf("interface exists = %v, %v", exists, err)
+		}
+		if err = n.peerBackend.CheckInterface(); err != nil {
+			t.Fatalf("check interface: %v", err)
+		}
+		if err = n.peerBackend.CheckListenPort(); err != nil {
+			t.Fatalf("check listen port: %v", err)
+		}
+	})
+
+	t.Run("forward rules", func(t *testing.T) {
+		if err := n.firewall.CheckForwardRules(); err == nil {
+			t.Fatal("forward rules exist before they are ensured")
+		}
+		// the second call finds the existing rules and does not duplicate them
+		for i := 0; i < 2; i++ {
+			if err := n.firewall.EnsureForwardRules(); err != nil {
+				t.Fatalf("ensure forward rules: %v", err)
+			}
+		}
+		if err := n.firewall.CheckForwardRules(); err != nil {
+			t.Fatalf("check forward rules: %v", err)
+		}
+		if out := n.execOK(t, "iptables -S FORWARD"); strings.Count(out, "-i "+config.VPNInterface) != 1 {
+			t.Fatalf("forward rules are duplicated:\n%s", out)
+		}
+	})
+
+	t.Run("peers", func(t *testing.T) {
+		if err := n.peerBackend.AddPeer(addresses[0], keys[0]); err != nil {
+			t.Fatalf("add peer: %v", err)
+		}
+		// adding the existing peer replaces its route instead of failing
+		if err := n.peerBackend.AddPeer(addresses[0], keys[0]); err != nil {
+			t.Fatalf("add existing peer: %v", err)
+		}
+		if err := n.peerBackend.AddPeers([]model.Peer{
+			{PublicKey: keys[1], AllowedIPs: addresses[1]},
+			{PublicKey: keys[2], AllowedIPs: addresses[2]},
+		}); err != nil {
+			t.Fatalf("add peers: %v", err)
+		}
+
+		peers, err := n.peerBackend.GetPeers()
+		if err != nil {
+			t.Fatalf("get peers: %v", err)
+		}
+		if len(peers) != len(keys) {
+			t.Fatalf("got %d peers, want %d: %v", len(peers), len(keys), peers)
+		}
+		for i, key := range keys {
+			if p := peers[key]; p.AllowedIPs != addresses[i] || p.LatestHandshake != 0 {
+				t.Fatalf("peer %s = %+v, want allowed ips %s without handshake", key, p, addresses[i])
+			}
+			n.expectRoute(t, addresses[i], true)
+		}
+
+		if err = n.peerBackend.DeletePeer(addresses[0], keys[0]); err != nil {
+			t.Fatalf("delete peer: %v", err)
+		}
+		if err = n.peerBackend.DeleteStalePeer(peers[keys[1]]); err != nil {
+			t.Fatalf("delete stale peer: %v", err)
+		}
+
+		if peers, err = n.peerBackend.GetPeers(); err != nil {
+			t.Fatalf("get peers: %v", err)
+		}
+		if _, ok := peers[keys[2]]; len(peers) != 1 || !ok {
+			t.Fatalf("got peers %v, want only %s", peers, keys[2])
+		}
+		n.expectRoute(t, addresses[0], false)
+		n.expectRoute(t, addresses[1], false)
+		n.expectRoute(t, addresses[2], true)
+	})
+
+	t.Run("NAT rules", func(t *testing.T) {
+		if err := n.firewall.AddNATRule(ids[0], addresses[0], netnsLabCIDR); err != nil {
+			t.Fatalf("add NAT rule: %v", err)
+		}
+		if err := n.firewall.AddNATRules([]model.FirewallRule{
+			{ID: ids[1], Source: addresses[1], Destination: netnsLabCIDR},
+			{ID: ids[2], Source: addresses[2], Destination: netnsLabCIDR},
+		}); err != nil {
+			t.Fatalf("add NAT rules: %v", err)
+		}
+
+		rules := n.rules(t)
+		if len(rules.NAT) != len(ids) {
+			t.Fatalf("got %d NAT rules, want %d: %+v", len(rules.NAT), len(ids), rules.NAT)
+		}
+		for i, r := range rules.NAT {
+			if r.ID != ids[i] || r.Source != addresses[i] || r.Destination != netnsLabCIDR {
+				t.Fatalf("NAT rule %d = %+v, want client %s from %s to %s", i, r, ids[i], addresses[i], netnsLabCIDR)
+			}
+		}
+
+		if err := n.firewall.DeleteNATRule(ids[0], addresses[0], netnsLabCIDR); err != nil {
+			t.Fatalf("delete NAT rule: %v", err)
+		}
+		if err := n.firewall.DeleteNATRuleSpec(rules.NAT[1]); err != nil {
+			t.Fatalf("delete NAT rule spec: %v", err)
+		}
+		if rules = n.rules(t); len(rules.NAT) != 1 || rules.NAT[0].ID != ids[2] {
+			t.Fatalf("got NAT rules %+v, want only client %s", rules.NAT, ids[2])
+		}
+	})
+
+	t.Run("blocking rules", func(t *testing.T) {
+		for i := range ids[:2] {
+			if err := n.firewall.AddBlockRule(ids[i], addresses[i]); err != nil {
+				t.Fatalf("add blocking rule: %v", err)
+			}
+		}
+
+		rules := n.rules(t)
+		if len(rules.Block) != 2 {
+			t.Fatalf("got %d blocking rules, want 2: %+v", len(rules.Block), rules.Block)
+		}
+		for i, r := range rules.Block {
+			if r.ID != ids[i] || r.Source != addresses[i] {
+				t.Fatalf("blocking rule %d = %+v, want client %s from %s", i, r, ids[i], addresses[i])
+			}
+		}
+
+		if err := n.firewall.DeleteBlockRule(ids[0], addresses[0]); err != nil {
+			t.Fatalf("delete blocking rule: %v", err)
+		}
+		if err := n.firewall.DeleteBlockRuleSpec(rules.Block[1]); err != nil {
+			t.Fatalf("delete blocking rule spec: %v", err)
+		}
+		if rules = n.rules(t); len(rules.Block) != 0 {
+			t.Fatalf("got blocking rules %+v, want none", rules.Block)
+		}
+		// deleting the missing rule fails as the service expects
+		if err := n.firewall.DeleteBlockRule(ids[0], addresses[0]); err == nil {
+			t.Fatal("deleting the missing blocking rule succeeded")
+		}
+	})
+
+	t.Run("rules deletion", func(t *testing.T) {
+		if err := n.firewall.AddBlockRule(ids[2], addresses[2]); err != nil {
+			t.Fatalf("add blocking rule: %v", err)
+		}
+		if rules := n.rules(t); len(rules.NAT) != 1 || len(rules.Block) != 1 {
+			t.Fatalf("got rules %+v, want the NAT and blocking rules of client %s", rules, ids[2])
+		}
+
+		if err := n.firewall.DeleteRules(context.Background()); err != nil {
+			t.Fatalf("delete rules: %v", err)
+		}
+		if rules := n.rules(t); len(rules.NAT) != 0 || len(rules.Block) != 0 {
+			t.Fatalf("got rules %+v, want none", rules)
+		}
+		// the forward rules of the interface are not the rules of the clients
+		if err := n.firewall.CheckForwardRules(); err != nil {
+			t.Fatalf("check forward rules: %v", err)
+		}
+	})
+}
+
+func (n *netns) rules(t *testing.T) *model.FirewallRules {
+	t.Helper()
+
+	rules, err := n.firewall.GetRules()
+	if err != nil {
+		t.Fatalf("get rules: %v", err)
+	}
+	return rules
+}
+
+// expectRoute checks whether the route of the address through the interface exists
+func (n *netns) expectRoute(t *testing.T, address string, exists bool) {
+	t.Helper()
+
+	out := n.execOK(t, fmt.Sprintf("ip -4 route show %s dev %s", address, config.VPNInterface))
+	if (strings.TrimSpace(out) != "") != exists {
+		t.Fatalf("route of %s exists = %v, want %v", address, !exists, exists)
+	}
+}
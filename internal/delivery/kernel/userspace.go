@@ -0,0 +1,520 @@
+package kernel
+
+import (
+	"bufio"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"github.com/cybericebox/wireguard/internal/config"
+	"github.com/cybericebox/wireguard/internal/model"
+	"github.com/cybericebox/wireguard/pkg/appError"
+	"github.com/rs/zerolog/log"
+	"golang.zx2c4.com/wireguard/conn"
+	"golang.zx2c4.com/wireguard/device"
+	"golang.zx2c4.com/wireguard/tun"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const (
+	// ipForwardFile is read instead of setting the forwarding, the unprivileged process can not write it.
+	// The forwarding of the pod network namespace is enabled with the net.ipv4.ip_forward sysctl of its security context.
+	ipForwardFile = "/proc/sys/net/ipv4/ip_forward"
+	netClassDir   = "/sys/class/net"
+)
+
+type (
+	// UserspaceWireGuard is the peer backend of the embedded wireguard-go device on the TUN interface.
+	// It needs neither the kernel module nor wg and wg-quick, the addresses and routes are still managed with ip.
+	UserspaceWireGuard struct {
+		config *config.VPNConfig
+		// ipForwardFile and netClassDir are the proc and sysfs paths, they are replaced by the tests
+		ipForwardFile string
+		netClassDir   string
+
+		mutex  sync.Mutex
+		device *device.Device
+	}
+
+	UserspaceWireGuardDependencies struct {
+		// Config is read when the interface is configured, so the address and the key pair set by the service are used
+		Config *config.VPNConfig
+	}
+)
+
+func NewUserspaceWireGuard(deps UserspaceWireGuardDependencies) *UserspaceWireGuard {
+	return &UserspaceWireGuard{
+		config:        deps.Config,
+		ipForwardFile: ipForwardFile,
+		netClassDir:   netClassDir,
+	}
+}
+
+// GetPeers returns a map of peers of the interface map[publicKey]peer
+func (w *UserspaceWireGuard) GetPeers() (map[string]model.Peer, error) {
+	dev, err := w.getDevice()
+	if err != nil {
+		return nil, appError.ErrWireguard.WithError(err).WithMessage("Failed to get peers").Err()
+	}
+
+	out, err := dev.IpcGet()
+	if err != nil {
+		return nil, appError.ErrWireguard.WithError(err).WithMessage("Failed to get device config").Err()
+	}
+
+	peers := make(map[string]model.Peer)
+	var (
+		current *model.Peer
+		errs    error
+	)
+
+	// the peer is stored when the next one starts or the config ends, the fields of the kernel dump are used for missing values
+	store := func() {
+		if current == nil {
+			return
+		}
+		if current.Endpoint == "" {
+			current.Endpoint = "(none)"
+		}
+		if current.AllowedIPs == "" {
+			current.AllowedIPs = "(none)"
+		}
+		peers[current.PublicKey] = *current
+	}
+
+	scanner := bufio.NewScanner(strings.NewReader(out))
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(scanner.Text(), "=")
+		if !ok {
+			continue
+		}
+
+		if key == "public_key" {
+			store()
+
+			publicKey, err := hexToBase64(value)
+			if err != nil {
+				errs = appError.ErrWireguard.WithError(err).WithMessage("Failed to convert public key").WithContext("publicKey", value).Err()
+				current = nil
+				continue
+			}
+			current = &model.Peer{PublicKey: publicKey}
+			continue
+		}
+
+		// the device fields precede the peers
+		if current == nil {
+			continue
+		}
+
+		switch key {
+		case "endpoint":
+			current.Endpoint = value
+		case "allowed_ip":
+			if current.AllowedIPs != "" {
+				current.AllowedIPs += ","
+			}
+			current.AllowedIPs += value
+		case "last_handshake_time_sec":
+			if current.LatestHandshake, err = strconv.Atoi(value); err != nil {
+				errs = appError.ErrWireguard.WithError(err).WithMessage("Failed to convert last handshake").WithContext("lastHandshake", value).Err()
+			}
+		case "rx_bytes":
+			if current.TransferRx, err = strconv.ParseInt(value, 10, 64); err != nil {
+				errs = appError.ErrWireguard.WithError(err).WithMessage("Failed to convert received bytes").WithContext("transferRx", value).Err()
+			}
+		case "tx_bytes":
+			if current.TransferTx, err = strconv.ParseInt(value, 10, 64); err != nil {
+				errs = appError.ErrWireguard.WithError(err).WithMessage("Failed to convert sent bytes").WithContext("transferTx", value).Err()
+			}
+		}
+	}
+	store()
+
+	if errs != nil {
+		return nil, appError.ErrWireguard.WithError(errs).WithMessage("Failed to get peers").Err()
+	}
+
+	return peers, nil
+}
+
+func (w *UserspaceWireGuard) AddPeer(ip, publicKey string) error {
+	log.Debug().Msgf("Peer with publickey [ %s ] is adding to %s", publicKey, ip)
+
+	peer, err := peerConfig(model.Peer{PublicKey: publicKey, AllowedIPs: ip})
+	if err != nil {
+		return appError.ErrWireguard.WithError(err).WithMessage("Failed to add peer").Err()
+	}
+
+	if err = w.ipcSet(peer); err != nil {
+		return appError.ErrWireguard.WithError(err).WithMessage("Failed to add peer").WithContext("publicKey", publicKey).Err()
+	}
+
+	// replace the route, so adding already existing peer does not fail
+	command := fmt.Sprintf("ip -4 route replace %s dev %s", ip, nic)
+
+	log.Debug().Str("command", command).Msg("Adding route")
+
+	if err = shell("", command).Run(); err != nil {
+		return appError.ErrWireguard.WithError(err).WithMessage("Failed to add route").WithContext("command", command).Err()
+	}
+
+	return nil
+}
+
+// AddPeers adds the peers with a single device configuration and their routes with a single ip call
+func (w *UserspaceWireGuard) AddPeers(peers []model.Peer) error {
+	if len(peers) == 0 {
+		return nil
+	}
+
+	var peersConfig, routes strings.Builder
+	for _, p := range peers {
+		peer, err := peerConfig(p)
+		if err != nil {
+			return appError.ErrWireguard.WithError(err).WithMessage("Failed to add peers").Err()
+		}
+		peersConfig.WriteString(peer)
+		// replace the routes, so adding already existing peers does not fail
+		routes.WriteString(fmt.Sprintf("route replace %s dev %s\n", p.AllowedIPs, nic))
+	}
+
+	log.Debug().Int("peers", len(peers)).Msg("Adding peers")
+
+	if err := w.ipcSet(peersConfig.String()); err != nil {
+		return appError.ErrWireguard.WithError(err).WithMessage("Failed to add peers").WithContext("peers", len(peers)).Err()
+	}
+
+	log.Debug().Int("routes", len(peers)).Msg("Adding routes")
+
+	cmd := shell("", "ip -4 -batch -")
+	cmd.Stdin = strings.NewReader(routes.String())
+	if err := cmd.Run(); err != nil {
+		return appError.ErrWireguard.WithError(err).WithMessage("Failed to add routes").WithContext("routes", len(peers)).Err()
+	}
+
+	return nil
+}
+
+func (w *UserspaceWireGuard) DeletePeer(ip, publicKey string) error {
+	log.Debug().Msgf("Peer with publickey [ %s ] is deleting from %s", publicKey, ip)
+
+	if err := w.removePeer(publicKey); err != nil {
+		return appError.ErrWireguard.WithError(err).WithMessage("Failed to delete peer").WithContext("publicKey", publicKey).Err()
+	}
+
+	command := fmt.Sprintf("ip -4 route delete %s dev %s", ip, nic)
+
+	log.Debug().Str("command", command).Msg("Deleting route")
+
+	if err := shell("", command).Run(); err != nil {
+		return appError.ErrWireguard.WithError(err).WithMessage("Failed to delete route").WithContext("command", command).Err()
+	}
+
+	return nil
+}
+
+// DeleteStalePeer deletes the peer which is not owned by any client together with its routes
+func (w *UserspaceWireGuard) DeleteStalePeer(p model.Peer) error {
+	log.Debug().Str("publicKey", p.PublicKey).Msg("Deleting stale peer")
+
+	if err := w.removePeer(p.PublicKey); err != nil {
+		return appError.ErrWireguard.WithError(err).WithMessage("Failed to delete stale peer").WithContext("publicKey", p.PublicKey).Err()
+	}
+
+	// peer without allowed ips has no routes
+	if p.AllowedIPs == "(none)" {
+		return nil
+	}
+
+	for _, ip := range strings.Split(p.AllowedIPs, ",") {
+		command := fmt.Sprintf("ip -4 route delete %s dev %s", ip, nic)
+
+		log.Debug().Str("command", command).Msg("Deleting stale route")
+
+		if err := shell("", command).Run(); err != nil {
+			return appError.ErrWireguard.WithError(err).WithMessage("Failed to delete stale route").WithContext("command", command).Err()
+		}
+	}
+
+	return nil
+}
+
+// AdoptInterface reconfigures the running device instead of creating a new one
+func (w *UserspaceWireGuard) AdoptInterface() error {
+	if err := w.configureInterface(); err != nil {
+		return appError.ErrWireguard.WithError(err).WithMessage("Failed to configure interface").Err()
+	}
+
+	return nil
+}
+
+// CreateInterface creates the TUN interface and runs the wireguard-go device on it
+func (w *UserspaceWireGuard) CreateInterface() error {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	if w.device != nil {
+		return appError.ErrWireguard.WithMessage("Device is already running").WithContext("interface", nic).Err()
+	}
+
+	log.Info().Str("interface", nic).Msg("Userspace interface is called to be up")
+
+	if err := w.checkForwarding(); err != nil {
+		return err
+	}
+
+	if err := w.deleteLeftoverInterface(); err != nil {
+		return err
+	}
+
+	tunDevice, err := tun.CreateTUN(nic, device.DefaultMTU)
+	if err != nil {
+		return appError.ErrWireguard.WithError(err).WithMessage("Failed to create TUN interface").WithContext("interface", nic).Err()
+	}
+
+	// closing the device closes the TUN interface too
+	w.device = device.NewDevice(tunDevice, conn.NewDefaultBind(), &device.Logger{
+		Verbosef: func(format string, args ...any) {
+			log.Debug().Str("interface", nic).Msgf(format, args...)
+		},
+		Errorf: func(format string, args ...any) {
+			log.Error().Str("interface", nic).Msgf(format, args...)
+		},
+	})
+
+	if err = w.device.Up(); err != nil {
+		w.device.Close()
+		w.device = nil
+		return appError.ErrWireguard.WithError(err).WithMessage("Failed to up device").WithContext("interface", nic).Err()
+	}
+
+	// the lock is held, so the device is configured directly
+	if err = w.configureDevice(w.device); err != nil {
+		w.device.Close()
+		w.device = nil
+		return appError.ErrWireguard.WithError(err).WithMessage("Failed to configure interface").Err()
+	}
+
+	return nil
+}
+
+// DownInterface closes the device, the interface, its peers and routes are removed with it
+func (w *UserspaceWireGuard) DownInterface() error {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	log.Info().Str("interface", nic).Msg("Userspace interface is called to be down")
+
+	if w.device == nil {
+		return appError.ErrWireguard.WithMessage("Device is not running").WithContext("interface", nic).Err()
+	}
+
+	w.device.Close()
+	w.device = nil
+
+	return nil
+}
+
+// InterfaceExists reports whether the device is running, the interface left by the previous process can not be adopted
+func (w *UserspaceWireGuard) InterfaceExists() (bool, error) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	return w.device != nil, nil
+}
+
+// CheckInterface checks that the device is running and the interface is up
+func (w *UserspaceWireGuard) CheckInterface() error {
+	if _, err := w.getDevice(); err != nil {
+		return appError.ErrWireguard.WithError(err).WithMessage("Failed to check interface").Err()
+	}
+
+	data, err := os.ReadFile(filepath.Join(w.netClassDir, nic, "flags"))
+	if err != nil {
+		return appError.ErrWireguard.WithError(err).WithMessage("Failed to read interface flags").WithContext("interface", nic).Err()
+	}
+
+	flags, err := strconv.ParseUint(strings.TrimSpace(string(data)), 0, 32)
+	if err != nil {
+		return appError.ErrWireguard.WithError(err).WithMessage("Failed to parse interface flags").WithContext("interface", nic).Err()
+	}
+
+	// IFF_UP
+	if flags&0x1 == 0 {
+		return appError.ErrWireguard.WithMessage("Interface is down").WithContext("interface", nic).Err()
+	}
+
+	return nil
+}
+
+// CheckListenPort checks that the device is bound to the configured port
+func (w *UserspaceWireGuard) CheckListenPort() error {
+	dev, err := w.getDevice()
+	if err != nil {
+		return appError.ErrWireguard.WithError(err).WithMessage("Failed to get listen port").Err()
+	}
+
+	out, err := dev.IpcGet()
+	if err != nil {
+		return appError.ErrWireguard.WithError(err).WithMessage("Failed to get device config").Err()
+	}
+
+	var port string
+	scanner := bufio.NewScanner(strings.NewReader(out))
+	for scanner.Scan() {
+		if value, ok := strings.CutPrefix(scanner.Text(), "listen_port="); ok {
+			port = value
+			break
+		}
+	}
+
+	if port != w.config.Port {
+		return appError.ErrWireguard.WithMessage("Interface is not bound to the listen port").WithContext("port", port).WithContext("expected", w.config.Port).Err()
+	}
+
+	return nil
+}
+
+func (w *UserspaceWireGuard) configureInterface() error {
+	dev, err := w.getDevice()
+	if err != nil {
+		return err
+	}
+
+	return w.configureDevice(dev)
+}
+
+// configureDevice sets the key pair and the listen port of the device and the address of the interface
+func (w *UserspaceWireGuard) configureDevice(dev *device.Device) error {
+	if err := w.checkForwarding(); err != nil {
+		return err
+	}
+
+	privateKey, err := base64ToHex(w.config.KeyPair.PrivateKey)
+	if err != nil {
+		return appError.ErrWireguard.WithError(err).WithMessage("Failed to convert private key").Err()
+	}
+
+	log.Debug().Str("interface", nic).Str("port", w.config.Port).Msg("Configuring device")
+
+	if err = dev.IpcSet(fmt.Sprintf("private_key=%s\nlisten_port=%s\n", privateKey, w.config.Port)); err != nil {
+		return appError.ErrWireguard.WithError(err).WithMessage("Failed to configure device").WithContext("port", w.config.Port).Err()
+	}
+
+	for _, command := range []string{
+		fmt.Sprintf("ip -4 address replace %s dev %s", w.config.Address, nic),
+		fmt.Sprintf("ip link set up dev %s", nic),
+	} {
+		log.Debug().Str("command", command).Msg("Configuring interface")
+
+		if err = shell("", command).Run(); err != nil {
+			return appError.ErrWireguard.WithError(err).WithMessage("Failed to configure interface").WithContext("command", command).Err()
+		}
+	}
+
+	return nil
+}
+
+// checkForwarding checks that the IPv4 forwarding is enabled, the clients can not reach the laboratories without it
+func (w *UserspaceWireGuard) checkForwarding() error {
+	data, err := os.ReadFile(w.ipForwardFile)
+	if err != nil {
+		return appError.ErrWireguard.WithError(err).WithMessage("Failed to read IPv4 forwarding").WithContext("file", w.ipForwardFile).Err()
+	}
+
+	if value := strings.TrimSpace(string(data)); value != "1" {
+		return appError.ErrWireguard.WithMessage("IPv4 forwarding is disabled, set the net.ipv4.ip_forward=1 sysctl in the pod security context").WithContext("ip_forward", value).Err()
+	}
+
+	return nil
+}
+
+// deleteLeftoverInterface deletes the interface which is not run by the device, so the TUN interface can be created.
+// It is left by the kernel data plane or the previous process, the device of another process can not be adopted.
+func (w *UserspaceWireGuard) deleteLeftoverInterface() error {
+	if _, err := os.Stat(filepath.Join(w.netClassDir, nic)); err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return appError.ErrWireguard.WithError(err).WithMessage("Failed to check interface").WithContext("interface", nic).Err()
+	}
+
+	command := fmt.Sprintf("ip link delete dev %s", nic)
+
+	log.Warn().Str("interface", nic).Str("command", command).Msg("Deleting leftover interface")
+
+	if err := shell("", command).Run(); err != nil {
+		return appError.ErrWireguard.WithError(err).WithMessage("Failed to delete leftover interface").WithContext("command", command).Err()
+	}
+
+	return nil
+}
+
+func (w *UserspaceWireGuard) removePeer(publicKey string) error {
+	key, err := base64ToHex(publicKey)
+	if err != nil {
+		return appError.ErrWireguard.WithError(err).WithMessage("Failed to convert public key").WithContext("publicKey", publicKey).Err()
+	}
+
+	return w.ipcSet(fmt.Sprintf("public_key=%s\nremove=true\n", key))
+}
+
+func (w *UserspaceWireGuard) ipcSet(config string) error {
+	dev, err := w.getDevice()
+	if err != nil {
+		return err
+	}
+
+	if err = dev.IpcSet(config); err != nil {
+		return appError.ErrWireguard.WithError(err).WithMessage("Failed to configure device").Err()
+	}
+
+	return nil
+}
+
+func (w *UserspaceWireGuard) getDevice() (*device.Device, error) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	if w.device == nil {
+		return nil, appError.ErrWireguard.WithMessage("Device is not running").WithContext("interface", nic).Err()
+	}
+
+	return w.device, nil
+}
+
+// peerConfig returns the device configuration adding the peer or replacing its allowed ips
+func peerConfig(p model.Peer) (string, error) {
+	key, err := base64ToHex(p.PublicKey)
+	if err != nil {
+		return "", appError.ErrWireguard.WithError(err).WithMessage("Failed to convert public key").WithContext("publicKey", p.PublicKey).Err()
+	}
+
+	return fmt.Sprintf("public_key=%s\nreplace_allowed_ips=true\nallowed_ip=%s\npersistent_keepalive_interval=%d\n", key, p.AllowedIPs, keepalive), nil
+}
+
+// base64ToHex converts the key from the wg format to the format of the device configuration
+func base64ToHex(key string) (string, error) {
+	data, err := base64.StdEncoding.DecodeString(key)
+	if err != nil {
+		return "", err
+	}
+	if len(data) != device.NoisePublicKeySize {
+		return "", fmt.Errorf("invalid key length %d", len(data))
+	}
+
+	return hex.EncodeToString(data), nil
+}
+
+func hexToBase64(key string) (string, error) {
+	data, err := hex.DecodeString(key)
+	if err != nil {
+		return "", err
+	}
+
+	return base64.StdEncoding.EncodeToString(data), nil
+}
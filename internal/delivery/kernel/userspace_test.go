@@ -0,0 +1,141 @@
+package kernel
+
+import (
+	"github.com/cybericebox/lib/pkg/wgKeyGen"
+	"github.com/cybericebox/wireguard/internal/config"
+	"github.com/cybericebox/wireguard/internal/model"
+	"golang.zx2c4.com/wireguard/conn"
+	"golang.zx2c4.com/wireguard/device"
+	"golang.zx2c4.com/wireguard/tun/tuntest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// newTestUserspace returns the backend reading the forwarding of the value and the interfaces of the temporary directory
+func newTestUserspace(t *testing.T, ipForward string) *UserspaceWireGuard {
+	t.Helper()
+
+	dir := t.TempDir()
+	w := NewUserspaceWireGuard(UserspaceWireGuardDependencies{Config: &config.VPNConfig{
+		Address: "10.128.0.1/16",
+		Port:    "51820",
+		KeyPair: newTestKeyPair(t),
+	}})
+	w.ipForwardFile = filepath.Join(dir, "ip_forward")
+	w.netClassDir = filepath.Join(dir, "net")
+
+	if err := os.Mkdir(w.netClassDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if ipForward != "" {
+		if err := os.WriteFile(w.ipForwardFile, []byte(ipForward+"\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return w
+}
+
+func newTestKeyPair(t *testing.T) *wgKeyGen.KeyPair {
+	t.Helper()
+
+	keyPair, err := wgKeyGen.NewKeyGenerator().NewKeyPair()
+	if err != nil {
+		t.Fatalf("generate key pair: %v", err)
+	}
+	return keyPair
+}
+
+func TestUserspaceCheckForwarding(t *testing.T) {
+	for name, tc := range map[string]struct {
+		ipForward string
+		ok        bool
+	}{
+		"enabled":  {ipForward: "1", ok: true},
+		"disabled": {ipForward: "0"},
+		"missing":  {},
+	} {
+		t.Run(name, func(t *testing.T) {
+			if err := newTestUserspace(t, tc.ipForward).checkForwarding(); (err == nil) != tc.ok {
+				t.Fatalf("check forwarding = %v, want ok %t", err, tc.ok)
+			}
+		})
+	}
+}
+
+func TestUserspaceCreateInterfaceRequiresForwarding(t *testing.T) {
+	w := newTestUserspace(t, "0")
+
+	// the interface is not created, so the check does not need the TUN device
+	if err := w.CreateInterface(); err == nil {
+		t.Fatal("interface was created with the forwarding disabled")
+	}
+	if exists, _ := w.InterfaceExists(); exists {
+		t.Fatal("device is running after the failed creation")
+	}
+}
+
+func TestUserspaceDeleteLeftoverInterfaceWithoutInterface(t *testing.T) {
+	if err := newTestUserspace(t, "1").deleteLeftoverInterface(); err != nil {
+		t.Fatalf("delete missing interface: %v", err)
+	}
+}
+
+func TestUserspacePeers(t *testing.T) {
+	w := newTestUserspace(t, "1")
+
+	// the device on the channel TUN is configured without the interface, it is not up, so it binds no port
+	w.device = device.NewDevice(tuntest.NewChannelTUN().TUN(), conn.NewDefaultBind(), device.NewLogger(device.LogLevelSilent, ""))
+	t.Cleanup(w.device.Close)
+
+	keys := []string{newTestKeyPair(t).PublicKey, newTestKeyPair(t).PublicKey}
+	var peersConfig string
+	for i, key := range keys {
+		peer, err := peerConfig(model.Peer{PublicKey: key, AllowedIPs: []string{"10.128.0.2/32", "10.128.0.3/32"}[i]})
+		if err != nil {
+			t.Fatalf("peer config: %v", err)
+		}
+		peersConfig += peer
+	}
+	if err := w.ipcSet(peersConfig); err != nil {
+		t.Fatalf("add peers: %v", err)
+	}
+
+	peers, err := w.GetPeers()
+	if err != nil {
+		t.Fatalf("get peers: %v", err)
+	}
+	if len(peers) != 2 {
+		t.Fatalf("got %d peers, want 2: %v", len(peers), peers)
+	}
+	if p := peers[keys[0]]; p.AllowedIPs != "10.128.0.2/32" || p.Endpoint != "(none)" || p.LatestHandshake != 0 {
+		t.Fatalf("unexpected peer %+v", p)
+	}
+
+	if err = w.removePeer(keys[0]); err != nil {
+		t.Fatalf("remove peer: %v", err)
+	}
+	if peers, err = w.GetPeers(); err != nil {
+		t.Fatalf("get peers: %v", err)
+	}
+	if _, ok := peers[keys[1]]; len(peers) != 1 || !ok {
+		t.Fatalf("got peers %v, want only %s", peers, keys[1])
+	}
+}
+
+func TestKeyConversion(t *testing.T) {
+	key := newTestKeyPair(t).PublicKey
+
+	hexKey, err := base64ToHex(key)
+	if err != nil {
+		t.Fatalf("convert key: %v", err)
+	}
+	if back, err := hexToBase64(hexKey); err != nil || back != key {
+		t.Fatalf("converted key back = %q, %v, want %q", back, err, key)
+	}
+
+	if _, err = base64ToHex("c2hvcnQ="); err == nil {
+		t.Fatal("short key was converted")
+	}
+}
@@ -0,0 +1,396 @@
+package kernel
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"github.com/cybericebox/wireguard/internal/config"
+	"github.com/cybericebox/wireguard/internal/model"
+	"github.com/cybericebox/wireguard/pkg/appError"
+	"github.com/rs/zerolog/log"
+	"io"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+const (
+	wgQuickBin           = "wg-quick"
+	wgManageBin          = "wg"
+	nic                  = config.VPNInterface
+	configPath           = "/etc/wireguard"
+	keepalive            = 25
+	serverConfigTemplate = `[Interface]
+Address = {{.Address}}
+ListenPort = {{.Port}}
+PrivateKey = {{.KeyPair.PrivateKey}}
+SaveConfig = true
+
+PostUp = iptables -A FORWARD -i %i -j ACCEPT; iptables -A FORWARD -o %i -j ACCEPT;
+PostUp = sysctl -w -q net.ipv4.ip_forward=1;
+PostDown = iptables -D FORWARD -i %i -j ACCEPT; iptables -D FORWARD -o %i -j ACCEPT;
+PostDown = sysctl -w -q net.ipv4.ip_forward=0;`
+)
+
+type (
+	// WireGuard is the peer backend of the kernel module, it is managed with wg, wg-quick and ip
+	WireGuard struct {
+		config    *config.VPNConfig
+		namespace string
+	}
+
+	WireGuardDependencies struct {
+		// Config is read when the interface is configured, so the address and the key pair set by the service are used
+		Config *config.VPNConfig
+		// Namespace is the network namespace the interface is managed in, the namespace of the process is used if it is empty
+		Namespace string
+	}
+)
+
+func NewWireGuard(deps WireGuardDependencies) *WireGuard {
+	return &WireGuard{
+		config:    deps.Config,
+		namespace: deps.Namespace,
+	}
+}
+
+// GetPeers returns a map of peers of the interface map[publicKey]peer
+func (w *WireGuard) GetPeers() (map[string]model.Peer, error) {
+	command := fmt.Sprintf("%s show %s dump", wgManageBin, nic)
+
+	log.Debug().Str("command", command).Msg("Getting peers")
+
+	out, err := shell(w.namespace, command).Output()
+	if err != nil {
+		return nil, appError.ErrWireguard.WithError(err).WithMessage("Failed to get peers").WithContext("command", command).Err()
+	}
+
+	peers := make(map[string]model.Peer)
+	var errs error
+
+	for _, line := range strings.Split(string(out), "\n")[1:] {
+		parts := strings.Fields(line)
+		if len(parts) != 8 {
+			continue
+		}
+
+		lastHandshake, err := strconv.Atoi(parts[4])
+		if err != nil {
+			errs = appError.ErrWireguard.WithError(err).WithMessage("Failed to convert last handshake").WithContext("lastHandshake", parts[4]).Err()
+			continue
+		}
+		transferRx, err := strconv.ParseInt(parts[5], 10, 64)
+		if err != nil {
+			errs = appError.ErrWireguard.WithError(err).WithMessage("Failed to convert received bytes").WithContext("transferRx", parts[5]).Err()
+			continue
+		}
+
+		transferTx, err := strconv.ParseInt(parts[6], 10, 64)
+		if err != nil {
+			errs = appError.ErrWireguard.WithError(err).WithMessage("Failed to convert sent bytes").WithContext("transferTx", parts[6]).Err()
+			continue
+		}
+
+		peers[parts[0]] = model.Peer{
+			PublicKey:       parts[0],
+			Endpoint:        parts[2],
+			AllowedIPs:      parts[3],
+			LatestHandshake: lastHandshake,
+			TransferRx:      transferRx,
+			TransferTx:      transferTx,
+		}
+	}
+
+	if errs != nil {
+		return nil, appError.ErrWireguard.WithError(errs).WithMessage("Failed to get peers").Err()
+	}
+
+	return peers, nil
+}
+
+func (w *WireGuard) AddPeer(ip, publicKey string) error {
+	log.Debug().Msgf("Peer with publickey [ %s ] is adding to %s", publicKey, ip)
+
+	command := fmt.Sprintf("%s set %s peer %s persistent-keepalive %d allowed-ips %s", wgManageBin, nic, publicKey, keepalive, ip)
+
+	log.Debug().Str("command", command).Msg("Adding peer")
+
+	if err := shell(w.namespace, command).Run(); err != nil {
+		return appError.ErrWireguard.WithError(err).WithMessage("Failed to add peer").WithContext("command", command).Err()
+	}
+
+	// replace the route, so adding already existing peer does not fail
+	command = fmt.Sprintf("ip -4 route replace %s dev %s", ip, nic)
+
+	log.Debug().Str("command", command).Msg("Adding route")
+
+	if err := shell(w.namespace, command).Run(); err != nil {
+		return appError.ErrWireguard.WithError(err).WithMessage("Failed to add route").WithContext("command", command).Err()
+	}
+
+	return nil
+}
+
+// AddPeers adds the peers with a single wg call and their routes with a single ip call
+func (w *WireGuard) AddPeers(peers []model.Peer) error {
+	if len(peers) == 0 {
+		return nil
+	}
+
+	var command, routes strings.Builder
+	command.WriteString(fmt.Sprintf("%s set %s", wgManageBin, nic))
+	for _, p := range peers {
+		command.WriteString(fmt.Sprintf(" peer %s persistent-keepalive %d allowed-ips %s", p.PublicKey, keepalive, p.AllowedIPs))
+		// replace the routes, so adding already existing peers does not fail
+		routes.WriteString(fmt.Sprintf("route replace %s dev %s\n", p.AllowedIPs, nic))
+	}
+
+	log.Debug().Str("command", command.String()).Int("peers", len(peers)).Msg("Adding peers")
+
+	if err := shell(w.namespace, command.String()).Run(); err != nil {
+		return appError.ErrWireguard.WithError(err).WithMessage("Failed to add peers").WithContext("peers", len(peers)).Err()
+	}
+
+	log.Debug().Int("routes", len(peers)).Msg("Adding routes")
+
+	cmd := shell(w.namespace, "ip -4 -batch -")
+	cmd.Stdin = strings.NewReader(routes.String())
+	if err := cmd.Run(); err != nil {
+		return appError.ErrWireguard.WithError(err).WithMessage("Failed to add routes").WithContext("routes", len(peers)).Err()
+	}
+
+	return nil
+}
+
+func (w *WireGuard) DeletePeer(ip, publicKey string) error {
+	log.Debug().Msgf("Peer with publickey [ %s ] is deleting from %s", publicKey, ip)
+
+	command := fmt.Sprintf("%s set %s peer %s remove", wgManageBin, nic, publicKey)
+
+	log.Debug().Str("command", command).Msg("Deleting peer")
+
+	if err := shell(w.namespace, command).Run(); err != nil {
+		return appError.ErrWireguard.WithError(err).WithMessage("Failed to delete peer").WithContext("command", command).Err()
+	}
+
+	command = fmt.Sprintf("ip -4 route delete %s dev %s", ip, nic)
+
+	log.Debug().Str("command", command).Msg("Deleting route")
+
+	if err := shell(w.namespace, command).Run(); err != nil {
+		return appError.ErrWireguard.WithError(err).WithMessage("Failed to delete route").WithContext("command", command).Err()
+	}
+
+	return nil
+}
+
+// DeleteStalePeer deletes the peer which is not owned by any client together with its routes
+func (w *WireGuard) DeleteStalePeer(p model.Peer) error {
+	command := fmt.Sprintf("%s set %s peer %s remove", wgManageBin, nic, p.PublicKey)
+
+	log.Debug().Str("command", command).Msg("Deleting stale peer")
+
+	if err := shell(w.namespace, command).Run(); err != nil {
+		return appError.ErrWireguard.WithError(err).WithMessage("Failed to delete stale peer").WithContext("command", command).Err()
+	}
+
+	// peer without allowed ips has no routes
+	if p.AllowedIPs == "(none)" {
+		return nil
+	}
+
+	for _, ip := range strings.Split(p.AllowedIPs, ",") {
+		command = fmt.Sprintf("ip -4 route delete %s dev %s", ip, nic)
+
+		log.Debug().Str("command", command).Msg("Deleting stale route")
+
+		if err := shell(w.namespace, command).Run(); err != nil {
+			return appError.ErrWireguard.WithError(err).WithMessage("Failed to delete stale route").WithContext("command", command).Err()
+		}
+	}
+
+	return nil
+}
+
+func (w *WireGuard) createServerConfig() error {
+	config, err := w.generateServerConfig()
+	if err != nil {
+		return appError.ErrWireguard.WithError(err).WithMessage("Failed to generate server config").Err()
+	}
+
+	if err = writeToFile(fmt.Sprintf("%s/%s.conf", configPath, nic), config); err != nil {
+		return appError.ErrWireguard.WithError(err).WithMessage("Failed to write server config").Err()
+	}
+
+	return nil
+}
+
+// AdoptInterface reconfigures already existing interface instead of creating a new one
+func (w *WireGuard) AdoptInterface() error {
+	if err := w.createServerConfig(); err != nil {
+		return appError.ErrWireguard.WithError(err).WithMessage("Failed to create server config").Err()
+	}
+
+	command := fmt.Sprintf("%s set %s listen-port %s private-key /dev/stdin", wgManageBin, nic, w.config.Port)
+
+	log.Debug().Str("command", command).Msg("Configuring interface")
+
+	cmd := shell(w.namespace, command)
+	cmd.Stdin = strings.NewReader(w.config.KeyPair.PrivateKey)
+	if err := cmd.Run(); err != nil {
+		return appError.ErrWireguard.WithError(err).WithMessage("Failed to configure interface").WithContext("command", command).Err()
+	}
+
+	for _, command = range []string{
+		fmt.Sprintf("ip -4 address replace %s dev %s", w.config.Address, nic),
+		fmt.Sprintf("ip link set up dev %s", nic),
+		"sysctl -w -q net.ipv4.ip_forward=1",
+	} {
+		log.Debug().Str("command", command).Msg("Configuring interface")
+
+		if err := shell(w.namespace, command).Run(); err != nil {
+			return appError.ErrWireguard.WithError(err).WithMessage("Failed to configure interface").WithContext("command", command).Err()
+		}
+	}
+
+	return nil
+}
+
+// CreateInterface writes the server config and brings the interface up with wg-quick
+func (w *WireGuard) CreateInterface() error {
+	if err := w.createServerConfig(); err != nil {
+		return appError.ErrWireguard.WithError(err).WithMessage("Failed to create server").Err()
+	}
+
+	if err := w.upInterface(); err != nil {
+		return appError.ErrWireguard.WithError(err).WithMessage("Failed to up interface").Err()
+	}
+
+	return nil
+}
+
+func (w *WireGuard) generateServerConfig() (string, error) {
+	var tpl bytes.Buffer
+
+	t, err := template.New("config").Parse(serverConfigTemplate)
+	if err != nil {
+		return "", appError.ErrWireguard.WithError(err).WithMessage("Failed to parse template").Err()
+	}
+
+	if err = t.Execute(&tpl, w.config); err != nil {
+		return "", appError.ErrWireguard.WithError(err).WithMessage("Failed to execute template").Err()
+	}
+
+	return tpl.String(), nil
+}
+
+// DownInterface brings the interface down with wg-quick, the peers and routes are removed with it
+func (w *WireGuard) DownInterface() error {
+	command := wgQuickBin + " down " + nic
+
+	log.Info().Str("interface", nic).Msg("Interface is called to be down")
+
+	if err := shell(w.namespace, command).Run(); err != nil {
+		return appError.ErrWireguard.WithError(err).WithMessage("Failed to down interface").WithContext("interface", nic).Err()
+	}
+
+	return nil
+}
+
+func (w *WireGuard) InterfaceExists() (bool, error) {
+	_, err := w.readSysFile(fmt.Sprintf("/sys/class/net/%s/flags", nic))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, appError.ErrWireguard.WithError(err).WithMessage("Failed to check interface").WithContext("interface", nic).Err()
+	}
+
+	return true, nil
+}
+
+// CheckInterface checks that the interface exists and it is up
+func (w *WireGuard) CheckInterface() error {
+	data, err := w.readSysFile(fmt.Sprintf("/sys/class/net/%s/flags", nic))
+	if err != nil {
+		return appError.ErrWireguard.WithError(err).WithMessage("Failed to read interface flags").WithContext("interface", nic).Err()
+	}
+
+	flags, err := strconv.ParseUint(strings.TrimSpace(string(data)), 0, 32)
+	if err != nil {
+		return appError.ErrWireguard.WithError(err).WithMessage("Failed to parse interface flags").WithContext("interface", nic).Err()
+	}
+
+	// IFF_UP
+	if flags&0x1 == 0 {
+		return appError.ErrWireguard.WithMessage("Interface is down").WithContext("interface", nic).Err()
+	}
+
+	return nil
+}
+
+// CheckListenPort checks that the interface is bound to the configured port
+func (w *WireGuard) CheckListenPort() error {
+	command := fmt.Sprintf("%s show %s listen-port", wgManageBin, nic)
+
+	out, err := shell(w.namespace, command).Output()
+	if err != nil {
+		return appError.ErrWireguard.WithError(err).WithMessage("Failed to get listen port").WithContext("command", command).Err()
+	}
+
+	if port := strings.TrimSpace(string(out)); port != w.config.Port {
+		return appError.ErrWireguard.WithMessage("Interface is not bound to the listen port").WithContext("port", port).WithContext("expected", w.config.Port).Err()
+	}
+
+	return nil
+}
+
+// readSysFile reads the sysfs file of the namespace, ip netns exec mounts the sysfs of the namespace for the command
+func (w *WireGuard) readSysFile(path string) ([]byte, error) {
+	if w.namespace == "" {
+		return os.ReadFile(path)
+	}
+
+	data, err := shell(w.namespace, fmt.Sprintf("test -e %[1]s || exit 2; cat %[1]s", path)).Output()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() == 2 {
+		return nil, os.ErrNotExist
+	}
+	return data, err
+}
+
+func writeToFile(filename string, data string) error {
+	file, err := os.Create(filename)
+	if err != nil {
+		return appError.ErrWireguard.WithError(err).WithMessage("Failed to create file").WithContext("filename", filename).Err()
+	}
+	defer func() {
+		if err = file.Close(); err != nil {
+			log.Fatal().Err(err).Msg("Failed to close file")
+		}
+	}()
+
+	if _, err = io.WriteString(file, data); err != nil {
+		return appError.ErrWireguard.WithError(err).WithMessage("Failed to write to file").WithContext("filename", filename).Err()
+	}
+
+	if err = file.Sync(); err != nil {
+		return appError.ErrWireguard.WithError(err).WithMessage("Failed to sync file").WithContext("filename", filename).Err()
+	}
+
+	return nil
+}
+
+func (w *WireGuard) upInterface() error {
+	command := wgQuickBin + " up " + nic
+
+	log.Info().Str("interface", nic).Msg("Interface is called to be up")
+
+	if err := shell(w.namespace, command).Run(); err != nil {
+		return appError.ErrWireguard.WithError(err).WithMessage("Failed to up interface").WithContext("interface", nic).Err()
+	}
+
+	return nil
+}
@@ -0,0 +1,28 @@
+package repository
+
+import (
+	"context"
+	"github.com/cybericebox/lib/pkg/ipam"
+	"github.com/cybericebox/wireguard/internal/delivery/repository/postgres"
+	"github.com/cybericebox/wireguard/pkg/appError"
+)
+
+// postgresIPAManager is the IPAM of the postgres driver, the library acquires the addresses one at a time,
+// so the addresses of the batch are acquired by the repository in a single query on the prefix of the library
+type postgresIPAManager struct {
+	*ipam.IPAManager
+	repository *postgres.PostgresRepository
+	cidr       string
+}
+
+func (m postgresIPAManager) AcquireIPs(ctx context.Context, count int) ([]string, error) {
+	ips, err := m.repository.AcquirePrefixIPs(ctx, m.cidr, count)
+	if err != nil {
+		return nil, appError.ErrIPAM.WithWrappedError(appError.ErrPostgres.WithError(err)).WithMessage("Failed to acquire IPs").Err()
+	}
+
+	if len(ips) < count {
+		return ips, appError.ErrIPAMNoFreeIP.WithContext("cidr", m.cidr).Err()
+	}
+	return ips, nil
+}
@@ -0,0 +1,118 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.25.0
+// source: audit_log.sql
+
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/gofrs/uuid"
+)
+
+const createAuditLogEntry = `-- name: CreateAuditLogEntry :exec
+insert into audit_log (subject, action, user_id, group_id, parameters, outcome, error, affected, node_id)
+values ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+`
+
+type CreateAuditLogEntryParams struct {
+	Subject    string        `json:"subject"`
+	Action     string        `json:"action"`
+	UserID     uuid.NullUUID `json:"user_id"`
+	GroupID    uuid.NullUUID `json:"group_id"`
+	Parameters []byte        `json:"parameters"`
+	Outcome    string        `json:"outcome"`
+	Error      string        `json:"error"`
+	Affected   int64         `json:"affected"`
+	NodeID     string        `json:"node_id"`
+}
+
+func (q *Queries) CreateAuditLogEntry(ctx context.Context, arg CreateAuditLogEntryParams) error {
+	_, err := q.db.Exec(ctx, createAuditLogEntry,
+		arg.Subject,
+		arg.Action,
+		arg.UserID,
+		arg.GroupID,
+		arg.Parameters,
+		arg.Outcome,
+		arg.Error,
+		arg.Affected,
+		arg.NodeID,
+	)
+	return err
+}
+
+const getAuditLog = `-- name: GetAuditLog :many
+select id,
+       subject,
+       action,
+       user_id,
+       group_id,
+       parameters,
+       outcome,
+       error,
+       affected,
+       node_id,
+       created_at
+from audit_log
+where ($1::text = '' or subject = $1::text)
+  and ($2::text = '' or action = $2::text)
+  and ($3::uuid is null or user_id = $3::uuid)
+  and ($4::uuid is null or group_id = $4::uuid)
+  and created_at >= $5
+  and created_at <= $6
+order by created_at desc, id desc
+limit $7
+`
+
+type GetAuditLogParams struct {
+	Subject  string        `json:"subject"`
+	Action   string        `json:"action"`
+	UserID   uuid.NullUUID `json:"user_id"`
+	GroupID  uuid.NullUUID `json:"group_id"`
+	Since    time.Time     `json:"since"`
+	Until    time.Time     `json:"until"`
+	RowLimit int32         `json:"row_limit"`
+}
+
+func (q *Queries) GetAuditLog(ctx context.Context, arg GetAuditLogParams) ([]AuditLog, error) {
+	rows, err := q.db.Query(ctx, getAuditLog,
+		arg.Subject,
+		arg.Action,
+		arg.UserID,
+		arg.GroupID,
+		arg.Since,
+		arg.Until,
+		arg.RowLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []AuditLog{}
+	for rows.Next() {
+		var i AuditLog
+		if err := rows.Scan(
+			&i.ID,
+			&i.Subject,
+			&i.Action,
+			&i.UserID,
+			&i.GroupID,
+			&i.Parameters,
+			&i.Outcome,
+			&i.Error,
+			&i.Affected,
+			&i.NodeID,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
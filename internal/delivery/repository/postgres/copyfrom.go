@@ -0,0 +1,48 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.25.0
+// source: copyfrom.go
+
+package postgres
+
+import (
+	"context"
+)
+
+// iteratorForCreateVpnClients implements pgx.CopyFromSource.
+type iteratorForCreateVpnClients struct {
+	rows                 []CreateVpnClientsParams
+	skippedFirstNextCall bool
+}
+
+func (r *iteratorForCreateVpnClients) Next() bool {
+	if len(r.rows) == 0 {
+		return false
+	}
+	if !r.skippedFirstNextCall {
+		r.skippedFirstNextCall = true
+		return true
+	}
+	r.rows = r.rows[1:]
+	return len(r.rows) > 0
+}
+
+func (r iteratorForCreateVpnClients) Values() ([]interface{}, error) {
+	return []interface{}{
+		r.rows[0].UserID,
+		r.rows[0].GroupID,
+		r.rows[0].IpAddress,
+		r.rows[0].PublicKey,
+		r.rows[0].PrivateKey,
+		r.rows[0].LaboratoryCidr,
+		r.rows[0].NodeID,
+	}, nil
+}
+
+func (r iteratorForCreateVpnClients) Err() error {
+	return nil
+}
+
+func (q *Queries) CreateVpnClients(ctx context.Context, arg []CreateVpnClientsParams) (int64, error) {
+	return q.db.CopyFrom(ctx, []string{"vpn_clients"}, []string{"user_id", "group_id", "ip_address", "public_key", "private_key", "laboratory_cidr", "node_id"}, &iteratorForCreateVpnClients{rows: arg})
+}
@@ -0,0 +1,48 @@
+package postgres
+
+import (
+	"context"
+)
+
+// acquirePrefixIPs marks the free addresses of the prefix as acquired and returns them.
+// The prefixes table belongs to the IPAM library, so the query is not generated from the schema of the migrations.
+// The prefix row is locked for the update and its version is raised, so the concurrent acquiring of the library is retried.
+// The network and broadcast addresses are acquired by the library with the prefix.
+const acquirePrefixIPs = `with prefix as (select cidr::cidr as network, prefix
+                from prefixes
+                where cidr = $1
+                    for update),
+     free as (select host(p.network + n) as ip
+              from prefix p,
+                   generate_series(0::bigint, (2::bigint ^ (32 - masklen(p.network)))::bigint - 1) n
+              where not coalesce(p.prefix -> 'IPs', '{}') ? host(p.network + n)
+              limit $2),
+     acquired as (update prefixes
+         set prefix = jsonb_set(jsonb_set(prefixes.prefix, '{IPs}',
+                                          coalesce(prefixes.prefix -> 'IPs', '{}') ||
+                                          (select coalesce(jsonb_object_agg(ip, true), '{}') from free)),
+                                '{Version}', to_jsonb((prefixes.prefix ->> 'Version')::bigint + 1))
+         where cidr = $1
+           and exists(select 1 from free))
+select ip
+from free`
+
+// AcquirePrefixIPs acquires the free addresses of the IPAM prefix in a single query, fewer addresses are returned if the prefix runs out of them
+func (r *PostgresRepository) AcquirePrefixIPs(ctx context.Context, cidr string, count int) ([]string, error) {
+	rows, err := r.db.Query(ctx, acquirePrefixIPs, cidr, count)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ips := make([]string, 0, count)
+	for rows.Next() {
+		var ip string
+		if err = rows.Scan(&ip); err != nil {
+			return nil, err
+		}
+		ips = append(ips, ip)
+	}
+
+	return ips, rows.Err()
+}
@@ -0,0 +1,6 @@
+drop index if exists vpn_clients_node_id_idx;
+
+alter table vpn_clients
+    drop column if exists node_id;
+
+drop table if exists vpn_nodes;
\ No newline at end of file
@@ -0,0 +1,17 @@
+create table if not exists vpn_nodes
+(
+    id            varchar(255) primary key,
+
+    endpoint      varchar(255) not null,
+    public_key    varchar(44)  not null,
+    grpc_endpoint varchar(255) not null,
+
+    heartbeat_at  timestamptz  not null default now(),
+
+    created_at    timestamptz  not null default now()
+);
+
+alter table vpn_clients
+    add column if not exists node_id varchar(255) not null default 'default';
+
+create index if not exists vpn_clients_node_id_idx on vpn_clients (node_id);
@@ -0,0 +1,24 @@
+create table if not exists audit_log
+(
+    id         bigserial primary key,
+
+    subject    varchar(255) not null,
+    action     varchar(64)  not null,
+
+    user_id    uuid,
+    group_id   uuid,
+
+    parameters jsonb        not null default '{}',
+
+    outcome    varchar(16)  not null,
+    error      text         not null default '',
+    affected   bigint       not null default 0,
+
+    node_id    varchar(255) not null,
+
+    created_at timestamptz  not null default now()
+);
+
+create index if not exists audit_log_created_at_idx on audit_log (created_at);
+create index if not exists audit_log_user_id_group_id_idx on audit_log (user_id, group_id);
+create index if not exists audit_log_group_id_idx on audit_log (group_id);
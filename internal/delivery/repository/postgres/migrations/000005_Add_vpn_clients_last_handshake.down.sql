@@ -0,0 +1,5 @@
+drop index if exists vpn_clients_created_at_idx;
+drop index if exists vpn_clients_last_handshake_at_idx;
+
+alter table vpn_clients
+    drop column if exists last_handshake_at;
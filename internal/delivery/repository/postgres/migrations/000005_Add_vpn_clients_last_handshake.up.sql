@@ -0,0 +1,5 @@
+alter table vpn_clients
+    add column if not exists last_handshake_at timestamptz;
+
+create index if not exists vpn_clients_last_handshake_at_idx on vpn_clients (last_handshake_at);
+create index if not exists vpn_clients_created_at_idx on vpn_clients (created_at);
@@ -0,0 +1,2 @@
+alter table vpn_clients
+    drop column if exists ban_reason;
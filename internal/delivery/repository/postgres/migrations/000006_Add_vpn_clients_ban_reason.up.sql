@@ -0,0 +1,2 @@
+alter table vpn_clients
+    add column if not exists ban_reason text not null default '';
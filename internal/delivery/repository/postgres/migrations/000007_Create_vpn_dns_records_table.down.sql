@@ -0,0 +1,2 @@
+drop table if exists vpn_dns_records;
+drop index if exists vpn_clients_group_id_idx;
\ No newline at end of file
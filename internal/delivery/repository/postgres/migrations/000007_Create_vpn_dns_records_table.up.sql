@@ -0,0 +1,17 @@
+create table if not exists vpn_dns_records
+(
+    id              uuid primary key,
+
+    group_id        uuid         not null,
+    name            varchar(255) not null,
+    address         inet         not null,
+    -- laboratory_cidr is the lab network of the group given with the record, the address is within it
+    laboratory_cidr cidr         not null,
+
+    created_at      timestamptz  not null default now(),
+
+    unique (group_id, name, address)
+);
+
+create index if not exists vpn_dns_records_name_idx on vpn_dns_records (name);
+create index if not exists vpn_clients_group_id_idx on vpn_clients (group_id);
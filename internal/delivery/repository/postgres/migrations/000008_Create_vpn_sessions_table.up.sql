@@ -0,0 +1,21 @@
+create table if not exists vpn_sessions
+(
+    id             bigserial primary key,
+
+    user_id        uuid         not null,
+    group_id       uuid         not null,
+    node_id        varchar(255) not null,
+
+    -- endpoint is the remote address the client peer connected from
+    endpoint       varchar(255) not null default '',
+    transfer_rx    bigint       not null default 0,
+    transfer_tx    bigint       not null default 0,
+
+    started_at     timestamptz  not null,
+    -- last_active_at is the last time the transfer of the session was sampled
+    last_active_at timestamptz  not null,
+    ended_at       timestamptz
+);
+
+create index if not exists vpn_sessions_user_id_group_id_started_at_idx on vpn_sessions (user_id, group_id, started_at);
+create index if not exists vpn_sessions_node_id_idx on vpn_sessions (node_id) where ended_at is null;
@@ -0,0 +1,21 @@
+create table if not exists vpn_webhook_deliveries
+(
+    id              bigserial primary key,
+
+    -- event_id is shared by the deliveries of the event to every URL
+    event_id        uuid         not null,
+    event_type      varchar(64)  not null,
+    url             text         not null,
+    payload         jsonb        not null,
+
+    attempts        integer      not null default 0,
+    -- next_attempt_at is also the end of the lease of the claimed delivery
+    next_attempt_at timestamptz  not null,
+    last_error      text         not null default '',
+    delivered_at    timestamptz,
+
+    created_at      timestamptz  not null default now()
+);
+
+create index if not exists vpn_webhook_deliveries_next_attempt_at_idx on vpn_webhook_deliveries (next_attempt_at) where delivered_at is null;
+create index if not exists vpn_webhook_deliveries_created_at_idx on vpn_webhook_deliveries (created_at);
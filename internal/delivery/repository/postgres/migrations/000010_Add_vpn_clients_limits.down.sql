@@ -0,0 +1,3 @@
+alter table vpn_clients
+    drop column if exists expires_at,
+    drop column if exists transfer_quota;
@@ -0,0 +1,4 @@
+alter table vpn_clients
+    -- the client is blocked when it expires or its sessions transfer the quota, zero quota is unlimited
+    add column if not exists expires_at     timestamptz,
+    add column if not exists transfer_quota bigint not null default 0;
@@ -12,14 +12,77 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+type AuditLog struct {
+	ID         int64         `json:"id"`
+	Subject    string        `json:"subject"`
+	Action     string        `json:"action"`
+	UserID     uuid.NullUUID `json:"user_id"`
+	GroupID    uuid.NullUUID `json:"group_id"`
+	Parameters []byte        `json:"parameters"`
+	Outcome    string        `json:"outcome"`
+	Error      string        `json:"error"`
+	Affected   int64         `json:"affected"`
+	NodeID     string        `json:"node_id"`
+	CreatedAt  time.Time     `json:"created_at"`
+}
+
 type VpnClient struct {
-	UserID         uuid.UUID          `json:"user_id"`
-	GroupID        uuid.UUID          `json:"group_id"`
-	IpAddress      netip.Prefix       `json:"ip_address"`
-	PublicKey      string             `json:"public_key"`
-	PrivateKey     string             `json:"private_key"`
-	LaboratoryCidr netip.Prefix       `json:"laboratory_cidr"`
-	Banned         bool               `json:"banned"`
-	UpdatedAt      pgtype.Timestamptz `json:"updated_at"`
-	CreatedAt      time.Time          `json:"created_at"`
+	UserID          uuid.UUID          `json:"user_id"`
+	GroupID         uuid.UUID          `json:"group_id"`
+	IpAddress       netip.Prefix       `json:"ip_address"`
+	PublicKey       string             `json:"public_key"`
+	PrivateKey      string             `json:"private_key"`
+	LaboratoryCidr  netip.Prefix       `json:"laboratory_cidr"`
+	Banned          bool               `json:"banned"`
+	UpdatedAt       pgtype.Timestamptz `json:"updated_at"`
+	CreatedAt       time.Time          `json:"created_at"`
+	NodeID          string             `json:"node_id"`
+	LastHandshakeAt pgtype.Timestamptz `json:"last_handshake_at"`
+	BanReason       string             `json:"ban_reason"`
+	ExpiresAt       pgtype.Timestamptz `json:"expires_at"`
+	TransferQuota   int64              `json:"transfer_quota"`
+}
+
+type VpnDnsRecord struct {
+	ID             uuid.UUID    `json:"id"`
+	GroupID        uuid.UUID    `json:"group_id"`
+	Name           string       `json:"name"`
+	Address        netip.Addr   `json:"address"`
+	LaboratoryCidr netip.Prefix `json:"laboratory_cidr"`
+	CreatedAt      time.Time    `json:"created_at"`
+}
+
+type VpnNode struct {
+	ID           string    `json:"id"`
+	Endpoint     string    `json:"endpoint"`
+	PublicKey    string    `json:"public_key"`
+	GrpcEndpoint string    `json:"grpc_endpoint"`
+	HeartbeatAt  time.Time `json:"heartbeat_at"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+type VpnSession struct {
+	ID           int64              `json:"id"`
+	UserID       uuid.UUID          `json:"user_id"`
+	GroupID      uuid.UUID          `json:"group_id"`
+	NodeID       string             `json:"node_id"`
+	Endpoint     string             `json:"endpoint"`
+	TransferRx   int64              `json:"transfer_rx"`
+	TransferTx   int64              `json:"transfer_tx"`
+	StartedAt    time.Time          `json:"started_at"`
+	LastActiveAt time.Time          `json:"last_active_at"`
+	EndedAt      pgtype.Timestamptz `json:"ended_at"`
+}
+
+type VpnWebhookDelivery struct {
+	ID            int64              `json:"id"`
+	EventID       uuid.UUID          `json:"event_id"`
+	EventType     string             `json:"event_type"`
+	Url           string             `json:"url"`
+	Payload       []byte             `json:"payload"`
+	Attempts      int32              `json:"attempts"`
+	NextAttemptAt time.Time          `json:"next_attempt_at"`
+	LastError     string             `json:"last_error"`
+	DeliveredAt   pgtype.Timestamptz `json:"delivered_at"`
+	CreatedAt     time.Time          `json:"created_at"`
 }
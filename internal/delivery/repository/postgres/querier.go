@@ -6,16 +6,52 @@ package postgres
 
 import (
 	"context"
+	"net/netip"
+	"time"
+
+	"github.com/gofrs/uuid"
 )
 
 type Querier interface {
+	ClaimVPNWebhookDeliveries(ctx context.Context, arg ClaimVPNWebhookDeliveriesParams) ([]VpnWebhookDelivery, error)
+	CreateAuditLogEntry(ctx context.Context, arg CreateAuditLogEntryParams) error
 	CreatePlatformSettings(ctx context.Context, arg CreatePlatformSettingsParams) error
+	CreateVPNDNSRecord(ctx context.Context, arg CreateVPNDNSRecordParams) (VpnDnsRecord, error)
+	CreateVPNSession(ctx context.Context, arg CreateVPNSessionParams) (int64, error)
+	CreateVPNWebhookDelivery(ctx context.Context, arg CreateVPNWebhookDeliveryParams) error
 	CreateVpnClient(ctx context.Context, arg CreateVpnClientParams) error
+	CreateVpnClients(ctx context.Context, arg []CreateVpnClientsParams) (int64, error)
 	DeleteVPNClients(ctx context.Context, arg DeleteVPNClientsParams) (int64, error)
+	DeleteVPNDNSRecord(ctx context.Context, arg DeleteVPNDNSRecordParams) (VpnDnsRecord, error)
+	EndVPNNodeSessions(ctx context.Context, nodeID string) (int64, error)
+	FailVPNWebhookDelivery(ctx context.Context, arg FailVPNWebhookDeliveryParams) error
+	GetAliveVPNNodes(ctx context.Context, heartbeatAt time.Time) ([]VpnNode, error)
+	GetAliveVPNNodesLoad(ctx context.Context, heartbeatAt time.Time) ([]GetAliveVPNNodesLoadRow, error)
+	GetAuditLog(ctx context.Context, arg GetAuditLogParams) ([]AuditLog, error)
+	GetNodeVPNClients(ctx context.Context, nodeID string) ([]VpnClient, error)
 	GetPlatformSettings(ctx context.Context, key string) ([]byte, error)
+	GetVPNClient(ctx context.Context, arg GetVPNClientParams) (VpnClient, error)
+	GetVPNClientNodeID(ctx context.Context, arg GetVPNClientNodeIDParams) (string, error)
+	GetVPNClientSessions(ctx context.Context, arg GetVPNClientSessionsParams) ([]VpnSession, error)
 	GetVPNClients(ctx context.Context) ([]VpnClient, error)
+	// the transfer of the clients with the quota is the sum of their sessions since they were created,
+	// the sessions start at the second of the handshake
+	GetVPNClientsTransfer(ctx context.Context, nodeID string) ([]GetVPNClientsTransferRow, error)
+	GetVPNClientsWithoutHandshake(ctx context.Context, arg GetVPNClientsWithoutHandshakeParams) ([]GetVPNClientsWithoutHandshakeRow, error)
+	GetVPNGroupLaboratoryCIDRs(ctx context.Context, groupID uuid.UUID) ([]netip.Prefix, error)
+	GetVPNGroupNodeID(ctx context.Context, groupID uuid.UUID) (string, error)
+	GetVPNNode(ctx context.Context, id string) (VpnNode, error)
+	ListVPNClients(ctx context.Context, arg ListVPNClientsParams) ([]ListVPNClientsRow, error)
+	ListVPNDNSRecords(ctx context.Context, groupID uuid.NullUUID) ([]VpnDnsRecord, error)
+	MarkVPNWebhookDelivered(ctx context.Context, arg MarkVPNWebhookDeliveredParams) error
+	ReplayVPNWebhookDeliveries(ctx context.Context, arg ReplayVPNWebhookDeliveriesParams) (int64, error)
 	UpdatePlatformSettings(ctx context.Context, arg UpdatePlatformSettingsParams) (int64, error)
 	UpdateVPNClientsBanStatus(ctx context.Context, arg UpdateVPNClientsBanStatusParams) (int64, error)
+	UpdateVPNClientsLastHandshake(ctx context.Context, arg UpdateVPNClientsLastHandshakeParams) (int64, error)
+	UpdateVPNClientsLimits(ctx context.Context, arg UpdateVPNClientsLimitsParams) (int64, error)
+	UpdateVPNNodeHeartbeat(ctx context.Context, id string) (int64, error)
+	UpdateVPNSession(ctx context.Context, arg UpdateVPNSessionParams) error
+	UpsertVPNNode(ctx context.Context, arg UpsertVPNNodeParams) error
 }
 
 var _ Querier = (*Queries)(nil)
@@ -0,0 +1,25 @@
+-- name: CreateAuditLogEntry :exec
+insert into audit_log (subject, action, user_id, group_id, parameters, outcome, error, affected, node_id)
+values ($1, $2, $3, $4, $5, $6, $7, $8, $9);
+
+-- name: GetAuditLog :many
+select id,
+       subject,
+       action,
+       user_id,
+       group_id,
+       parameters,
+       outcome,
+       error,
+       affected,
+       node_id,
+       created_at
+from audit_log
+where (sqlc.arg(subject)::text = '' or subject = sqlc.arg(subject)::text)
+  and (sqlc.arg(action)::text = '' or action = sqlc.arg(action)::text)
+  and (sqlc.narg(user_id)::uuid is null or user_id = sqlc.narg(user_id)::uuid)
+  and (sqlc.narg(group_id)::uuid is null or group_id = sqlc.narg(group_id)::uuid)
+  and created_at >= sqlc.arg(since)
+  and created_at <= sqlc.arg(until)
+order by created_at desc, id desc
+limit sqlc.arg(row_limit);
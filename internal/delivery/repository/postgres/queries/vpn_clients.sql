@@ -1,6 +1,10 @@
 -- name: CreateVpnClient :exec
-insert into vpn_clients (user_id, group_id, ip_address, public_key, private_key, laboratory_cidr)
-values ($1, $2, $3, $4, $5, $6);
+insert into vpn_clients (user_id, group_id, ip_address, public_key, private_key, laboratory_cidr, node_id)
+values ($1, $2, $3, $4, $5, $6, $7);
+
+-- name: CreateVpnClients :copyfrom
+insert into vpn_clients (user_id, group_id, ip_address, public_key, private_key, laboratory_cidr, node_id)
+values ($1, $2, $3, $4, $5, $6, $7);
 
 -- name: GetVPNClients :many
 select user_id,
@@ -11,18 +15,148 @@ select user_id,
        laboratory_cidr,
        banned,
        updated_at,
-       created_at
+       created_at,
+       node_id,
+       last_handshake_at,
+       ban_reason,
+       expires_at,
+       transfer_quota
 from vpn_clients;
 
+-- name: GetNodeVPNClients :many
+select user_id,
+       group_id,
+       ip_address,
+       public_key,
+       private_key,
+       laboratory_cidr,
+       banned,
+       updated_at,
+       created_at,
+       node_id,
+       last_handshake_at,
+       ban_reason,
+       expires_at,
+       transfer_quota
+from vpn_clients
+where node_id = $1;
+
+-- name: GetVPNClient :one
+select user_id,
+       group_id,
+       ip_address,
+       public_key,
+       private_key,
+       laboratory_cidr,
+       banned,
+       updated_at,
+       created_at,
+       node_id,
+       last_handshake_at,
+       ban_reason,
+       expires_at,
+       transfer_quota
+from vpn_clients
+where user_id = $1
+  and group_id = $2;
+
+-- name: GetVPNClientNodeID :one
+select node_id
+from vpn_clients
+where user_id = $1
+  and group_id = $2;
+
+-- name: GetVPNGroupNodeID :one
+select node_id
+from vpn_clients
+where group_id = $1
+group by node_id
+order by count(*) desc
+limit 1;
+
 -- name: UpdateVPNClientsBanStatus :execrows
 update vpn_clients
-set banned = $1,
+set banned     = $1,
+    ban_reason = sqlc.arg(ban_reason),
     updated_at = now()
-where user_id = coalesce(sqlc.narg(user_id), user_id)
+where node_id = $2
+  and user_id = coalesce(sqlc.narg(user_id), user_id)
+  and group_id = coalesce(sqlc.narg(group_id), group_id)
+  -- the clients already in the status keep their ban reason
+  and banned <> $1;
+
+-- name: UpdateVPNClientsLimits :execrows
+update vpn_clients
+set expires_at     = sqlc.narg(expires_at),
+    transfer_quota = sqlc.arg(transfer_quota),
+    updated_at     = now()
+where node_id = sqlc.arg(node_id)
+  and user_id = coalesce(sqlc.narg(user_id), user_id)
   and group_id = coalesce(sqlc.narg(group_id), group_id);
 
+-- name: GetVPNClientsTransfer :many
+-- the transfer of the clients with the quota is the sum of their sessions since they were created,
+-- the sessions start at the second of the handshake
+select c.user_id,
+       c.group_id,
+       coalesce(sum(s.transfer_rx + s.transfer_tx), 0)::bigint as transfer
+from vpn_clients c
+         join vpn_sessions s on s.user_id = c.user_id and s.group_id = c.group_id and s.started_at >= date_trunc('second', c.created_at)
+where c.node_id = $1
+  and c.transfer_quota > 0
+group by c.user_id, c.group_id;
+
 -- name: DeleteVPNClients :execrows
 delete
 from vpn_clients
-where user_id = coalesce(sqlc.narg(user_id), user_id)
-  and group_id = coalesce(sqlc.narg(group_id), group_id);
\ No newline at end of file
+where node_id = $1
+  and user_id = coalesce(sqlc.narg(user_id), user_id)
+  and group_id = coalesce(sqlc.narg(group_id), group_id);
+
+-- name: ListVPNClients :many
+select user_id,
+       group_id,
+       ip_address,
+       banned,
+       created_at,
+       node_id,
+       last_handshake_at,
+       ban_reason
+from vpn_clients
+where (sqlc.narg(user_id)::uuid is null or user_id = sqlc.narg(user_id)::uuid)
+  and (sqlc.narg(group_id)::uuid is null or group_id = sqlc.narg(group_id)::uuid)
+  and (sqlc.narg(banned)::bool is null or banned = sqlc.narg(banned)::bool)
+  and (sqlc.narg(online_since)::timestamptz is null or last_handshake_at >= sqlc.narg(online_since)::timestamptz)
+  and (sqlc.narg(address_cidr)::cidr is null or ip_address <<= sqlc.narg(address_cidr)::cidr)
+  and (sqlc.narg(created_after)::timestamptz is null or created_at > sqlc.narg(created_after)::timestamptz)
+order by case when sqlc.arg(sort_by)::text = 'lastSeen' and not sqlc.arg(sort_desc)::bool then last_handshake_at end nulls first,
+         case when sqlc.arg(sort_by)::text = 'lastSeen' and sqlc.arg(sort_desc)::bool then last_handshake_at end desc nulls last,
+         case when sqlc.arg(sort_by)::text = 'address' and not sqlc.arg(sort_desc)::bool then ip_address end,
+         case when sqlc.arg(sort_by)::text = 'address' and sqlc.arg(sort_desc)::bool then ip_address end desc,
+         case when sqlc.arg(sort_by)::text = 'created' and not sqlc.arg(sort_desc)::bool then created_at end,
+         case when sqlc.arg(sort_by)::text = 'created' and sqlc.arg(sort_desc)::bool then created_at end desc,
+         user_id,
+         group_id
+limit sqlc.arg(page_size) offset sqlc.arg(page_offset);
+
+-- name: UpdateVPNClientsLastHandshake :execrows
+update vpn_clients c
+set last_handshake_at = h.last_handshake_at
+from (select unnest(sqlc.arg(public_keys)::text[])              as public_key,
+             unnest(sqlc.arg(last_handshakes)::timestamptz[]) as last_handshake_at) h
+where c.node_id = sqlc.arg(node_id)
+  and c.public_key = h.public_key;
+
+-- name: GetVPNClientsWithoutHandshake :many
+select user_id,
+       group_id,
+       public_key
+from vpn_clients
+where node_id = sqlc.arg(node_id)
+  and last_handshake_at is null
+  and public_key = any (sqlc.arg(public_keys)::text[]);
+
+-- name: GetVPNGroupLaboratoryCIDRs :many
+select distinct laboratory_cidr
+from vpn_clients
+where group_id = $1;
@@ -0,0 +1,23 @@
+-- name: CreateVPNDNSRecord :one
+insert into vpn_dns_records (id, group_id, name, address, laboratory_cidr)
+values ($1, $2, $3, $4, $5)
+on conflict (group_id, name, address) do nothing
+returning id, group_id, name, address, laboratory_cidr, created_at;
+
+-- name: ListVPNDNSRecords :many
+select id,
+       group_id,
+       name,
+       address,
+       laboratory_cidr,
+       created_at
+from vpn_dns_records
+where (sqlc.narg(group_id)::uuid is null or group_id = sqlc.narg(group_id)::uuid)
+order by name, address;
+
+-- name: DeleteVPNDNSRecord :one
+delete
+from vpn_dns_records
+where id = $1
+  and group_id = $2
+returning id, group_id, name, address, laboratory_cidr, created_at;
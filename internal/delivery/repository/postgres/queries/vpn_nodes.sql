@@ -0,0 +1,43 @@
+-- name: UpsertVPNNode :exec
+insert into vpn_nodes (id, endpoint, public_key, grpc_endpoint)
+values ($1, $2, $3, $4)
+on conflict (id) do update
+    set endpoint      = excluded.endpoint,
+        public_key    = excluded.public_key,
+        grpc_endpoint = excluded.grpc_endpoint,
+        heartbeat_at  = now();
+
+-- name: UpdateVPNNodeHeartbeat :execrows
+update vpn_nodes
+set heartbeat_at = now()
+where id = $1;
+
+-- name: GetVPNNode :one
+select id,
+       endpoint,
+       public_key,
+       grpc_endpoint,
+       heartbeat_at,
+       created_at
+from vpn_nodes
+where id = $1;
+
+-- name: GetAliveVPNNodes :many
+select id,
+       endpoint,
+       public_key,
+       grpc_endpoint,
+       heartbeat_at,
+       created_at
+from vpn_nodes
+where heartbeat_at > $1
+order by id;
+
+-- name: GetAliveVPNNodesLoad :many
+select n.id,
+       count(c.user_id) as clients
+from vpn_nodes n
+         left join vpn_clients c on c.node_id = n.id
+where n.heartbeat_at > $1
+group by n.id
+order by clients, n.id;
@@ -0,0 +1,38 @@
+-- name: CreateVPNSession :one
+insert into vpn_sessions (user_id, group_id, node_id, endpoint, transfer_rx, transfer_tx, started_at, last_active_at)
+values ($1, $2, $3, $4, $5, $6, $7, $8)
+returning id;
+
+-- name: UpdateVPNSession :exec
+update vpn_sessions
+set endpoint       = $2,
+    transfer_rx    = $3,
+    transfer_tx    = $4,
+    last_active_at = $5,
+    ended_at       = $6
+where id = $1;
+
+-- name: EndVPNNodeSessions :execrows
+update vpn_sessions
+set ended_at = last_active_at
+where node_id = $1
+  and ended_at is null;
+
+-- name: GetVPNClientSessions :many
+select id,
+       user_id,
+       group_id,
+       node_id,
+       endpoint,
+       transfer_rx,
+       transfer_tx,
+       started_at,
+       last_active_at,
+       ended_at
+from vpn_sessions
+where user_id = sqlc.arg(user_id)
+  and group_id = sqlc.arg(group_id)
+  and started_at >= sqlc.arg(since)
+  and started_at <= sqlc.arg(until)
+order by started_at desc, id desc
+limit sqlc.arg(row_limit);
@@ -0,0 +1,40 @@
+-- name: CreateVPNWebhookDelivery :exec
+insert into vpn_webhook_deliveries (event_id, event_type, url, payload, next_attempt_at)
+values ($1, $2, $3, $4, now());
+
+-- name: ClaimVPNWebhookDeliveries :many
+update vpn_webhook_deliveries d
+set next_attempt_at = sqlc.arg(lease_until)
+where d.id in (select q.id
+               from vpn_webhook_deliveries q
+               where q.delivered_at is null
+                 and q.attempts < sqlc.arg(max_attempts)::integer
+                 and q.next_attempt_at <= sqlc.arg(now)
+               order by q.next_attempt_at, q.id
+               limit sqlc.arg(row_limit) for update skip locked)
+returning d.id, d.event_id, d.event_type, d.url, d.payload, d.attempts, d.next_attempt_at, d.last_error, d.delivered_at, d.created_at;
+
+-- name: MarkVPNWebhookDelivered :exec
+update vpn_webhook_deliveries
+set attempts     = attempts + 1,
+    last_error   = '',
+    delivered_at = sqlc.arg(delivered_at)::timestamptz
+where id = sqlc.arg(id);
+
+-- name: FailVPNWebhookDelivery :exec
+update vpn_webhook_deliveries
+set attempts        = attempts + 1,
+    next_attempt_at = $2,
+    last_error      = $3
+where id = $1;
+
+-- name: ReplayVPNWebhookDeliveries :execrows
+update vpn_webhook_deliveries
+set attempts        = 0,
+    next_attempt_at = sqlc.arg(now),
+    last_error      = '',
+    delivered_at    = null
+where created_at >= sqlc.arg(since)
+  and created_at <= sqlc.arg(until)
+  and (sqlc.arg(event_type)::text = '' or event_type = sqlc.arg(event_type)::text)
+  and (not sqlc.arg(undelivered_only)::boolean or delivered_at is null);
@@ -97,3 +97,7 @@ func runMigrations(cfg *config.PostgresConfig) error {
 func (r *PostgresRepository) Close() {
 	r.db.Close()
 }
+
+func (r *PostgresRepository) Ping(ctx context.Context) error {
+	return r.db.Ping(ctx)
+}
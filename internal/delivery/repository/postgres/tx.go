@@ -0,0 +1,37 @@
+package postgres
+
+import (
+	"context"
+)
+
+type (
+	// TxQuerier is the queries of the client changes which queue the webhook events in the same transaction,
+	// so the events are queued if and only if the change is stored
+	TxQuerier interface {
+		CreateVpnClient(ctx context.Context, arg CreateVpnClientParams) error
+		CreateVpnClients(ctx context.Context, arg []CreateVpnClientsParams) (int64, error)
+		DeleteVPNClients(ctx context.Context, arg DeleteVPNClientsParams) (int64, error)
+		UpdateVPNClientsBanStatus(ctx context.Context, arg UpdateVPNClientsBanStatusParams) (int64, error)
+		UpdateVPNClientsLastHandshake(ctx context.Context, arg UpdateVPNClientsLastHandshakeParams) (int64, error)
+		UpdateVPNSession(ctx context.Context, arg UpdateVPNSessionParams) error
+		CreateVPNWebhookDelivery(ctx context.Context, arg CreateVPNWebhookDeliveryParams) error
+	}
+)
+
+// InTx runs the function with the queries bound to the transaction, the transaction is committed if the function succeeds
+func (r *PostgresRepository) InTx(ctx context.Context, fn func(q TxQuerier) error) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	// the rollback of the committed transaction does nothing
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	if err = fn(r.Queries.WithTx(tx)); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
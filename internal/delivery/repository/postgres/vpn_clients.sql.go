@@ -8,13 +8,15 @@ package postgres
 import (
 	"context"
 	"net/netip"
+	"time"
 
 	"github.com/gofrs/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
 const createVpnClient = `-- name: CreateVpnClient :exec
-insert into vpn_clients (user_id, group_id, ip_address, public_key, private_key, laboratory_cidr)
-values ($1, $2, $3, $4, $5, $6)
+insert into vpn_clients (user_id, group_id, ip_address, public_key, private_key, laboratory_cidr, node_id)
+values ($1, $2, $3, $4, $5, $6, $7)
 `
 
 type CreateVpnClientParams struct {
@@ -24,6 +26,7 @@ type CreateVpnClientParams struct {
 	PublicKey      string       `json:"public_key"`
 	PrivateKey     string       `json:"private_key"`
 	LaboratoryCidr netip.Prefix `json:"laboratory_cidr"`
+	NodeID         string       `json:"node_id"`
 }
 
 func (q *Queries) CreateVpnClient(ctx context.Context, arg CreateVpnClientParams) error {
@@ -34,30 +37,163 @@ func (q *Queries) CreateVpnClient(ctx context.Context, arg CreateVpnClientParams
 		arg.PublicKey,
 		arg.PrivateKey,
 		arg.LaboratoryCidr,
+		arg.NodeID,
 	)
 	return err
 }
 
+type CreateVpnClientsParams struct {
+	UserID         uuid.UUID    `json:"user_id"`
+	GroupID        uuid.UUID    `json:"group_id"`
+	IpAddress      netip.Prefix `json:"ip_address"`
+	PublicKey      string       `json:"public_key"`
+	PrivateKey     string       `json:"private_key"`
+	LaboratoryCidr netip.Prefix `json:"laboratory_cidr"`
+	NodeID         string       `json:"node_id"`
+}
+
 const deleteVPNClients = `-- name: DeleteVPNClients :execrows
 delete
 from vpn_clients
-where user_id = coalesce($1, user_id)
-  and group_id = coalesce($2, group_id)
+where node_id = $1
+  and user_id = coalesce($2, user_id)
+  and group_id = coalesce($3, group_id)
 `
 
 type DeleteVPNClientsParams struct {
+	NodeID  string        `json:"node_id"`
 	UserID  uuid.NullUUID `json:"user_id"`
 	GroupID uuid.NullUUID `json:"group_id"`
 }
 
 func (q *Queries) DeleteVPNClients(ctx context.Context, arg DeleteVPNClientsParams) (int64, error) {
-	result, err := q.db.Exec(ctx, deleteVPNClients, arg.UserID, arg.GroupID)
+	result, err := q.db.Exec(ctx, deleteVPNClients, arg.NodeID, arg.UserID, arg.GroupID)
 	if err != nil {
 		return 0, err
 	}
 	return result.RowsAffected(), nil
 }
 
+const getNodeVPNClients = `-- name: GetNodeVPNClients :many
+select user_id,
+       group_id,
+       ip_address,
+       public_key,
+       private_key,
+       laboratory_cidr,
+       banned,
+       updated_at,
+       created_at,
+       node_id,
+       last_handshake_at,
+       ban_reason,
+       expires_at,
+       transfer_quota
+from vpn_clients
+where node_id = $1
+`
+
+func (q *Queries) GetNodeVPNClients(ctx context.Context, nodeID string) ([]VpnClient, error) {
+	rows, err := q.db.Query(ctx, getNodeVPNClients, nodeID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []VpnClient{}
+	for rows.Next() {
+		var i VpnClient
+		if err := rows.Scan(
+			&i.UserID,
+			&i.GroupID,
+			&i.IpAddress,
+			&i.PublicKey,
+			&i.PrivateKey,
+			&i.LaboratoryCidr,
+			&i.Banned,
+			&i.UpdatedAt,
+			&i.CreatedAt,
+			&i.NodeID,
+			&i.LastHandshakeAt,
+			&i.BanReason,
+			&i.ExpiresAt,
+			&i.TransferQuota,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getVPNClient = `-- name: GetVPNClient :one
+select user_id,
+       group_id,
+       ip_address,
+       public_key,
+       private_key,
+       laboratory_cidr,
+       banned,
+       updated_at,
+       created_at,
+       node_id,
+       last_handshake_at,
+       ban_reason,
+       expires_at,
+       transfer_quota
+from vpn_clients
+where user_id = $1
+  and group_id = $2
+`
+
+type GetVPNClientParams struct {
+	UserID  uuid.UUID `json:"user_id"`
+	GroupID uuid.UUID `json:"group_id"`
+}
+
+func (q *Queries) GetVPNClient(ctx context.Context, arg GetVPNClientParams) (VpnClient, error) {
+	row := q.db.QueryRow(ctx, getVPNClient, arg.UserID, arg.GroupID)
+	var i VpnClient
+	err := row.Scan(
+		&i.UserID,
+		&i.GroupID,
+		&i.IpAddress,
+		&i.PublicKey,
+		&i.PrivateKey,
+		&i.LaboratoryCidr,
+		&i.Banned,
+		&i.UpdatedAt,
+		&i.CreatedAt,
+		&i.NodeID,
+		&i.LastHandshakeAt,
+		&i.BanReason,
+		&i.ExpiresAt,
+		&i.TransferQuota,
+	)
+	return i, err
+}
+
+const getVPNClientNodeID = `-- name: GetVPNClientNodeID :one
+select node_id
+from vpn_clients
+where user_id = $1
+  and group_id = $2
+`
+
+type GetVPNClientNodeIDParams struct {
+	UserID  uuid.UUID `json:"user_id"`
+	GroupID uuid.UUID `json:"group_id"`
+}
+
+func (q *Queries) GetVPNClientNodeID(ctx context.Context, arg GetVPNClientNodeIDParams) (string, error) {
+	row := q.db.QueryRow(ctx, getVPNClientNodeID, arg.UserID, arg.GroupID)
+	var node_id string
+	err := row.Scan(&node_id)
+	return node_id, err
+}
+
 const getVPNClients = `-- name: GetVPNClients :many
 select user_id,
        group_id,
@@ -67,7 +203,12 @@ select user_id,
        laboratory_cidr,
        banned,
        updated_at,
-       created_at
+       created_at,
+       node_id,
+       last_handshake_at,
+       ban_reason,
+       expires_at,
+       transfer_quota
 from vpn_clients
 `
 
@@ -90,6 +231,224 @@ func (q *Queries) GetVPNClients(ctx context.Context) ([]VpnClient, error) {
 			&i.Banned,
 			&i.UpdatedAt,
 			&i.CreatedAt,
+			&i.NodeID,
+			&i.LastHandshakeAt,
+			&i.BanReason,
+			&i.ExpiresAt,
+			&i.TransferQuota,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getVPNClientsTransfer = `-- name: GetVPNClientsTransfer :many
+select c.user_id,
+       c.group_id,
+       coalesce(sum(s.transfer_rx + s.transfer_tx), 0)::bigint as transfer
+from vpn_clients c
+         join vpn_sessions s on s.user_id = c.user_id and s.group_id = c.group_id and s.started_at >= date_trunc('second', c.created_at)
+where c.node_id = $1
+  and c.transfer_quota > 0
+group by c.user_id, c.group_id
+`
+
+type GetVPNClientsTransferRow struct {
+	UserID   uuid.UUID `json:"user_id"`
+	GroupID  uuid.UUID `json:"group_id"`
+	Transfer int64     `json:"transfer"`
+}
+
+// the transfer of the clients with the quota is the sum of their sessions since they were created,
+// the sessions start at the second of the handshake
+func (q *Queries) GetVPNClientsTransfer(ctx context.Context, nodeID string) ([]GetVPNClientsTransferRow, error) {
+	rows, err := q.db.Query(ctx, getVPNClientsTransfer, nodeID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []GetVPNClientsTransferRow{}
+	for rows.Next() {
+		var i GetVPNClientsTransferRow
+		if err := rows.Scan(&i.UserID, &i.GroupID, &i.Transfer); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getVPNClientsWithoutHandshake = `-- name: GetVPNClientsWithoutHandshake :many
+select user_id,
+       group_id,
+       public_key
+from vpn_clients
+where node_id = $1
+  and last_handshake_at is null
+  and public_key = any ($2::text[])
+`
+
+type GetVPNClientsWithoutHandshakeParams struct {
+	NodeID     string   `json:"node_id"`
+	PublicKeys []string `json:"public_keys"`
+}
+
+type GetVPNClientsWithoutHandshakeRow struct {
+	UserID    uuid.UUID `json:"user_id"`
+	GroupID   uuid.UUID `json:"group_id"`
+	PublicKey string    `json:"public_key"`
+}
+
+func (q *Queries) GetVPNClientsWithoutHandshake(ctx context.Context, arg GetVPNClientsWithoutHandshakeParams) ([]GetVPNClientsWithoutHandshakeRow, error) {
+	rows, err := q.db.Query(ctx, getVPNClientsWithoutHandshake, arg.NodeID, arg.PublicKeys)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []GetVPNClientsWithoutHandshakeRow{}
+	for rows.Next() {
+		var i GetVPNClientsWithoutHandshakeRow
+		if err := rows.Scan(&i.UserID, &i.GroupID, &i.PublicKey); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getVPNGroupLaboratoryCIDRs = `-- name: GetVPNGroupLaboratoryCIDRs :many
+select distinct laboratory_cidr
+from vpn_clients
+where group_id = $1
+`
+
+func (q *Queries) GetVPNGroupLaboratoryCIDRs(ctx context.Context, groupID uuid.UUID) ([]netip.Prefix, error) {
+	rows, err := q.db.Query(ctx, getVPNGroupLaboratoryCIDRs, groupID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []netip.Prefix{}
+	for rows.Next() {
+		var laboratory_cidr netip.Prefix
+		if err := rows.Scan(&laboratory_cidr); err != nil {
+			return nil, err
+		}
+		items = append(items, laboratory_cidr)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getVPNGroupNodeID = `-- name: GetVPNGroupNodeID :one
+select node_id
+from vpn_clients
+where group_id = $1
+group by node_id
+order by count(*) desc
+limit 1
+`
+
+func (q *Queries) GetVPNGroupNodeID(ctx context.Context, groupID uuid.UUID) (string, error) {
+	row := q.db.QueryRow(ctx, getVPNGroupNodeID, groupID)
+	var node_id string
+	err := row.Scan(&node_id)
+	return node_id, err
+}
+
+const listVPNClients = `-- name: ListVPNClients :many
+select user_id,
+       group_id,
+       ip_address,
+       banned,
+       created_at,
+       node_id,
+       last_handshake_at,
+       ban_reason
+from vpn_clients
+where ($1::uuid is null or user_id = $1::uuid)
+  and ($2::uuid is null or group_id = $2::uuid)
+  and ($3::bool is null or banned = $3::bool)
+  and ($4::timestamptz is null or last_handshake_at >= $4::timestamptz)
+  and ($5::cidr is null or ip_address <<= $5::cidr)
+  and ($6::timestamptz is null or created_at > $6::timestamptz)
+order by case when $7::text = 'lastSeen' and not $8::bool then last_handshake_at end nulls first,
+         case when $7::text = 'lastSeen' and $8::bool then last_handshake_at end desc nulls last,
+         case when $7::text = 'address' and not $8::bool then ip_address end,
+         case when $7::text = 'address' and $8::bool then ip_address end desc,
+         case when $7::text = 'created' and not $8::bool then created_at end,
+         case when $7::text = 'created' and $8::bool then created_at end desc,
+         user_id,
+         group_id
+limit $10 offset $9
+`
+
+type ListVPNClientsParams struct {
+	UserID       uuid.NullUUID      `json:"user_id"`
+	GroupID      uuid.NullUUID      `json:"group_id"`
+	Banned       pgtype.Bool        `json:"banned"`
+	OnlineSince  pgtype.Timestamptz `json:"online_since"`
+	AddressCidr  *netip.Prefix      `json:"address_cidr"`
+	CreatedAfter pgtype.Timestamptz `json:"created_after"`
+	SortBy       string             `json:"sort_by"`
+	SortDesc     bool               `json:"sort_desc"`
+	PageOffset   int32              `json:"page_offset"`
+	PageSize     int32              `json:"page_size"`
+}
+
+type ListVPNClientsRow struct {
+	UserID          uuid.UUID          `json:"user_id"`
+	GroupID         uuid.UUID          `json:"group_id"`
+	IpAddress       netip.Prefix       `json:"ip_address"`
+	Banned          bool               `json:"banned"`
+	CreatedAt       time.Time          `json:"created_at"`
+	NodeID          string             `json:"node_id"`
+	LastHandshakeAt pgtype.Timestamptz `json:"last_handshake_at"`
+	BanReason       string             `json:"ban_reason"`
+}
+
+func (q *Queries) ListVPNClients(ctx context.Context, arg ListVPNClientsParams) ([]ListVPNClientsRow, error) {
+	rows, err := q.db.Query(ctx, listVPNClients,
+		arg.UserID,
+		arg.GroupID,
+		arg.Banned,
+		arg.OnlineSince,
+		arg.AddressCidr,
+		arg.CreatedAfter,
+		arg.SortBy,
+		arg.SortDesc,
+		arg.PageOffset,
+		arg.PageSize,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListVPNClientsRow{}
+	for rows.Next() {
+		var i ListVPNClientsRow
+		if err := rows.Scan(
+			&i.UserID,
+			&i.GroupID,
+			&i.IpAddress,
+			&i.Banned,
+			&i.CreatedAt,
+			&i.NodeID,
+			&i.LastHandshakeAt,
+			&i.BanReason,
 		); err != nil {
 			return nil, err
 		}
@@ -103,20 +462,87 @@ func (q *Queries) GetVPNClients(ctx context.Context) ([]VpnClient, error) {
 
 const updateVPNClientsBanStatus = `-- name: UpdateVPNClientsBanStatus :execrows
 update vpn_clients
-set banned = $1,
+set banned     = $1,
+    ban_reason = $3,
     updated_at = now()
-where user_id = coalesce($2, user_id)
-  and group_id = coalesce($3, group_id)
+where node_id = $2
+  and user_id = coalesce($4, user_id)
+  and group_id = coalesce($5, group_id)
+  -- the clients already in the status keep their ban reason
+  and banned <> $1
 `
 
 type UpdateVPNClientsBanStatusParams struct {
-	Banned  bool          `json:"banned"`
-	UserID  uuid.NullUUID `json:"user_id"`
-	GroupID uuid.NullUUID `json:"group_id"`
+	Banned    bool          `json:"banned"`
+	NodeID    string        `json:"node_id"`
+	BanReason string        `json:"ban_reason"`
+	UserID    uuid.NullUUID `json:"user_id"`
+	GroupID   uuid.NullUUID `json:"group_id"`
 }
 
 func (q *Queries) UpdateVPNClientsBanStatus(ctx context.Context, arg UpdateVPNClientsBanStatusParams) (int64, error) {
-	result, err := q.db.Exec(ctx, updateVPNClientsBanStatus, arg.Banned, arg.UserID, arg.GroupID)
+	result, err := q.db.Exec(ctx, updateVPNClientsBanStatus,
+		arg.Banned,
+		arg.NodeID,
+		arg.BanReason,
+		arg.UserID,
+		arg.GroupID,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const updateVPNClientsLastHandshake = `-- name: UpdateVPNClientsLastHandshake :execrows
+update vpn_clients c
+set last_handshake_at = h.last_handshake_at
+from (select unnest($2::text[])              as public_key,
+             unnest($3::timestamptz[]) as last_handshake_at) h
+where c.node_id = $1
+  and c.public_key = h.public_key
+`
+
+type UpdateVPNClientsLastHandshakeParams struct {
+	NodeID         string      `json:"node_id"`
+	PublicKeys     []string    `json:"public_keys"`
+	LastHandshakes []time.Time `json:"last_handshakes"`
+}
+
+func (q *Queries) UpdateVPNClientsLastHandshake(ctx context.Context, arg UpdateVPNClientsLastHandshakeParams) (int64, error) {
+	result, err := q.db.Exec(ctx, updateVPNClientsLastHandshake, arg.NodeID, arg.PublicKeys, arg.LastHandshakes)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const updateVPNClientsLimits = `-- name: UpdateVPNClientsLimits :execrows
+update vpn_clients
+set expires_at     = $1,
+    transfer_quota = $2,
+    updated_at     = now()
+where node_id = $3
+  and user_id = coalesce($4, user_id)
+  and group_id = coalesce($5, group_id)
+`
+
+type UpdateVPNClientsLimitsParams struct {
+	ExpiresAt     pgtype.Timestamptz `json:"expires_at"`
+	TransferQuota int64              `json:"transfer_quota"`
+	NodeID        string             `json:"node_id"`
+	UserID        uuid.NullUUID      `json:"user_id"`
+	GroupID       uuid.NullUUID      `json:"group_id"`
+}
+
+func (q *Queries) UpdateVPNClientsLimits(ctx context.Context, arg UpdateVPNClientsLimitsParams) (int64, error) {
+	result, err := q.db.Exec(ctx, updateVPNClientsLimits,
+		arg.ExpiresAt,
+		arg.TransferQuota,
+		arg.NodeID,
+		arg.UserID,
+		arg.GroupID,
+	)
 	if err != nil {
 		return 0, err
 	}
@@ -0,0 +1,114 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.25.0
+// source: vpn_dns_records.sql
+
+package postgres
+
+import (
+	"context"
+	"net/netip"
+
+	"github.com/gofrs/uuid"
+)
+
+const createVPNDNSRecord = `-- name: CreateVPNDNSRecord :one
+insert into vpn_dns_records (id, group_id, name, address, laboratory_cidr)
+values ($1, $2, $3, $4, $5)
+on conflict (group_id, name, address) do nothing
+returning id, group_id, name, address, laboratory_cidr, created_at
+`
+
+type CreateVPNDNSRecordParams struct {
+	ID             uuid.UUID    `json:"id"`
+	GroupID        uuid.UUID    `json:"group_id"`
+	Name           string       `json:"name"`
+	Address        netip.Addr   `json:"address"`
+	LaboratoryCidr netip.Prefix `json:"laboratory_cidr"`
+}
+
+func (q *Queries) CreateVPNDNSRecord(ctx context.Context, arg CreateVPNDNSRecordParams) (VpnDnsRecord, error) {
+	row := q.db.QueryRow(ctx, createVPNDNSRecord,
+		arg.ID,
+		arg.GroupID,
+		arg.Name,
+		arg.Address,
+		arg.LaboratoryCidr,
+	)
+	var i VpnDnsRecord
+	err := row.Scan(
+		&i.ID,
+		&i.GroupID,
+		&i.Name,
+		&i.Address,
+		&i.LaboratoryCidr,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const deleteVPNDNSRecord = `-- name: DeleteVPNDNSRecord :one
+delete
+from vpn_dns_records
+where id = $1
+  and group_id = $2
+returning id, group_id, name, address, laboratory_cidr, created_at
+`
+
+type DeleteVPNDNSRecordParams struct {
+	ID      uuid.UUID `json:"id"`
+	GroupID uuid.UUID `json:"group_id"`
+}
+
+func (q *Queries) DeleteVPNDNSRecord(ctx context.Context, arg DeleteVPNDNSRecordParams) (VpnDnsRecord, error) {
+	row := q.db.QueryRow(ctx, deleteVPNDNSRecord, arg.ID, arg.GroupID)
+	var i VpnDnsRecord
+	err := row.Scan(
+		&i.ID,
+		&i.GroupID,
+		&i.Name,
+		&i.Address,
+		&i.LaboratoryCidr,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const listVPNDNSRecords = `-- name: ListVPNDNSRecords :many
+select id,
+       group_id,
+       name,
+       address,
+       laboratory_cidr,
+       created_at
+from vpn_dns_records
+where ($1::uuid is null or group_id = $1::uuid)
+order by name, address
+`
+
+func (q *Queries) ListVPNDNSRecords(ctx context.Context, groupID uuid.NullUUID) ([]VpnDnsRecord, error) {
+	rows, err := q.db.Query(ctx, listVPNDNSRecords, groupID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []VpnDnsRecord{}
+	for rows.Next() {
+		var i VpnDnsRecord
+		if err := rows.Scan(
+			&i.ID,
+			&i.GroupID,
+			&i.Name,
+			&i.Address,
+			&i.LaboratoryCidr,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -0,0 +1,151 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.25.0
+// source: vpn_nodes.sql
+
+package postgres
+
+import (
+	"context"
+	"time"
+)
+
+const getAliveVPNNodes = `-- name: GetAliveVPNNodes :many
+select id,
+       endpoint,
+       public_key,
+       grpc_endpoint,
+       heartbeat_at,
+       created_at
+from vpn_nodes
+where heartbeat_at > $1
+order by id
+`
+
+func (q *Queries) GetAliveVPNNodes(ctx context.Context, heartbeatAt time.Time) ([]VpnNode, error) {
+	rows, err := q.db.Query(ctx, getAliveVPNNodes, heartbeatAt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []VpnNode{}
+	for rows.Next() {
+		var i VpnNode
+		if err := rows.Scan(
+			&i.ID,
+			&i.Endpoint,
+			&i.PublicKey,
+			&i.GrpcEndpoint,
+			&i.HeartbeatAt,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getAliveVPNNodesLoad = `-- name: GetAliveVPNNodesLoad :many
+select n.id,
+       count(c.user_id) as clients
+from vpn_nodes n
+         left join vpn_clients c on c.node_id = n.id
+where n.heartbeat_at > $1
+group by n.id
+order by clients, n.id
+`
+
+type GetAliveVPNNodesLoadRow struct {
+	ID      string `json:"id"`
+	Clients int64  `json:"clients"`
+}
+
+func (q *Queries) GetAliveVPNNodesLoad(ctx context.Context, heartbeatAt time.Time) ([]GetAliveVPNNodesLoadRow, error) {
+	rows, err := q.db.Query(ctx, getAliveVPNNodesLoad, heartbeatAt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []GetAliveVPNNodesLoadRow{}
+	for rows.Next() {
+		var i GetAliveVPNNodesLoadRow
+		if err := rows.Scan(&i.ID, &i.Clients); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getVPNNode = `-- name: GetVPNNode :one
+select id,
+       endpoint,
+       public_key,
+       grpc_endpoint,
+       heartbeat_at,
+       created_at
+from vpn_nodes
+where id = $1
+`
+
+func (q *Queries) GetVPNNode(ctx context.Context, id string) (VpnNode, error) {
+	row := q.db.QueryRow(ctx, getVPNNode, id)
+	var i VpnNode
+	err := row.Scan(
+		&i.ID,
+		&i.Endpoint,
+		&i.PublicKey,
+		&i.GrpcEndpoint,
+		&i.HeartbeatAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const updateVPNNodeHeartbeat = `-- name: UpdateVPNNodeHeartbeat :execrows
+update vpn_nodes
+set heartbeat_at = now()
+where id = $1
+`
+
+func (q *Queries) UpdateVPNNodeHeartbeat(ctx context.Context, id string) (int64, error) {
+	result, err := q.db.Exec(ctx, updateVPNNodeHeartbeat, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const upsertVPNNode = `-- name: UpsertVPNNode :exec
+insert into vpn_nodes (id, endpoint, public_key, grpc_endpoint)
+values ($1, $2, $3, $4)
+on conflict (id) do update
+    set endpoint      = excluded.endpoint,
+        public_key    = excluded.public_key,
+        grpc_endpoint = excluded.grpc_endpoint,
+        heartbeat_at  = now()
+`
+
+type UpsertVPNNodeParams struct {
+	ID           string `json:"id"`
+	Endpoint     string `json:"endpoint"`
+	PublicKey    string `json:"public_key"`
+	GrpcEndpoint string `json:"grpc_endpoint"`
+}
+
+func (q *Queries) UpsertVPNNode(ctx context.Context, arg UpsertVPNNodeParams) error {
+	_, err := q.db.Exec(ctx, upsertVPNNode,
+		arg.ID,
+		arg.Endpoint,
+		arg.PublicKey,
+		arg.GrpcEndpoint,
+	)
+	return err
+}
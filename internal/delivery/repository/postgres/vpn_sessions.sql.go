@@ -0,0 +1,158 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.25.0
+// source: vpn_sessions.sql
+
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const createVPNSession = `-- name: CreateVPNSession :one
+insert into vpn_sessions (user_id, group_id, node_id, endpoint, transfer_rx, transfer_tx, started_at, last_active_at)
+values ($1, $2, $3, $4, $5, $6, $7, $8)
+returning id
+`
+
+type CreateVPNSessionParams struct {
+	UserID       uuid.UUID `json:"user_id"`
+	GroupID      uuid.UUID `json:"group_id"`
+	NodeID       string    `json:"node_id"`
+	Endpoint     string    `json:"endpoint"`
+	TransferRx   int64     `json:"transfer_rx"`
+	TransferTx   int64     `json:"transfer_tx"`
+	StartedAt    time.Time `json:"started_at"`
+	LastActiveAt time.Time `json:"last_active_at"`
+}
+
+func (q *Queries) CreateVPNSession(ctx context.Context, arg CreateVPNSessionParams) (int64, error) {
+	row := q.db.QueryRow(ctx, createVPNSession,
+		arg.UserID,
+		arg.GroupID,
+		arg.NodeID,
+		arg.Endpoint,
+		arg.TransferRx,
+		arg.TransferTx,
+		arg.StartedAt,
+		arg.LastActiveAt,
+	)
+	var id int64
+	err := row.Scan(&id)
+	return id, err
+}
+
+const endVPNNodeSessions = `-- name: EndVPNNodeSessions :execrows
+update vpn_sessions
+set ended_at = last_active_at
+where node_id = $1
+  and ended_at is null
+`
+
+func (q *Queries) EndVPNNodeSessions(ctx context.Context, nodeID string) (int64, error) {
+	result, err := q.db.Exec(ctx, endVPNNodeSessions, nodeID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const getVPNClientSessions = `-- name: GetVPNClientSessions :many
+select id,
+       user_id,
+       group_id,
+       node_id,
+       endpoint,
+       transfer_rx,
+       transfer_tx,
+       started_at,
+       last_active_at,
+       ended_at
+from vpn_sessions
+where user_id = $1
+  and group_id = $2
+  and started_at >= $3
+  and started_at <= $4
+order by started_at desc, id desc
+limit $5
+`
+
+type GetVPNClientSessionsParams struct {
+	UserID   uuid.UUID `json:"user_id"`
+	GroupID  uuid.UUID `json:"group_id"`
+	Since    time.Time `json:"since"`
+	Until    time.Time `json:"until"`
+	RowLimit int32     `json:"row_limit"`
+}
+
+func (q *Queries) GetVPNClientSessions(ctx context.Context, arg GetVPNClientSessionsParams) ([]VpnSession, error) {
+	rows, err := q.db.Query(ctx, getVPNClientSessions,
+		arg.UserID,
+		arg.GroupID,
+		arg.Since,
+		arg.Until,
+		arg.RowLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []VpnSession{}
+	for rows.Next() {
+		var i VpnSession
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.GroupID,
+			&i.NodeID,
+			&i.Endpoint,
+			&i.TransferRx,
+			&i.TransferTx,
+			&i.StartedAt,
+			&i.LastActiveAt,
+			&i.EndedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const updateVPNSession = `-- name: UpdateVPNSession :exec
+update vpn_sessions
+set endpoint       = $2,
+    transfer_rx    = $3,
+    transfer_tx    = $4,
+    last_active_at = $5,
+    ended_at       = $6
+where id = $1
+`
+
+type UpdateVPNSessionParams struct {
+	ID           int64              `json:"id"`
+	Endpoint     string             `json:"endpoint"`
+	TransferRx   int64              `json:"transfer_rx"`
+	TransferTx   int64              `json:"transfer_tx"`
+	LastActiveAt time.Time          `json:"last_active_at"`
+	EndedAt      pgtype.Timestamptz `json:"ended_at"`
+}
+
+func (q *Queries) UpdateVPNSession(ctx context.Context, arg UpdateVPNSessionParams) error {
+	_, err := q.db.Exec(ctx, updateVPNSession,
+		arg.ID,
+		arg.Endpoint,
+		arg.TransferRx,
+		arg.TransferTx,
+		arg.LastActiveAt,
+		arg.EndedAt,
+	)
+	return err
+}
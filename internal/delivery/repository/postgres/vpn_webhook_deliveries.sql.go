@@ -0,0 +1,162 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.25.0
+// source: vpn_webhook_deliveries.sql
+
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/gofrs/uuid"
+)
+
+const claimVPNWebhookDeliveries = `-- name: ClaimVPNWebhookDeliveries :many
+update vpn_webhook_deliveries d
+set next_attempt_at = $1
+where d.id in (select q.id
+               from vpn_webhook_deliveries q
+               where q.delivered_at is null
+                 and q.attempts < $2::integer
+                 and q.next_attempt_at <= $3
+               order by q.next_attempt_at, q.id
+               limit $4 for update skip locked)
+returning d.id, d.event_id, d.event_type, d.url, d.payload, d.attempts, d.next_attempt_at, d.last_error, d.delivered_at, d.created_at
+`
+
+type ClaimVPNWebhookDeliveriesParams struct {
+	LeaseUntil  time.Time `json:"lease_until"`
+	MaxAttempts int32     `json:"max_attempts"`
+	Now         time.Time `json:"now"`
+	RowLimit    int32     `json:"row_limit"`
+}
+
+func (q *Queries) ClaimVPNWebhookDeliveries(ctx context.Context, arg ClaimVPNWebhookDeliveriesParams) ([]VpnWebhookDelivery, error) {
+	rows, err := q.db.Query(ctx, claimVPNWebhookDeliveries,
+		arg.LeaseUntil,
+		arg.MaxAttempts,
+		arg.Now,
+		arg.RowLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []VpnWebhookDelivery{}
+	for rows.Next() {
+		var i VpnWebhookDelivery
+		if err := rows.Scan(
+			&i.ID,
+			&i.EventID,
+			&i.EventType,
+			&i.Url,
+			&i.Payload,
+			&i.Attempts,
+			&i.NextAttemptAt,
+			&i.LastError,
+			&i.DeliveredAt,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const createVPNWebhookDelivery = `-- name: CreateVPNWebhookDelivery :exec
+insert into vpn_webhook_deliveries (event_id, event_type, url, payload, next_attempt_at)
+values ($1, $2, $3, $4, now())
+`
+
+type CreateVPNWebhookDeliveryParams struct {
+	EventID   uuid.UUID `json:"event_id"`
+	EventType string    `json:"event_type"`
+	Url       string    `json:"url"`
+	Payload   []byte    `json:"payload"`
+}
+
+func (q *Queries) CreateVPNWebhookDelivery(ctx context.Context, arg CreateVPNWebhookDeliveryParams) error {
+	_, err := q.db.Exec(ctx, createVPNWebhookDelivery,
+		arg.EventID,
+		arg.EventType,
+		arg.Url,
+		arg.Payload,
+	)
+	return err
+}
+
+const failVPNWebhookDelivery = `-- name: FailVPNWebhookDelivery :exec
+update vpn_webhook_deliveries
+set attempts        = attempts + 1,
+    next_attempt_at = $2,
+    last_error      = $3
+where id = $1
+`
+
+type FailVPNWebhookDeliveryParams struct {
+	ID            int64     `json:"id"`
+	NextAttemptAt time.Time `json:"next_attempt_at"`
+	LastError     string    `json:"last_error"`
+}
+
+func (q *Queries) FailVPNWebhookDelivery(ctx context.Context, arg FailVPNWebhookDeliveryParams) error {
+	_, err := q.db.Exec(ctx, failVPNWebhookDelivery, arg.ID, arg.NextAttemptAt, arg.LastError)
+	return err
+}
+
+const markVPNWebhookDelivered = `-- name: MarkVPNWebhookDelivered :exec
+update vpn_webhook_deliveries
+set attempts     = attempts + 1,
+    last_error   = '',
+    delivered_at = $1::timestamptz
+where id = $2
+`
+
+type MarkVPNWebhookDeliveredParams struct {
+	DeliveredAt time.Time `json:"delivered_at"`
+	ID          int64     `json:"id"`
+}
+
+func (q *Queries) MarkVPNWebhookDelivered(ctx context.Context, arg MarkVPNWebhookDeliveredParams) error {
+	_, err := q.db.Exec(ctx, markVPNWebhookDelivered, arg.DeliveredAt, arg.ID)
+	return err
+}
+
+const replayVPNWebhookDeliveries = `-- name: ReplayVPNWebhookDeliveries :execrows
+update vpn_webhook_deliveries
+set attempts        = 0,
+    next_attempt_at = $1,
+    last_error      = '',
+    delivered_at    = null
+where created_at >= $2
+  and created_at <= $3
+  and ($4::text = '' or event_type = $4::text)
+  and (not $5::boolean or delivered_at is null)
+`
+
+type ReplayVPNWebhookDeliveriesParams struct {
+	Now             time.Time `json:"now"`
+	Since           time.Time `json:"since"`
+	Until           time.Time `json:"until"`
+	EventType       string    `json:"event_type"`
+	UndeliveredOnly bool      `json:"undelivered_only"`
+}
+
+func (q *Queries) ReplayVPNWebhookDeliveries(ctx context.Context, arg ReplayVPNWebhookDeliveriesParams) (int64, error) {
+	result, err := q.db.Exec(ctx, replayVPNWebhookDeliveries,
+		arg.Now,
+		arg.Since,
+		arg.Until,
+		arg.EventType,
+		arg.UndeliveredOnly,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
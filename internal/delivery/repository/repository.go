@@ -1,13 +1,25 @@
 package repository
 
 import (
+	"github.com/cybericebox/lib/pkg/ipam"
 	"github.com/cybericebox/wireguard/internal/config"
 	"github.com/cybericebox/wireguard/internal/delivery/repository/postgres"
+	"github.com/cybericebox/wireguard/internal/delivery/repository/sqlite"
+	"github.com/cybericebox/wireguard/internal/service"
 )
 
 type (
 	Repository struct {
-		*postgres.PostgresRepository
+		backend
+		config   *config.RepositoryConfig
+		sqlite   *sqlite.SQLiteRepository
+		postgres *postgres.PostgresRepository
+	}
+
+	// backend is the repository of the configured driver
+	backend interface {
+		service.Repository
+		Close()
 	}
 
 	Dependencies struct {
@@ -16,11 +28,47 @@ type (
 )
 
 func NewRepository(deps Dependencies) *Repository {
+	if deps.Config.Driver == config.RepositoryDriverSQLite {
+		repo := sqlite.NewRepository(&deps.Config.SQLite)
+		return &Repository{
+			backend: repo,
+			config:  deps.Config,
+			sqlite:  repo,
+		}
+	}
+
+	repo := postgres.NewRepository(&deps.Config.Postgres)
 	return &Repository{
-		postgres.NewRepository(&deps.Config.Postgres),
+		backend:  repo,
+		config:   deps.Config,
+		postgres: repo,
+	}
+}
+
+// NewIPAManager returns the manager of the client addresses stored by the repository driver
+func (r *Repository) NewIPAManager(cidr string) (service.IPAManager, error) {
+	if r.sqlite != nil {
+		ipaManager, err := r.sqlite.NewIPAManager(cidr)
+		if err != nil {
+			return nil, err
+		}
+		return ipaManager, nil
+	}
+
+	ipaManager, err := ipam.NewIPAManager(ipam.Dependencies{
+		PostgresConfig: ipam.PostgresConfig(r.config.Postgres),
+		CIDR:           cidr,
+	})
+	if err != nil {
+		return nil, err
 	}
+	return postgresIPAManager{
+		IPAManager: ipaManager,
+		repository: r.postgres,
+		cidr:       cidr,
+	}, nil
 }
 
 func (r *Repository) Close() {
-	r.PostgresRepository.Close()
+	r.backend.Close()
 }
@@ -0,0 +1,87 @@
+package sqlite
+
+import (
+	"context"
+	"github.com/cybericebox/wireguard/internal/delivery/repository/postgres"
+)
+
+func (r *SQLiteRepository) CreateAuditLogEntry(ctx context.Context, arg postgres.CreateAuditLogEntryParams) error {
+	parameters := string(arg.Parameters)
+	if parameters == "" {
+		parameters = "{}"
+	}
+
+	_, err := r.db.ExecContext(ctx, `insert into audit_log (subject, action, user_id, group_id, parameters, outcome, error, affected, node_id, created_at)
+values (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		arg.Subject,
+		arg.Action,
+		nullUUID(arg.UserID),
+		nullUUID(arg.GroupID),
+		parameters,
+		arg.Outcome,
+		arg.Error,
+		arg.Affected,
+		arg.NodeID,
+		now(),
+	)
+	return err
+}
+
+func (r *SQLiteRepository) GetAuditLog(ctx context.Context, arg postgres.GetAuditLogParams) ([]postgres.AuditLog, error) {
+	rows, err := r.db.QueryContext(ctx, `select id,
+       subject,
+       action,
+       user_id,
+       group_id,
+       parameters,
+       outcome,
+       error,
+       affected,
+       node_id,
+       created_at
+from audit_log
+where (?1 = '' or subject = ?1)
+  and (?2 = '' or action = ?2)
+  and (?3 is null or user_id = ?3)
+  and (?4 is null or group_id = ?4)
+  and created_at >= ?5
+  and created_at <= ?6
+order by created_at desc, id desc
+limit ?7`,
+		arg.Subject,
+		arg.Action,
+		nullUUID(arg.UserID),
+		nullUUID(arg.GroupID),
+		nanos(arg.Since),
+		nanos(arg.Until),
+		arg.RowLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []postgres.AuditLog
+	for rows.Next() {
+		var i postgres.AuditLog
+		var createdAt int64
+		if err = rows.Scan(
+			&i.ID,
+			&i.Subject,
+			&i.Action,
+			&i.UserID,
+			&i.GroupID,
+			&i.Parameters,
+			&i.Outcome,
+			&i.Error,
+			&i.Affected,
+			&i.NodeID,
+			&createdAt,
+		); err != nil {
+			return nil, err
+		}
+		i.CreatedAt = fromNanos(createdAt)
+		items = append(items, i)
+	}
+	return items, rows.Err()
+}
@@ -0,0 +1,75 @@
+package sqlite
+
+import (
+	"database/sql"
+	"encoding/binary"
+	"errors"
+	"github.com/gofrs/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"math"
+	"net/netip"
+	"time"
+)
+
+// The timestamps are stored as unix nanoseconds, the uuids and addresses as text and the booleans as integers
+
+var (
+	minTime = time.Unix(0, math.MinInt64)
+	maxTime = time.Unix(0, math.MaxInt64)
+)
+
+func now() int64 {
+	return time.Now().UnixNano()
+}
+
+// nanos returns the unix nanoseconds of the time, the times out of their range like the zero time are clamped
+func nanos(t time.Time) int64 {
+	switch {
+	case t.Before(minTime):
+		return math.MinInt64
+	case t.After(maxTime):
+		return math.MaxInt64
+	default:
+		return t.UnixNano()
+	}
+}
+
+func fromNanos(n int64) time.Time {
+	return time.Unix(0, n)
+}
+
+func toTimestamptz(n sql.NullInt64) pgtype.Timestamptz {
+	if !n.Valid {
+		return pgtype.Timestamptz{}
+	}
+	return pgtype.Timestamptz{Time: fromNanos(n.Int64), Valid: true}
+}
+
+func fromTimestamptz(t pgtype.Timestamptz) any {
+	if !t.Valid {
+		return nil
+	}
+	return nanos(t.Time)
+}
+
+func nullUUID(id uuid.NullUUID) any {
+	if !id.Valid {
+		return nil
+	}
+	return id.UUID.String()
+}
+
+// ipNumber returns the integer form of the IPv4 address of the prefix
+func ipNumber(addr netip.Addr) int64 {
+	ip := addr.As4()
+	return int64(binary.BigEndian.Uint32(ip[:]))
+}
+
+// noRows replaces the error of the missing row with the error of pgx, the service checks for it
+func noRows(err error) error {
+	if errors.Is(err, sql.ErrNoRows) {
+		return pgx.ErrNoRows
+	}
+	return err
+}
@@ -0,0 +1,169 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"github.com/cybericebox/lib/pkg/ipam"
+	"github.com/cybericebox/wireguard/pkg/appError"
+	"net/netip"
+)
+
+type (
+	// IPAManager allocates the client addresses of the CIDR in the sqlite database, so the single node does not need Postgres.
+	// The free addresses are acquired from the lowest one, the network and broadcast addresses are never acquired.
+	IPAManager struct {
+		db    *sql.DB
+		cidr  string
+		first int64
+		last  int64
+	}
+
+	// rowQuerier is the database or the transaction the address is acquired in
+	rowQuerier interface {
+		QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+	}
+)
+
+// NewIPAManager returns the manager of the addresses of the IPv4 CIDR
+func (r *SQLiteRepository) NewIPAManager(cidr string) (*IPAManager, error) {
+	if cidr == "" {
+		return nil, appError.ErrIPAM.WithMessage("CIDR is required").Err()
+	}
+
+	prefix, err := netip.ParsePrefix(cidr)
+	if err != nil {
+		return nil, appError.ErrIPAM.WithError(err).WithMessage("Failed to parse CIDR").Err()
+	}
+	if !prefix.Addr().Is4() {
+		return nil, appError.ErrIPAM.WithMessage("Only IPv4 CIDR is supported").WithContext("cidr", cidr).Err()
+	}
+	prefix = prefix.Masked()
+
+	network := ipNumber(prefix.Addr())
+	broadcast := network + int64(1)<<(32-prefix.Bits()) - 1
+
+	return &IPAManager{
+		db:    r.conn,
+		cidr:  prefix.String(),
+		first: network + 1,
+		last:  broadcast - 1,
+	}, nil
+}
+
+func (m *IPAManager) AcquireSingleIP(ctx context.Context, specificIP ...string) (string, error) {
+	if len(specificIP) > 0 {
+		number, err := m.ipNumber(specificIP[0])
+		if err != nil {
+			return "", err
+		}
+
+		// the acquiring of the acquired address succeeds, as in the postgres manager
+		if _, err = m.db.ExecContext(ctx, `insert into ip_allocations (ip_number, ip, created_at)
+values (?, ?, ?)
+on conflict (ip_number) do nothing`, number, specificIP[0], now()); err != nil {
+			return "", appError.ErrIPAM.WithWrappedError(appError.ErrSQLite.WithError(err)).WithMessage("Failed to acquire specific IP").Err()
+		}
+		return specificIP[0], nil
+	}
+
+	return m.acquireLowest(ctx, m.db)
+}
+
+// AcquireIPs acquires the lowest free addresses in a single transaction
+func (m *IPAManager) AcquireIPs(ctx context.Context, count int) (ips []string, err error) {
+	tx, err := m.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, appError.ErrIPAM.WithWrappedError(appError.ErrSQLite.WithError(err)).WithMessage("Failed to acquire IPs").Err()
+	}
+
+	ips = make([]string, 0, count)
+	for range count {
+		ip, err := m.acquireLowest(ctx, tx)
+		if err != nil {
+			// the addresses acquired before the failure are kept
+			if commitErr := tx.Commit(); commitErr != nil {
+				return nil, appError.ErrIPAM.WithWrappedError(appError.ErrSQLite.WithError(commitErr)).WithMessage("Failed to acquire IPs").Err()
+			}
+			return ips, err
+		}
+		ips = append(ips, ip)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, appError.ErrIPAM.WithWrappedError(appError.ErrSQLite.WithError(err)).WithMessage("Failed to acquire IPs").Err()
+	}
+
+	return ips, nil
+}
+
+// acquireLowest acquires the lowest free address, it is the first host address or the address after an acquired one which is not acquired itself
+func (m *IPAManager) acquireLowest(ctx context.Context, db rowQuerier) (string, error) {
+	var ip string
+	if err := db.QueryRowContext(ctx, `insert into ip_allocations (ip_number, ip, created_at)
+select n, (n >> 24) || '.' || ((n >> 16) & 255) || '.' || ((n >> 8) & 255) || '.' || (n & 255), ?3
+from (select ?1 as n
+      union all
+      select ip_number + 1
+      from ip_allocations
+      where ip_number between ?1 and ?2)
+where n <= ?2
+  and n not in (select ip_number from ip_allocations)
+order by n
+limit 1
+returning ip`, m.first, m.last, now()).Scan(&ip); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", appError.ErrIPAMNoFreeIP.WithContext("cidr", m.cidr).Err()
+		}
+		return "", appError.ErrIPAM.WithWrappedError(appError.ErrSQLite.WithError(err)).WithMessage("Failed to acquire IP").Err()
+	}
+
+	return ip, nil
+}
+
+func (m *IPAManager) ReleaseSingleIP(ctx context.Context, ip string) error {
+	number, err := m.ipNumber(ip)
+	if err != nil {
+		return err
+	}
+
+	if _, err = m.db.ExecContext(ctx, `delete
+from ip_allocations
+where ip_number = ?`, number); err != nil {
+		return appError.ErrIPAM.WithWrappedError(appError.ErrSQLite.WithError(err)).WithMessage("Failed to release IP").Err()
+	}
+
+	return nil
+}
+
+func (m *IPAManager) GetFirstIP() (string, error) {
+	ip, err := ipam.GetFirstCIDRIP(m.cidr)
+	if err != nil {
+		return "", appError.ErrIPAM.WithError(err).WithMessage("Failed to get first IP").Err()
+	}
+
+	return ip, nil
+}
+
+func (m *IPAManager) GetCIDR() string {
+	return m.cidr
+}
+
+// ipNumber returns the integer form of the address of the CIDR
+func (m *IPAManager) ipNumber(ip string) (int64, error) {
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return 0, appError.ErrIPAM.WithError(err).WithMessage("Failed to parse IP").Err()
+	}
+
+	addr = addr.Unmap()
+	if !addr.Is4() {
+		return 0, appError.ErrIPAMIPOutOfRange.WithContext("ip", ip).WithContext("cidr", m.cidr).Err()
+	}
+
+	number := ipNumber(addr)
+	if number < m.first-1 || number > m.last+1 {
+		return 0, appError.ErrIPAMIPOutOfRange.WithContext("ip", ip).WithContext("cidr", m.cidr).Err()
+	}
+	return number, nil
+}
@@ -0,0 +1,9 @@
+create table if not exists platform_settings
+(
+    key        text primary key,
+    value      text    not null,
+
+    updated_at integer,
+
+    created_at integer not null
+);
@@ -0,0 +1,31 @@
+create table if not exists vpn_clients
+(
+    user_id           text    not null,
+    group_id          text    not null,
+
+    ip_address        text    not null unique,
+    -- ip_number is the integer form of the address, it is used to sort the clients by address and to filter them by CIDR
+    ip_number         integer not null,
+    public_key        text    not null unique,
+    private_key       text    not null unique,
+    laboratory_cidr   text    not null,
+
+    banned            integer not null default 0,
+    ban_reason        text    not null default '',
+
+    node_id           text    not null default 'default',
+
+    last_handshake_at integer,
+
+    updated_at        integer,
+
+    created_at        integer not null,
+
+    primary key (user_id, group_id)
+);
+
+create index if not exists vpn_clients_node_id_idx on vpn_clients (node_id);
+create index if not exists vpn_clients_group_id_idx on vpn_clients (group_id);
+create index if not exists vpn_clients_ip_number_idx on vpn_clients (ip_number);
+create index if not exists vpn_clients_last_handshake_at_idx on vpn_clients (last_handshake_at);
+create index if not exists vpn_clients_created_at_idx on vpn_clients (created_at);
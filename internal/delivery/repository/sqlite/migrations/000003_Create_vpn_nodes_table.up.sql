@@ -0,0 +1,12 @@
+create table if not exists vpn_nodes
+(
+    id            text primary key,
+
+    endpoint      text    not null,
+    public_key    text    not null,
+    grpc_endpoint text    not null,
+
+    heartbeat_at  integer not null,
+
+    created_at    integer not null
+);
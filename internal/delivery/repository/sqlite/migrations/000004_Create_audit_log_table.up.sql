@@ -0,0 +1,24 @@
+create table if not exists audit_log
+(
+    id         integer primary key autoincrement,
+
+    subject    text    not null,
+    action     text    not null,
+
+    user_id    text,
+    group_id   text,
+
+    parameters text    not null default '{}',
+
+    outcome    text    not null,
+    error      text    not null default '',
+    affected   integer not null default 0,
+
+    node_id    text    not null,
+
+    created_at integer not null
+);
+
+create index if not exists audit_log_created_at_idx on audit_log (created_at);
+create index if not exists audit_log_user_id_group_id_idx on audit_log (user_id, group_id);
+create index if not exists audit_log_group_id_idx on audit_log (group_id);
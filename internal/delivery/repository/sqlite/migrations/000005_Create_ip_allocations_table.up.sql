@@ -0,0 +1,7 @@
+create table if not exists ip_allocations
+(
+    ip_number  integer primary key,
+    ip         text    not null unique,
+
+    created_at integer not null
+);
@@ -0,0 +1 @@
+drop table if exists vpn_dns_records;
\ No newline at end of file
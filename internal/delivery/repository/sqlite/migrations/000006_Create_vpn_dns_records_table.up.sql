@@ -0,0 +1,16 @@
+create table if not exists vpn_dns_records
+(
+    id              text    primary key,
+
+    group_id        text    not null,
+    name            text    not null,
+    address         text    not null,
+    -- laboratory_cidr is the lab network of the group given with the record, the address is within it
+    laboratory_cidr text    not null,
+
+    created_at      integer not null,
+
+    unique (group_id, name, address)
+);
+
+create index if not exists vpn_dns_records_name_idx on vpn_dns_records (name);
@@ -0,0 +1 @@
+drop table if exists vpn_sessions;
\ No newline at end of file
@@ -0,0 +1,21 @@
+create table if not exists vpn_sessions
+(
+    id             integer primary key autoincrement,
+
+    user_id        text    not null,
+    group_id       text    not null,
+    node_id        text    not null,
+
+    -- endpoint is the remote address the client peer connected from
+    endpoint       text    not null default '',
+    transfer_rx    integer not null default 0,
+    transfer_tx    integer not null default 0,
+
+    started_at     integer not null,
+    -- last_active_at is the last time the transfer of the session was sampled
+    last_active_at integer not null,
+    ended_at       integer
+);
+
+create index if not exists vpn_sessions_user_id_group_id_started_at_idx on vpn_sessions (user_id, group_id, started_at);
+create index if not exists vpn_sessions_node_id_idx on vpn_sessions (node_id) where ended_at is null;
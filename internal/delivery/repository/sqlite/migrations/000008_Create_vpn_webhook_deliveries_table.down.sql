@@ -0,0 +1 @@
+drop table if exists vpn_webhook_deliveries;
\ No newline at end of file
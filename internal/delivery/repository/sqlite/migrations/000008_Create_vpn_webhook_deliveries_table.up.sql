@@ -0,0 +1,21 @@
+create table if not exists vpn_webhook_deliveries
+(
+    id              integer primary key autoincrement,
+
+    -- event_id is shared by the deliveries of the event to every URL
+    event_id        text    not null,
+    event_type      text    not null,
+    url             text    not null,
+    payload         blob    not null,
+
+    attempts        integer not null default 0,
+    -- next_attempt_at is also the end of the lease of the claimed delivery
+    next_attempt_at integer not null,
+    last_error      text    not null default '',
+    delivered_at    integer,
+
+    created_at      integer not null
+);
+
+create index if not exists vpn_webhook_deliveries_next_attempt_at_idx on vpn_webhook_deliveries (next_attempt_at) where delivered_at is null;
+create index if not exists vpn_webhook_deliveries_created_at_idx on vpn_webhook_deliveries (created_at);
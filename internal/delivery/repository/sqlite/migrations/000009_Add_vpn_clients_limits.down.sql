@@ -0,0 +1,4 @@
+alter table vpn_clients
+    drop column transfer_quota;
+alter table vpn_clients
+    drop column expires_at;
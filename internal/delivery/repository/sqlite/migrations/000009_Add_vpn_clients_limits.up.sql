@@ -0,0 +1,5 @@
+-- the client is blocked when it expires or its sessions transfer the quota, zero quota is unlimited
+alter table vpn_clients
+    add column expires_at integer;
+alter table vpn_clients
+    add column transfer_quota integer not null default 0;
@@ -0,0 +1,31 @@
+package sqlite
+
+import (
+	"context"
+	"github.com/cybericebox/wireguard/internal/delivery/repository/postgres"
+)
+
+func (r *SQLiteRepository) GetPlatformSettings(ctx context.Context, key string) ([]byte, error) {
+	var value []byte
+	err := r.db.QueryRowContext(ctx, `select value
+from platform_settings
+where key = ?`, key).Scan(&value)
+	return value, noRows(err)
+}
+
+func (r *SQLiteRepository) CreatePlatformSettings(ctx context.Context, arg postgres.CreatePlatformSettingsParams) error {
+	_, err := r.db.ExecContext(ctx, `insert into platform_settings (key, value, created_at)
+values (?, ?, ?)`, arg.Key, string(arg.Value), now())
+	return err
+}
+
+func (r *SQLiteRepository) UpdatePlatformSettings(ctx context.Context, arg postgres.UpdatePlatformSettingsParams) (int64, error) {
+	result, err := r.db.ExecContext(ctx, `update platform_settings
+set value      = ?,
+    updated_at = ?
+where key = ?`, string(arg.Value), now(), arg.Key)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
@@ -0,0 +1,102 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"errors"
+	"fmt"
+	"github.com/cybericebox/wireguard/internal/config"
+	"github.com/cybericebox/wireguard/pkg/appError"
+	"github.com/golang-migrate/migrate/v4"
+	sqliteMigrate "github.com/golang-migrate/migrate/v4/database/sqlite"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+	"github.com/rs/zerolog/log"
+	_ "modernc.org/sqlite"
+)
+
+const migrationTable = "wireguard_schema_migrations"
+
+//go:embed migrations/*.sql
+var migrations embed.FS
+
+type (
+	// SQLiteRepository stores the data of the single node in the embedded SQLite database.
+	// The queries take and return the types of the postgres queries, so the service works with both repositories.
+	SQLiteRepository struct {
+		// db is the database or the transaction the queries run in
+		db   dbtx
+		conn *sql.DB
+		// inTx is set if the repository is bound to the transaction of InTx
+		inTx bool
+	}
+)
+
+func NewRepository(config *config.SQLiteConfig) *SQLiteRepository {
+	db, err := newSQLiteDB(config)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to open sqlite db")
+	}
+
+	if err = runMigrations(db); err != nil {
+		log.Fatal().Err(err).Msg("Failed to run db migrations")
+	}
+
+	return &SQLiteRepository{
+		db:   db,
+		conn: db,
+	}
+}
+
+func newSQLiteDB(cfg *config.SQLiteConfig) (*sql.DB, error) {
+	db, err := sql.Open("sqlite", fmt.Sprintf("file:%s?_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)", cfg.Path))
+	if err != nil {
+		return nil, appError.ErrSQLite.WithError(err).WithMessage("Failed to open sqlite db").Err()
+	}
+
+	// sqlite allows a single writer, the single connection serializes the queries instead of failing them as busy
+	db.SetMaxOpenConns(1)
+
+	// ping db
+	if err = db.Ping(); err != nil {
+		return nil, appError.ErrSQLite.WithError(err).WithMessage("Failed to ping db").Err()
+	}
+
+	return db, nil
+}
+
+func runMigrations(db *sql.DB) error {
+	source, err := iofs.New(migrations, "migrations")
+	if err != nil {
+		return appError.ErrSQLite.WithError(err).WithMessage("Failed to read embedded migrations").Err()
+	}
+
+	driver, err := sqliteMigrate.WithInstance(db, &sqliteMigrate.Config{
+		MigrationsTable: migrationTable,
+	})
+	if err != nil {
+		return appError.ErrSQLite.WithError(err).WithMessage("Failed to create migration driver").Err()
+	}
+
+	m, err := migrate.NewWithInstance("iofs", source, "sqlite", driver)
+	if err != nil {
+		return appError.ErrSQLite.WithError(err).WithMessage("Failed to create migration driver").Err()
+	}
+
+	if err = m.Up(); err != nil {
+		if !errors.Is(err, migrate.ErrNoChange) {
+			return appError.ErrSQLite.WithError(err).WithMessage("Failed to run migrations").Err()
+		}
+	}
+	return nil
+}
+
+func (r *SQLiteRepository) Close() {
+	if err := r.conn.Close(); err != nil {
+		log.Error().Err(err).Msg("Failed to close sqlite db")
+	}
+}
+
+func (r *SQLiteRepository) Ping(ctx context.Context) error {
+	return r.conn.PingContext(ctx)
+}
@@ -0,0 +1,44 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"github.com/cybericebox/wireguard/internal/delivery/repository/postgres"
+)
+
+type (
+	// dbtx is the database or the transaction the queries run in
+	dbtx interface {
+		ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+		QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+		QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+		PrepareContext(ctx context.Context, query string) (*sql.Stmt, error)
+	}
+)
+
+// InTx runs the function with the queries bound to the transaction, the transaction is committed if the function succeeds.
+// The database has a single connection, so the function must not use the repository outside the transaction.
+func (r *SQLiteRepository) InTx(ctx context.Context, fn func(q postgres.TxQuerier) error) error {
+	return r.withTx(ctx, func(tx dbtx) error {
+		return fn(&SQLiteRepository{db: tx, conn: r.conn, inTx: true})
+	})
+}
+
+// withTx runs the function in the transaction the repository is bound to or in a new one
+func (r *SQLiteRepository) withTx(ctx context.Context, fn func(tx dbtx) error) error {
+	if r.inTx {
+		return fn(r.db)
+	}
+
+	tx, err := r.conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	if err = fn(tx); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
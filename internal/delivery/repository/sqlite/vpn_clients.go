@@ -0,0 +1,441 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"github.com/cybericebox/wireguard/internal/delivery/repository/postgres"
+	"github.com/cybericebox/wireguard/internal/model"
+	"github.com/gofrs/uuid"
+	"net/netip"
+	"strings"
+)
+
+const vpnClientColumns = `user_id,
+       group_id,
+       ip_address,
+       public_key,
+       private_key,
+       laboratory_cidr,
+       banned,
+       updated_at,
+       created_at,
+       node_id,
+       last_handshake_at,
+       ban_reason,
+       expires_at,
+       transfer_quota`
+
+const createVpnClient = `insert into vpn_clients (user_id, group_id, ip_address, ip_number, public_key, private_key, laboratory_cidr, node_id, created_at)
+values (?, ?, ?, ?, ?, ?, ?, ?, ?)`
+
+// sortColumns are the columns of the clients sort orders, the address is sorted by its integer form
+var sortColumns = map[string]string{
+	model.ClientsSortByLastSeen: "last_handshake_at",
+	model.ClientsSortByAddress:  "ip_number",
+	model.ClientsSortByCreated:  "created_at",
+}
+
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+func scanVpnClient(row scanner) (postgres.VpnClient, error) {
+	var i postgres.VpnClient
+	var ipAddress, laboratoryCidr string
+	var updatedAt, lastHandshakeAt, expiresAt sql.NullInt64
+	var createdAt int64
+	if err := row.Scan(
+		&i.UserID,
+		&i.GroupID,
+		&ipAddress,
+		&i.PublicKey,
+		&i.PrivateKey,
+		&laboratoryCidr,
+		&i.Banned,
+		&updatedAt,
+		&createdAt,
+		&i.NodeID,
+		&lastHandshakeAt,
+		&i.BanReason,
+		&expiresAt,
+		&i.TransferQuota,
+	); err != nil {
+		return i, err
+	}
+
+	var err error
+	if i.IpAddress, err = netip.ParsePrefix(ipAddress); err != nil {
+		return i, err
+	}
+	if i.LaboratoryCidr, err = netip.ParsePrefix(laboratoryCidr); err != nil {
+		return i, err
+	}
+	i.UpdatedAt = toTimestamptz(updatedAt)
+	i.CreatedAt = fromNanos(createdAt)
+	i.LastHandshakeAt = toTimestamptz(lastHandshakeAt)
+	i.ExpiresAt = toTimestamptz(expiresAt)
+	return i, nil
+}
+
+func (r *SQLiteRepository) CreateVpnClient(ctx context.Context, arg postgres.CreateVpnClientParams) error {
+	_, err := r.db.ExecContext(ctx, createVpnClient,
+		arg.UserID.String(),
+		arg.GroupID.String(),
+		arg.IpAddress.String(),
+		ipNumber(arg.IpAddress.Addr()),
+		arg.PublicKey,
+		arg.PrivateKey,
+		arg.LaboratoryCidr.String(),
+		arg.NodeID,
+		now(),
+	)
+	return err
+}
+
+// CreateVpnClients inserts the clients in a single transaction, so either all or none of them are created
+func (r *SQLiteRepository) CreateVpnClients(ctx context.Context, arg []postgres.CreateVpnClientsParams) (int64, error) {
+	err := r.withTx(ctx, func(tx dbtx) error {
+		stmt, err := tx.PrepareContext(ctx, createVpnClient)
+		if err != nil {
+			return err
+		}
+		defer stmt.Close()
+
+		createdAt := now()
+		for _, c := range arg {
+			if _, err = stmt.ExecContext(ctx,
+				c.UserID.String(),
+				c.GroupID.String(),
+				c.IpAddress.String(),
+				ipNumber(c.IpAddress.Addr()),
+				c.PublicKey,
+				c.PrivateKey,
+				c.LaboratoryCidr.String(),
+				c.NodeID,
+				createdAt,
+			); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return int64(len(arg)), nil
+}
+
+func (r *SQLiteRepository) GetNodeVPNClients(ctx context.Context, nodeID string) ([]postgres.VpnClient, error) {
+	rows, err := r.db.QueryContext(ctx, fmt.Sprintf(`select %s
+from vpn_clients
+where node_id = ?`, vpnClientColumns), nodeID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []postgres.VpnClient
+	for rows.Next() {
+		i, err := scanVpnClient(rows)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	return items, rows.Err()
+}
+
+func (r *SQLiteRepository) GetVPNClient(ctx context.Context, arg postgres.GetVPNClientParams) (postgres.VpnClient, error) {
+	i, err := scanVpnClient(r.db.QueryRowContext(ctx, fmt.Sprintf(`select %s
+from vpn_clients
+where user_id = ?
+  and group_id = ?`, vpnClientColumns), arg.UserID.String(), arg.GroupID.String()))
+	return i, noRows(err)
+}
+
+// ListVPNClients returns the page of the clients matching the filters in the order of the postgres query
+func (r *SQLiteRepository) ListVPNClients(ctx context.Context, arg postgres.ListVPNClientsParams) ([]postgres.ListVPNClientsRow, error) {
+	conditions := []string{"1 = 1"}
+	var args []any
+
+	if arg.UserID.Valid {
+		conditions = append(conditions, "user_id = ?")
+		args = append(args, arg.UserID.UUID.String())
+	}
+	if arg.GroupID.Valid {
+		conditions = append(conditions, "group_id = ?")
+		args = append(args, arg.GroupID.UUID.String())
+	}
+	if arg.Banned.Valid {
+		conditions = append(conditions, "banned = ?")
+		args = append(args, arg.Banned.Bool)
+	}
+	if arg.OnlineSince.Valid {
+		conditions = append(conditions, "last_handshake_at >= ?")
+		args = append(args, nanos(arg.OnlineSince.Time))
+	}
+	if arg.AddressCidr != nil {
+		cidr := arg.AddressCidr.Masked()
+		first := ipNumber(cidr.Addr())
+		conditions = append(conditions, "ip_number between ? and ?")
+		args = append(args, first, first+int64(1)<<(32-cidr.Bits())-1)
+	}
+	if arg.CreatedAfter.Valid {
+		conditions = append(conditions, "created_at > ?")
+		args = append(args, nanos(arg.CreatedAfter.Time))
+	}
+
+	// sqlite sorts the missing handshakes first in the ascending order and last in the descending one, as the postgres query does
+	order := "user_id, group_id"
+	if column, ok := sortColumns[arg.SortBy]; ok {
+		direction := "asc"
+		if arg.SortDesc {
+			direction = "desc"
+		}
+		order = fmt.Sprintf("%s %s, %s", column, direction, order)
+	}
+
+	args = append(args, arg.PageSize, arg.PageOffset)
+	rows, err := r.db.QueryContext(ctx, fmt.Sprintf(`select user_id,
+       group_id,
+       ip_address,
+       banned,
+       created_at,
+       node_id,
+       last_handshake_at,
+       ban_reason
+from vpn_clients
+where %s
+order by %s
+limit ? offset ?`, strings.Join(conditions, " and "), order), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []postgres.ListVPNClientsRow
+	for rows.Next() {
+		var i postgres.ListVPNClientsRow
+		var ipAddress string
+		var createdAt int64
+		var lastHandshakeAt sql.NullInt64
+		if err = rows.Scan(
+			&i.UserID,
+			&i.GroupID,
+			&ipAddress,
+			&i.Banned,
+			&createdAt,
+			&i.NodeID,
+			&lastHandshakeAt,
+			&i.BanReason,
+		); err != nil {
+			return nil, err
+		}
+		if i.IpAddress, err = netip.ParsePrefix(ipAddress); err != nil {
+			return nil, err
+		}
+		i.CreatedAt = fromNanos(createdAt)
+		i.LastHandshakeAt = toTimestamptz(lastHandshakeAt)
+		items = append(items, i)
+	}
+	return items, rows.Err()
+}
+
+func (r *SQLiteRepository) GetVPNClientNodeID(ctx context.Context, arg postgres.GetVPNClientNodeIDParams) (string, error) {
+	var nodeID string
+	err := r.db.QueryRowContext(ctx, `select node_id
+from vpn_clients
+where user_id = ?
+  and group_id = ?`, arg.UserID.String(), arg.GroupID.String()).Scan(&nodeID)
+	return nodeID, noRows(err)
+}
+
+func (r *SQLiteRepository) GetVPNGroupNodeID(ctx context.Context, groupID uuid.UUID) (string, error) {
+	var nodeID string
+	err := r.db.QueryRowContext(ctx, `select node_id
+from vpn_clients
+where group_id = ?
+group by node_id
+order by count(*) desc
+limit 1`, groupID.String()).Scan(&nodeID)
+	return nodeID, noRows(err)
+}
+
+// GetVPNClientsWithoutHandshake passes the public keys as the JSON array, so they are bound as a single parameter
+func (r *SQLiteRepository) GetVPNClientsWithoutHandshake(ctx context.Context, arg postgres.GetVPNClientsWithoutHandshakeParams) ([]postgres.GetVPNClientsWithoutHandshakeRow, error) {
+	publicKeys, err := json.Marshal(arg.PublicKeys)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.db.QueryContext(ctx, `select user_id,
+       group_id,
+       public_key
+from vpn_clients
+where node_id = ?
+  and last_handshake_at is null
+  and public_key in (select value from json_each(?))`, arg.NodeID, string(publicKeys))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []postgres.GetVPNClientsWithoutHandshakeRow
+	for rows.Next() {
+		var i postgres.GetVPNClientsWithoutHandshakeRow
+		if err = rows.Scan(&i.UserID, &i.GroupID, &i.PublicKey); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	return items, rows.Err()
+}
+
+func (r *SQLiteRepository) GetVPNGroupLaboratoryCIDRs(ctx context.Context, groupID uuid.UUID) ([]netip.Prefix, error) {
+	rows, err := r.db.QueryContext(ctx, `select distinct laboratory_cidr
+from vpn_clients
+where group_id = ?`, groupID.String())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []netip.Prefix
+	for rows.Next() {
+		var laboratoryCidr string
+		if err = rows.Scan(&laboratoryCidr); err != nil {
+			return nil, err
+		}
+		prefix, err := netip.ParsePrefix(laboratoryCidr)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, prefix)
+	}
+	return items, rows.Err()
+}
+
+func (r *SQLiteRepository) UpdateVPNClientsBanStatus(ctx context.Context, arg postgres.UpdateVPNClientsBanStatusParams) (int64, error) {
+	result, err := r.db.ExecContext(ctx, `update vpn_clients
+set banned     = ?,
+    ban_reason = ?,
+    updated_at = ?
+where node_id = ?
+  and user_id = coalesce(?, user_id)
+  and group_id = coalesce(?, group_id)
+  and banned <> ?`,
+		arg.Banned,
+		arg.BanReason,
+		now(),
+		arg.NodeID,
+		nullUUID(arg.UserID),
+		nullUUID(arg.GroupID),
+		arg.Banned,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+func (r *SQLiteRepository) UpdateVPNClientsLimits(ctx context.Context, arg postgres.UpdateVPNClientsLimitsParams) (int64, error) {
+	result, err := r.db.ExecContext(ctx, `update vpn_clients
+set expires_at     = ?,
+    transfer_quota = ?,
+    updated_at     = ?
+where node_id = ?
+  and user_id = coalesce(?, user_id)
+  and group_id = coalesce(?, group_id)`,
+		fromTimestamptz(arg.ExpiresAt),
+		arg.TransferQuota,
+		now(),
+		arg.NodeID,
+		nullUUID(arg.UserID),
+		nullUUID(arg.GroupID),
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// GetVPNClientsTransfer returns the transfer of the node clients with the quota, it is the sum of their sessions since they were created,
+// the sessions start at the second of the handshake
+func (r *SQLiteRepository) GetVPNClientsTransfer(ctx context.Context, nodeID string) ([]postgres.GetVPNClientsTransferRow, error) {
+	rows, err := r.db.QueryContext(ctx, `select c.user_id,
+       c.group_id,
+       coalesce(sum(s.transfer_rx + s.transfer_tx), 0) as transfer
+from vpn_clients c
+         join vpn_sessions s on s.user_id = c.user_id and s.group_id = c.group_id and s.started_at >= c.created_at / 1000000000 * 1000000000
+where c.node_id = ?
+  and c.transfer_quota > 0
+group by c.user_id, c.group_id`, nodeID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []postgres.GetVPNClientsTransferRow
+	for rows.Next() {
+		var i postgres.GetVPNClientsTransferRow
+		if err = rows.Scan(&i.UserID, &i.GroupID, &i.Transfer); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	return items, rows.Err()
+}
+
+// UpdateVPNClientsLastHandshake stores the last handshakes of the node clients by their public keys in a single transaction
+func (r *SQLiteRepository) UpdateVPNClientsLastHandshake(ctx context.Context, arg postgres.UpdateVPNClientsLastHandshakeParams) (int64, error) {
+	if len(arg.PublicKeys) != len(arg.LastHandshakes) {
+		return 0, fmt.Errorf("got %d public keys and %d last handshakes", len(arg.PublicKeys), len(arg.LastHandshakes))
+	}
+
+	var updated int64
+	err := r.withTx(ctx, func(tx dbtx) error {
+		stmt, err := tx.PrepareContext(ctx, `update vpn_clients
+set last_handshake_at = ?
+where node_id = ?
+  and public_key = ?`)
+		if err != nil {
+			return err
+		}
+		defer stmt.Close()
+
+		for i, publicKey := range arg.PublicKeys {
+			result, err := stmt.ExecContext(ctx, nanos(arg.LastHandshakes[i]), arg.NodeID, publicKey)
+			if err != nil {
+				return err
+			}
+			affected, err := result.RowsAffected()
+			if err != nil {
+				return err
+			}
+			updated += affected
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return updated, nil
+}
+
+func (r *SQLiteRepository) DeleteVPNClients(ctx context.Context, arg postgres.DeleteVPNClientsParams) (int64, error) {
+	result, err := r.db.ExecContext(ctx, `delete
+from vpn_clients
+where node_id = ?
+  and user_id = coalesce(?, user_id)
+  and group_id = coalesce(?, group_id)`,
+		arg.NodeID,
+		nullUUID(arg.UserID),
+		nullUUID(arg.GroupID),
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
@@ -0,0 +1,91 @@
+package sqlite
+
+import (
+	"context"
+	"github.com/cybericebox/wireguard/internal/delivery/repository/postgres"
+	"github.com/gofrs/uuid"
+	"net/netip"
+)
+
+const vpnDNSRecordColumns = `id,
+       group_id,
+       name,
+       address,
+       laboratory_cidr,
+       created_at`
+
+func scanVpnDNSRecord(row scanner) (postgres.VpnDnsRecord, error) {
+	var i postgres.VpnDnsRecord
+	var address, laboratoryCidr string
+	var createdAt int64
+	if err := row.Scan(
+		&i.ID,
+		&i.GroupID,
+		&i.Name,
+		&address,
+		&laboratoryCidr,
+		&createdAt,
+	); err != nil {
+		return i, err
+	}
+
+	var err error
+	if i.Address, err = netip.ParseAddr(address); err != nil {
+		return i, err
+	}
+	if i.LaboratoryCidr, err = netip.ParsePrefix(laboratoryCidr); err != nil {
+		return i, err
+	}
+	i.CreatedAt = fromNanos(createdAt)
+	return i, nil
+}
+
+func (r *SQLiteRepository) CreateVPNDNSRecord(ctx context.Context, arg postgres.CreateVPNDNSRecordParams) (postgres.VpnDnsRecord, error) {
+	row := r.db.QueryRowContext(ctx, `insert into vpn_dns_records (id, group_id, name, address, laboratory_cidr, created_at)
+values (?, ?, ?, ?, ?, ?)
+on conflict (group_id, name, address) do nothing
+returning `+vpnDNSRecordColumns,
+		arg.ID.String(),
+		arg.GroupID.String(),
+		arg.Name,
+		arg.Address.String(),
+		arg.LaboratoryCidr.String(),
+		now(),
+	)
+	i, err := scanVpnDNSRecord(row)
+	return i, noRows(err)
+}
+
+func (r *SQLiteRepository) ListVPNDNSRecords(ctx context.Context, groupID uuid.NullUUID) ([]postgres.VpnDnsRecord, error) {
+	rows, err := r.db.QueryContext(ctx, `select `+vpnDNSRecordColumns+`
+from vpn_dns_records
+where (?1 is null or group_id = ?1)
+order by name, address`, nullUUID(groupID))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []postgres.VpnDnsRecord
+	for rows.Next() {
+		i, err := scanVpnDNSRecord(rows)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	return items, rows.Err()
+}
+
+func (r *SQLiteRepository) DeleteVPNDNSRecord(ctx context.Context, arg postgres.DeleteVPNDNSRecordParams) (postgres.VpnDnsRecord, error) {
+	row := r.db.QueryRowContext(ctx, `delete
+from vpn_dns_records
+where id = ?
+  and group_id = ?
+returning `+vpnDNSRecordColumns,
+		arg.ID.String(),
+		arg.GroupID.String(),
+	)
+	i, err := scanVpnDNSRecord(row)
+	return i, noRows(err)
+}
@@ -0,0 +1,116 @@
+package sqlite
+
+import (
+	"context"
+	"github.com/cybericebox/wireguard/internal/delivery/repository/postgres"
+	"time"
+)
+
+func scanVpnNode(row scanner) (postgres.VpnNode, error) {
+	var i postgres.VpnNode
+	var heartbeatAt, createdAt int64
+	if err := row.Scan(
+		&i.ID,
+		&i.Endpoint,
+		&i.PublicKey,
+		&i.GrpcEndpoint,
+		&heartbeatAt,
+		&createdAt,
+	); err != nil {
+		return i, err
+	}
+	i.HeartbeatAt = fromNanos(heartbeatAt)
+	i.CreatedAt = fromNanos(createdAt)
+	return i, nil
+}
+
+func (r *SQLiteRepository) UpsertVPNNode(ctx context.Context, arg postgres.UpsertVPNNodeParams) error {
+	heartbeatAt := now()
+	_, err := r.db.ExecContext(ctx, `insert into vpn_nodes (id, endpoint, public_key, grpc_endpoint, heartbeat_at, created_at)
+values (?, ?, ?, ?, ?, ?)
+on conflict (id) do update
+    set endpoint      = excluded.endpoint,
+        public_key    = excluded.public_key,
+        grpc_endpoint = excluded.grpc_endpoint,
+        heartbeat_at  = excluded.heartbeat_at`,
+		arg.ID,
+		arg.Endpoint,
+		arg.PublicKey,
+		arg.GrpcEndpoint,
+		heartbeatAt,
+		heartbeatAt,
+	)
+	return err
+}
+
+func (r *SQLiteRepository) UpdateVPNNodeHeartbeat(ctx context.Context, id string) (int64, error) {
+	result, err := r.db.ExecContext(ctx, `update vpn_nodes
+set heartbeat_at = ?
+where id = ?`, now(), id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+func (r *SQLiteRepository) GetVPNNode(ctx context.Context, id string) (postgres.VpnNode, error) {
+	i, err := scanVpnNode(r.db.QueryRowContext(ctx, `select id,
+       endpoint,
+       public_key,
+       grpc_endpoint,
+       heartbeat_at,
+       created_at
+from vpn_nodes
+where id = ?`, id))
+	return i, noRows(err)
+}
+
+func (r *SQLiteRepository) GetAliveVPNNodes(ctx context.Context, heartbeatAt time.Time) ([]postgres.VpnNode, error) {
+	rows, err := r.db.QueryContext(ctx, `select id,
+       endpoint,
+       public_key,
+       grpc_endpoint,
+       heartbeat_at,
+       created_at
+from vpn_nodes
+where heartbeat_at > ?
+order by id`, nanos(heartbeatAt))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []postgres.VpnNode
+	for rows.Next() {
+		i, err := scanVpnNode(rows)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	return items, rows.Err()
+}
+
+func (r *SQLiteRepository) GetAliveVPNNodesLoad(ctx context.Context, heartbeatAt time.Time) ([]postgres.GetAliveVPNNodesLoadRow, error) {
+	rows, err := r.db.QueryContext(ctx, `select n.id,
+       count(c.user_id) as clients
+from vpn_nodes n
+         left join vpn_clients c on c.node_id = n.id
+where n.heartbeat_at > ?
+group by n.id
+order by clients, n.id`, nanos(heartbeatAt))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []postgres.GetAliveVPNNodesLoadRow
+	for rows.Next() {
+		var i postgres.GetAliveVPNNodesLoadRow
+		if err = rows.Scan(&i.ID, &i.Clients); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	return items, rows.Err()
+}
@@ -0,0 +1,110 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"github.com/cybericebox/wireguard/internal/delivery/repository/postgres"
+)
+
+func (r *SQLiteRepository) CreateVPNSession(ctx context.Context, arg postgres.CreateVPNSessionParams) (int64, error) {
+	result, err := r.db.ExecContext(ctx, `insert into vpn_sessions (user_id, group_id, node_id, endpoint, transfer_rx, transfer_tx, started_at, last_active_at)
+values (?, ?, ?, ?, ?, ?, ?, ?)`,
+		arg.UserID.String(),
+		arg.GroupID.String(),
+		arg.NodeID,
+		arg.Endpoint,
+		arg.TransferRx,
+		arg.TransferTx,
+		nanos(arg.StartedAt),
+		nanos(arg.LastActiveAt),
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.LastInsertId()
+}
+
+func (r *SQLiteRepository) UpdateVPNSession(ctx context.Context, arg postgres.UpdateVPNSessionParams) error {
+	_, err := r.db.ExecContext(ctx, `update vpn_sessions
+set endpoint       = ?,
+    transfer_rx    = ?,
+    transfer_tx    = ?,
+    last_active_at = ?,
+    ended_at       = ?
+where id = ?`,
+		arg.Endpoint,
+		arg.TransferRx,
+		arg.TransferTx,
+		nanos(arg.LastActiveAt),
+		fromTimestamptz(arg.EndedAt),
+		arg.ID,
+	)
+	return err
+}
+
+func (r *SQLiteRepository) EndVPNNodeSessions(ctx context.Context, nodeID string) (int64, error) {
+	result, err := r.db.ExecContext(ctx, `update vpn_sessions
+set ended_at = last_active_at
+where node_id = ?
+  and ended_at is null`, nodeID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+func (r *SQLiteRepository) GetVPNClientSessions(ctx context.Context, arg postgres.GetVPNClientSessionsParams) ([]postgres.VpnSession, error) {
+	rows, err := r.db.QueryContext(ctx, `select id,
+       user_id,
+       group_id,
+       node_id,
+       endpoint,
+       transfer_rx,
+       transfer_tx,
+       started_at,
+       last_active_at,
+       ended_at
+from vpn_sessions
+where user_id = ?
+  and group_id = ?
+  and started_at >= ?
+  and started_at <= ?
+order by started_at desc, id desc
+limit ?`,
+		arg.UserID.String(),
+		arg.GroupID.String(),
+		nanos(arg.Since),
+		nanos(arg.Until),
+		arg.RowLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []postgres.VpnSession
+	for rows.Next() {
+		var i postgres.VpnSession
+		var startedAt, lastActiveAt int64
+		var endedAt sql.NullInt64
+		if err = rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.GroupID,
+			&i.NodeID,
+			&i.Endpoint,
+			&i.TransferRx,
+			&i.TransferTx,
+			&startedAt,
+			&lastActiveAt,
+			&endedAt,
+		); err != nil {
+			return nil, err
+		}
+		i.StartedAt = fromNanos(startedAt)
+		i.LastActiveAt = fromNanos(lastActiveAt)
+		i.EndedAt = toTimestamptz(endedAt)
+		items = append(items, i)
+	}
+	return items, rows.Err()
+}
@@ -0,0 +1,110 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"github.com/cybericebox/wireguard/internal/delivery/repository/postgres"
+)
+
+func (r *SQLiteRepository) CreateVPNWebhookDelivery(ctx context.Context, arg postgres.CreateVPNWebhookDeliveryParams) error {
+	createdAt := now()
+	_, err := r.db.ExecContext(ctx, `insert into vpn_webhook_deliveries (event_id, event_type, url, payload, next_attempt_at, created_at)
+values (?, ?, ?, ?, ?, ?)`,
+		arg.EventID.String(),
+		arg.EventType,
+		arg.Url,
+		arg.Payload,
+		createdAt,
+		createdAt,
+	)
+	return err
+}
+
+// ClaimVPNWebhookDeliveries needs no row locks, as the writes to the SQLite database are serialized
+func (r *SQLiteRepository) ClaimVPNWebhookDeliveries(ctx context.Context, arg postgres.ClaimVPNWebhookDeliveriesParams) ([]postgres.VpnWebhookDelivery, error) {
+	rows, err := r.db.QueryContext(ctx, `update vpn_webhook_deliveries
+set next_attempt_at = ?
+where id in (select id
+             from vpn_webhook_deliveries
+             where delivered_at is null
+               and attempts < ?
+               and next_attempt_at <= ?
+             order by next_attempt_at, id
+             limit ?)
+returning id, event_id, event_type, url, payload, attempts, next_attempt_at, last_error, delivered_at, created_at`,
+		nanos(arg.LeaseUntil),
+		arg.MaxAttempts,
+		nanos(arg.Now),
+		arg.RowLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []postgres.VpnWebhookDelivery
+	for rows.Next() {
+		var i postgres.VpnWebhookDelivery
+		var nextAttemptAt, createdAt int64
+		var deliveredAt sql.NullInt64
+		if err = rows.Scan(
+			&i.ID,
+			&i.EventID,
+			&i.EventType,
+			&i.Url,
+			&i.Payload,
+			&i.Attempts,
+			&nextAttemptAt,
+			&i.LastError,
+			&deliveredAt,
+			&createdAt,
+		); err != nil {
+			return nil, err
+		}
+		i.NextAttemptAt = fromNanos(nextAttemptAt)
+		i.DeliveredAt = toTimestamptz(deliveredAt)
+		i.CreatedAt = fromNanos(createdAt)
+		items = append(items, i)
+	}
+	return items, rows.Err()
+}
+
+func (r *SQLiteRepository) MarkVPNWebhookDelivered(ctx context.Context, arg postgres.MarkVPNWebhookDeliveredParams) error {
+	_, err := r.db.ExecContext(ctx, `update vpn_webhook_deliveries
+set attempts     = attempts + 1,
+    last_error   = '',
+    delivered_at = ?
+where id = ?`, nanos(arg.DeliveredAt), arg.ID)
+	return err
+}
+
+func (r *SQLiteRepository) FailVPNWebhookDelivery(ctx context.Context, arg postgres.FailVPNWebhookDeliveryParams) error {
+	_, err := r.db.ExecContext(ctx, `update vpn_webhook_deliveries
+set attempts        = attempts + 1,
+    next_attempt_at = ?,
+    last_error      = ?
+where id = ?`, nanos(arg.NextAttemptAt), arg.LastError, arg.ID)
+	return err
+}
+
+func (r *SQLiteRepository) ReplayVPNWebhookDeliveries(ctx context.Context, arg postgres.ReplayVPNWebhookDeliveriesParams) (int64, error) {
+	result, err := r.db.ExecContext(ctx, `update vpn_webhook_deliveries
+set attempts        = 0,
+    next_attempt_at = ?1,
+    last_error      = '',
+    delivered_at    = null
+where created_at >= ?2
+  and created_at <= ?3
+  and (?4 = '' or event_type = ?4)
+  and (not ?5 or delivered_at is null)`,
+		nanos(arg.Now),
+		nanos(arg.Since),
+		nanos(arg.Until),
+		arg.EventType,
+		arg.UndeliveredOnly,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
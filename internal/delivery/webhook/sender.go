@@ -0,0 +1,91 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"github.com/cybericebox/wireguard/internal/config"
+	"github.com/cybericebox/wireguard/internal/model"
+	"github.com/cybericebox/wireguard/pkg/appError"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Headers of the webhook requests
+const (
+	HeaderEventID   = "X-Webhook-ID"
+	HeaderEventType = "X-Webhook-Event"
+	HeaderTimestamp = "X-Webhook-Timestamp"
+	// HeaderSignature is "sha256=" and the hex HMAC-SHA256 of the timestamp, "." and the body by the secret
+	HeaderSignature = "X-Webhook-Signature"
+
+	signaturePrefix = "sha256="
+	// maxErrorBodySize is the size of the rejecting response kept as the delivery error
+	maxErrorBodySize = 512
+)
+
+type (
+	// Sender posts the events to the webhook URLs, the requests are signed, so the receivers can verify their origin
+	Sender struct {
+		config *config.WebhooksConfig
+		client *http.Client
+	}
+
+	Dependencies struct {
+		Config *config.WebhooksConfig
+	}
+)
+
+func NewSender(deps Dependencies) *Sender {
+	return &Sender{
+		config: deps.Config,
+		client: &http.Client{
+			Timeout: deps.Config.Timeout,
+		},
+	}
+}
+
+// Send posts the event to its URL, the event is delivered when the receiver responds with 2xx status.
+// The timestamp is signed with the body, so the receivers can reject the replayed requests which are too old.
+func (s *Sender) Send(ctx context.Context, delivery model.WebhookDelivery) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return appError.ErrWebhook.WithError(err).WithMessage("Failed to create webhook request").Err()
+	}
+
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderEventID, delivery.EventID.String())
+	req.Header.Set(HeaderEventType, delivery.EventType)
+	req.Header.Set(HeaderTimestamp, timestamp)
+	req.Header.Set(HeaderSignature, Sign(s.config.Secret, timestamp, delivery.Payload))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return appError.ErrWebhook.WithError(err).WithMessage("Failed to send webhook request").Err()
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodySize))
+		return appError.ErrWebhookRejected.WithContext("status", resp.Status).WithContext("body", string(body)).Err()
+	}
+
+	// the body is drained, so the connection is reused
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	return nil
+}
+
+// Sign returns the signature header of the body sent at the timestamp
+func Sign(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return signaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
@@ -1,18 +1,331 @@
 package model
 
-import "github.com/gofrs/uuid"
+import (
+	"github.com/gofrs/uuid"
+	"time"
+)
+
+// Health check names
+const (
+	HealthCheckDatabase   = "database"
+	HealthCheckInterface  = "interface"
+	HealthCheckListenPort = "listenPort"
+	HealthCheckFirewall   = "firewall"
+	HealthCheckReconcile  = "reconcile"
+)
+
+// Operations on clients
+const (
+	ClientsOperationDelete = "delete"
+	ClientsOperationBan    = "ban"
+	ClientsOperationUnBan  = "unBan"
+	// ClientsOperationSetLimits sets the expiration and the transfer quota of the clients
+	ClientsOperationSetLimits = "setLimits"
+)
+
+// Ban reasons of the clients blocked by their limits
+const (
+	ClientBanReasonExpired       = "expired"
+	ClientBanReasonQuotaExceeded = "transfer quota exceeded"
+)
+
+// Sort orders of clients
+const (
+	ClientsSortByCreated  = "created"
+	ClientsSortByLastSeen = "lastSeen"
+	ClientsSortByAddress  = "address"
+)
+
+// Resources changed by the planned operations
+const (
+	PlanResourceIP        = "ip"
+	PlanResourcePeer      = "peer"
+	PlanResourceNATRule   = "natRule"
+	PlanResourceBlockRule = "blockRule"
+	PlanResourceDatabase  = "database"
+)
+
+// Changes of the planned operations
+const (
+	PlanActionAdd    = "add"
+	PlanActionUpdate = "update"
+	PlanActionDelete = "delete"
+)
+
+// Audited actions
+const (
+	AuditActionGetClientConfig  = "getClientConfig"
+	AuditActionDeleteClients    = "deleteClients"
+	AuditActionBanClients       = "banClients"
+	AuditActionUnBanClients     = "unBanClients"
+	AuditActionSetClientLimits  = "setClientLimits"
+	AuditActionProvisionClients = "provisionClients"
+	AuditActionCreateDNSRecord  = "createDNSRecord"
+	AuditActionDeleteDNSRecord  = "deleteDNSRecord"
+	AuditActionReplayWebhooks   = "replayWebhooks"
+)
+
+// Webhook event types
+const (
+	WebhookEventClientCreated        = "client.created"
+	WebhookEventClientDeleted        = "client.deleted"
+	WebhookEventClientBanned         = "client.banned"
+	WebhookEventClientUnBanned       = "client.unbanned"
+	WebhookEventClientFirstHandshake = "client.firstHandshake"
+	WebhookEventClientOffline        = "client.offline"
+	// WebhookEventClientQuotaExceeded and WebhookEventClientExpired are queued when the client is blocked by its limits
+	WebhookEventClientQuotaExceeded = "client.quotaExceeded"
+	WebhookEventClientExpired       = "client.expired"
+)
+
+// WebhookEventTypes are all webhook event types
+var WebhookEventTypes = []string{
+	WebhookEventClientCreated,
+	WebhookEventClientDeleted,
+	WebhookEventClientBanned,
+	WebhookEventClientUnBanned,
+	WebhookEventClientFirstHandshake,
+	WebhookEventClientOffline,
+	WebhookEventClientQuotaExceeded,
+	WebhookEventClientExpired,
+}
+
+// Results of lab hostname lookups
+const (
+	// DNSLookupForward means the name is not a lab hostname of any group, it is resolved by the upstreams
+	DNSLookupForward = "forward"
+	// DNSLookupFound means the name is a lab hostname of the client group
+	DNSLookupFound = "found"
+	// DNSLookupRefused means the name is a lab hostname of another group or the source is not an active client
+	DNSLookupRefused = "refused"
+)
+
+// Audit outcomes
+const (
+	AuditOutcomeSuccess = "success"
+	AuditOutcomeFailure = "failure"
+)
 
 type (
 	Client struct {
 		UserID     uuid.UUID
 		GroupID    uuid.UUID
+		NodeID     string
 		Address    string
 		DNS        string
 		PrivateKey string
 		PublicKey  string
 		AllowedIPs string
+		// ServerEndpoint is the endpoint of the VPN server, PublicKey is always the key of the client
+		ServerEndpoint string
+		Banned         bool
+		LastSeen       int64
+		CreatedAt      time.Time
+		// ExpiresAt is the time the client is blocked at, zero means never
+		ExpiresAt time.Time
+		// TransferQuota is the bytes the sessions of the client can transfer before it is blocked, zero means unlimited
+		TransferQuota int64
+	}
+
+	Node struct {
+		ID           string
+		Endpoint     string
+		PublicKey    string
+		GRPCEndpoint string
+		HeartbeatAt  time.Time
+	}
+
+	Health struct {
+		Ready  bool
+		Checks []HealthCheck
+	}
+
+	HealthCheck struct {
+		Name    string
+		Healthy bool
+		Error   string
+	}
+
+	AuditEntry struct {
+		ID         int64
+		Subject    string
+		Action     string
+		UserID     uuid.UUID
+		GroupID    uuid.UUID
+		Parameters map[string]string
+		Outcome    string
+		Error      string
+		Affected   int64
+		NodeID     string
+		CreatedAt  time.Time
+	}
+
+	AuditFilter struct {
+		Subject string
+		Action  string
+		UserID  uuid.UUID
+		GroupID uuid.UUID
+		Since   time.Time
+		Until   time.Time
+		Limit   int32
+	}
+
+	// ClientDetails is the client with its database record and peer state, the private key is never set
+	ClientDetails struct {
+		Client
+		BanReason       string
+		UpdatedAt       time.Time
+		LastHandshakeAt time.Time
+		// PeerEndpoint is the address the client peer last connected from
+		PeerEndpoint string
+		TransferRx   int64
+		TransferTx   int64
+	}
+
+	// ProvisionItem is the client to provision with the destination CIDR of its config
+	ProvisionItem struct {
+		UserID   uuid.UUID
+		GroupID  uuid.UUID
+		DestCIDR string
+	}
+
+	// ProvisionResult is the config of the provisioned client or the error of its provisioning
+	ProvisionResult struct {
+		UserID  uuid.UUID
+		GroupID uuid.UUID
+		Config  string
+		Err     error
+	}
+
+	// ClientNode is the node of the client or the error of getting it
+	ClientNode struct {
+		UserID  uuid.UUID
+		GroupID uuid.UUID
+		Node    *Node
+		Err     error
+	}
+
+	// ClientsQuery selects the page of clients of all nodes
+	ClientsQuery struct {
+		UserID  uuid.UUID
+		GroupID uuid.UUID
+		// Banned filters the clients by the ban status if it is set
+		Banned *bool
+		// OnlineWithin filters the clients with the handshake within the duration if it is set
+		OnlineWithin time.Duration
+		// AddressCIDR filters the clients with the address within the CIDR if it is set
+		AddressCIDR    string
+		CreatedAfter   time.Time
+		SortBy         string
+		SortDescending bool
+		PageSize       int32
+		PageToken      string
+	}
+
+	// ClientsPage is the page of clients, NextPageToken is empty on the last page
+	ClientsPage struct {
+		Clients       []*Client
+		NextPageToken string
+	}
+
+	// Peer is the peer of the server interface
+	Peer struct {
+		PublicKey  string
+		Endpoint   string
+		AllowedIPs string
+		// LatestHandshake is the unix time of the last handshake in seconds, it is 0 before the first handshake
+		LatestHandshake int
+		TransferRx      int64
+		TransferTx      int64
+	}
+
+	// FirewallRule is the service-owned firewall rule of the client
+	FirewallRule struct {
+		// ID is the client ID the rule belongs to
+		ID          string
+		Source      string
+		Destination string
+		// Spec is the rule as the firewall lists it, the rule is deleted exactly by it
+		Spec string
+	}
+
+	// FirewallRules are the service-owned NAT and blocking rules
+	FirewallRules struct {
+		NAT   []FirewallRule
+		Block []FirewallRule
+	}
+
+	// PlannedChange is the change the operation would make to a resource of the client
+	PlannedChange struct {
+		UserID   uuid.UUID
+		GroupID  uuid.UUID
+		NodeID   string
+		Resource string
+		Action   string
+		// Target identifies the resource, e.g. the address, the peer public key or the rule command
+		Target string
+	}
+
+	// DNSRecord is the lab hostname of the group, it is resolved only for the clients of the group
+	DNSRecord struct {
+		ID      uuid.UUID
+		GroupID uuid.UUID
+		// Name is the fully qualified lowercase name with the trailing dot
+		Name    string
+		Address string
+		// LaboratoryCIDR is the lab network of the group the address belongs to
+		LaboratoryCIDR string
+		CreatedAt      time.Time
+	}
+
+	// DNSLookup is the result of the lab hostname lookup with the addresses of the found name
+	DNSLookup struct {
+		Result    string
+		Addresses []string
+	}
+
+	// Session is the connection of the client peer from the handshake until the peer goes offline
+	Session struct {
+		ID      int64
+		UserID  uuid.UUID
+		GroupID uuid.UUID
+		NodeID  string
+		// Endpoint is the remote address the client peer connected from
 		Endpoint   string
-		Banned     bool
-		LastSeen   int64
+		TransferRx int64
+		TransferTx int64
+		StartedAt  time.Time
+		// LastActiveAt is the last time the transfer of the session was sampled, EndedAt is zero while the session is open
+		LastActiveAt time.Time
+		EndedAt      time.Time
+	}
+
+	// SessionsFilter selects the sessions of the client by their start, the zero times are unbounded
+	SessionsFilter struct {
+		UserID  uuid.UUID
+		GroupID uuid.UUID
+		Since   time.Time
+		Until   time.Time
+		Limit   int32
+	}
+
+	// WebhookDelivery is the event queued for delivery to the webhook URL
+	WebhookDelivery struct {
+		ID        int64
+		EventID   uuid.UUID
+		EventType string
+		URL       string
+		// Payload is the JSON body of the request, it is signed as it is
+		Payload  []byte
+		Attempts int32
+	}
+
+	// WebhookReplayFilter selects the queued events to deliver again by their creation, the zero times are unbounded
+	WebhookReplayFilter struct {
+		EventType string
+		Since     time.Time
+		Until     time.Time
+		// UndeliveredOnly replays only the events which have not been delivered, e.g. after the attempts ran out
+		UndeliveredOnly bool
 	}
 )
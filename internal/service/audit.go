@@ -0,0 +1,102 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/cybericebox/wireguard/internal/delivery/repository/postgres"
+	"github.com/cybericebox/wireguard/internal/model"
+	"github.com/cybericebox/wireguard/pkg/appError"
+	"github.com/gofrs/uuid"
+	"github.com/rs/zerolog/log"
+	"time"
+)
+
+const (
+	// defaultAuditLogLimit is the number of entries returned when the limit is not set
+	defaultAuditLogLimit = 100
+	// maxAuditLogLimit is the maximum number of entries returned at once
+	maxAuditLogLimit = 1000
+)
+
+// RecordAuditEntry stores the administrative action in the audit log
+func (s *Service) RecordAuditEntry(ctx context.Context, entry *model.AuditEntry) error {
+	parameters, err := json.Marshal(entry.Parameters)
+	if err != nil {
+		return appError.ErrPlatform.WithError(err).WithMessage("Failed to marshal audit entry parameters").Err()
+	}
+
+	log.Debug().Str("subject", entry.Subject).Str("action", entry.Action).Str("outcome", entry.Outcome).Msg("Recording audit entry")
+	if err = s.repository.CreateAuditLogEntry(ctx, postgres.CreateAuditLogEntryParams{
+		Subject:    entry.Subject,
+		Action:     entry.Action,
+		UserID:     nullUUID(entry.UserID),
+		GroupID:    nullUUID(entry.GroupID),
+		Parameters: parameters,
+		Outcome:    entry.Outcome,
+		Error:      entry.Error,
+		Affected:   entry.Affected,
+		NodeID:     s.config.Node.ID,
+	}); err != nil {
+		return appError.ErrPlatform.WithWrappedError(appError.ErrPostgres.WithError(err)).WithMessage("Failed to record audit entry").Err()
+	}
+
+	return nil
+}
+
+// GetAuditLog returns the audit log entries matching the filter, the newest first
+func (s *Service) GetAuditLog(ctx context.Context, filter model.AuditFilter) ([]*model.AuditEntry, error) {
+	if filter.Until.IsZero() {
+		filter.Until = time.Now()
+	}
+
+	if filter.Limit <= 0 {
+		filter.Limit = defaultAuditLogLimit
+	}
+
+	if filter.Limit > maxAuditLogLimit {
+		filter.Limit = maxAuditLogLimit
+	}
+
+	log.Debug().Str("subject", filter.Subject).Str("action", filter.Action).Msg("Getting audit log from db")
+	entries, err := s.repository.GetAuditLog(ctx, postgres.GetAuditLogParams{
+		Subject:  filter.Subject,
+		Action:   filter.Action,
+		UserID:   nullUUID(filter.UserID),
+		GroupID:  nullUUID(filter.GroupID),
+		Since:    filter.Since,
+		Until:    filter.Until,
+		RowLimit: filter.Limit,
+	})
+	if err != nil {
+		return nil, appError.ErrPlatform.WithWrappedError(appError.ErrPostgres.WithError(err)).WithMessage("Failed to get audit log").Err()
+	}
+
+	result := make([]*model.AuditEntry, 0, len(entries))
+	for _, e := range entries {
+		parameters := make(map[string]string)
+		if err = json.Unmarshal(e.Parameters, &parameters); err != nil {
+			return nil, appError.ErrPlatform.WithError(err).WithMessage("Failed to unmarshal audit entry parameters").Err()
+		}
+
+		result = append(result, &model.AuditEntry{
+			ID:         e.ID,
+			Subject:    e.Subject,
+			Action:     e.Action,
+			UserID:     e.UserID.UUID,
+			GroupID:    e.GroupID.UUID,
+			Parameters: parameters,
+			Outcome:    e.Outcome,
+			Error:      e.Error,
+			Affected:   e.Affected,
+			NodeID:     e.NodeID,
+			CreatedAt:  e.CreatedAt,
+		})
+	}
+
+	return result, nil
+}
+
+// nullUUID treats the nil UUID as the absence of the value
+func nullUUID(id uuid.UUID) uuid.NullUUID {
+	return uuid.NullUUID{UUID: id, Valid: id != uuid.Nil}
+}
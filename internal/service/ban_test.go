@@ -0,0 +1,60 @@
+package service_test
+
+import (
+	"context"
+	"github.com/cybericebox/wireguard/pkg/controller/grpc/client"
+	"github.com/cybericebox/wireguard/pkg/controller/grpc/protobuf"
+	"github.com/cybericebox/wireguard/pkg/wgtest"
+	"github.com/gofrs/uuid"
+	"testing"
+)
+
+func TestBanKeepsReasonOfBannedClients(t *testing.T) {
+	h := wgtest.NewHarness(t)
+	c := h.Client(t, []string{client.RoleAdmin}, "")
+	ctx := context.Background()
+
+	groupID := uuid.Must(uuid.NewV4()).String()
+	banned, other := uuid.Must(uuid.NewV4()).String(), uuid.Must(uuid.NewV4()).String()
+	for _, userID := range []string{banned, other} {
+		if _, err := c.GetClientConfig(ctx, &protobuf.ClientConfigRequest{UserID: userID, GroupID: groupID, DestCIDR: "10.0.0.0/24"}); err != nil {
+			t.Fatalf("get client config: %v", err)
+		}
+	}
+
+	if _, err := c.BanClients(ctx, &protobuf.ClientsRequest{UserID: banned, GroupID: groupID, BanReason: "cheating"}); err != nil {
+		t.Fatalf("ban client: %v", err)
+	}
+
+	dryRun, err := c.BanClients(ctx, &protobuf.ClientsRequest{GroupID: groupID, BanReason: "group closed", DryRun: true})
+	if err != nil {
+		t.Fatalf("dry run group ban: %v", err)
+	}
+
+	resp, err := c.BanClients(ctx, &protobuf.ClientsRequest{GroupID: groupID, BanReason: "group closed"})
+	if err != nil {
+		t.Fatalf("ban group: %v", err)
+	}
+
+	if resp.GetClientsAffected() != 1 || resp.GetClientsAffected() != dryRun.GetClientsAffected() {
+		t.Fatalf("expected 1 affected client as the dry run, got %d and %d by the dry run", resp.GetClientsAffected(), dryRun.GetClientsAffected())
+	}
+
+	for userID, reason := range map[string]string{banned: "cheating", other: "group closed"} {
+		details, err := c.GetClient(ctx, &protobuf.ClientRequest{UserID: userID, GroupID: groupID})
+		if err != nil {
+			t.Fatalf("get client: %v", err)
+		}
+		if !details.GetClient().GetBanned() || details.GetClient().GetBanReason() != reason {
+			t.Errorf("expected client banned for %q, got banned %v for %q", reason, details.GetClient().GetBanned(), details.GetClient().GetBanReason())
+		}
+	}
+
+	unbanned, err := c.UnBanClients(ctx, &protobuf.ClientsRequest{GroupID: groupID})
+	if err != nil {
+		t.Fatalf("unban group: %v", err)
+	}
+	if unbanned.GetClientsAffected() != 2 {
+		t.Fatalf("expected 2 unbanned clients, got %d", unbanned.GetClientsAffected())
+	}
+}
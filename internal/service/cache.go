@@ -0,0 +1,147 @@
+package service
+
+import (
+	"github.com/cybericebox/wireguard/internal/model"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+type (
+	// clientCache is the cache of the node clients by client ID.
+	// Readers load the current snapshot without locking and get copies of the clients,
+	// writers replace the snapshot, so the clients of a snapshot are never changed.
+	clientCache struct {
+		// m serializes the writers
+		m        sync.Mutex
+		snapshot atomic.Pointer[clientSnapshot]
+	}
+
+	clientSnapshot struct {
+		clients map[string]*model.Client
+		// byAddress are the clients by the address without the mask, it is rebuilt with every snapshot
+		byAddress map[string]*model.Client
+	}
+)
+
+func newClientCache() *clientCache {
+	c := &clientCache{}
+	c.snapshot.Store(newClientSnapshot(map[string]*model.Client{}))
+	return c
+}
+
+func newClientSnapshot(clients map[string]*model.Client) *clientSnapshot {
+	byAddress := make(map[string]*model.Client, len(clients))
+	for _, client := range clients {
+		ip, _, _ := strings.Cut(client.Address, "/")
+		byAddress[ip] = client
+	}
+	return &clientSnapshot{clients: clients, byAddress: byAddress}
+}
+
+func (c *clientCache) load() map[string]*model.Client {
+	return c.snapshot.Load().clients
+}
+
+// get returns the copy of the client
+func (c *clientCache) get(id string) (*model.Client, bool) {
+	client, ok := c.load()[id]
+	if !ok {
+		return nil, false
+	}
+	clientCopy := *client
+	return &clientCopy, true
+}
+
+// has reports whether the client is cached
+func (c *clientCache) has(id string) bool {
+	_, ok := c.load()[id]
+	return ok
+}
+
+// list returns the copies of the clients which pass the filter, all clients if the filter is nil
+func (c *clientCache) list(filter func(id string, client *model.Client) bool) []*model.Client {
+	snapshot := c.load()
+	clients := make([]*model.Client, 0, len(snapshot))
+	for id, client := range snapshot {
+		if filter != nil && !filter(id, client) {
+			continue
+		}
+		clientCopy := *client
+		clients = append(clients, &clientCopy)
+	}
+	return clients
+}
+
+// update replaces the snapshot with the copy changed by the function
+func (c *clientCache) update(fn func(clients map[string]*model.Client)) {
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	current := c.load()
+	next := make(map[string]*model.Client, len(current))
+	for id, client := range current {
+		next[id] = client
+	}
+	fn(next)
+	c.snapshot.Store(newClientSnapshot(next))
+}
+
+// put caches the copies of the clients
+func (c *clientCache) put(clients ...*model.Client) {
+	c.update(func(snapshot map[string]*model.Client) {
+		for _, client := range clients {
+			clientCopy := *client
+			snapshot[getClientID(client.UserID, client.GroupID)] = &clientCopy
+		}
+	})
+}
+
+// remove deletes the clients from the cache
+func (c *clientCache) remove(clients ...*model.Client) {
+	c.update(func(snapshot map[string]*model.Client) {
+		for _, client := range clients {
+			delete(snapshot, getClientID(client.UserID, client.GroupID))
+		}
+	})
+}
+
+// setBanned replaces the cached clients with the copies of the changed ban status
+func (c *clientCache) setBanned(banned bool, clients ...*model.Client) {
+	c.update(func(snapshot map[string]*model.Client) {
+		for _, client := range clients {
+			id := getClientID(client.UserID, client.GroupID)
+			if cached, ok := snapshot[id]; ok {
+				clientCopy := *cached
+				clientCopy.Banned = banned
+				snapshot[id] = &clientCopy
+			}
+		}
+	})
+}
+
+// setLimits replaces the cached clients with the copies of the changed limits
+func (c *clientCache) setLimits(expiresAt time.Time, transferQuota int64, clients ...*model.Client) {
+	c.update(func(snapshot map[string]*model.Client) {
+		for _, client := range clients {
+			id := getClientID(client.UserID, client.GroupID)
+			if cached, ok := snapshot[id]; ok {
+				clientCopy := *cached
+				clientCopy.ExpiresAt = expiresAt
+				clientCopy.TransferQuota = transferQuota
+				snapshot[id] = &clientCopy
+			}
+		}
+	})
+}
+
+// findByAddress returns the copy of the client of the address, the address is given without the mask
+func (c *clientCache) findByAddress(address string) (*model.Client, bool) {
+	client, ok := c.snapshot.Load().byAddress[address]
+	if !ok {
+		return nil, false
+	}
+	clientCopy := *client
+	return &clientCopy, true
+}
@@ -0,0 +1,157 @@
+package service
+
+import (
+	"context"
+	"github.com/cybericebox/lib/pkg/wgKeyGen"
+	"github.com/cybericebox/wireguard/internal/config"
+	"github.com/cybericebox/wireguard/internal/delivery/repository/postgres"
+	"github.com/cybericebox/wireguard/internal/model"
+	"github.com/gofrs/uuid"
+	"net/netip"
+	"strings"
+	"testing"
+)
+
+func testClient() *model.Client {
+	return &model.Client{
+		UserID:     uuid.Must(uuid.NewV4()),
+		GroupID:    uuid.Must(uuid.NewV4()),
+		Address:    "10.128.0.2/32",
+		PublicKey:  "client-public-key",
+		PrivateKey: "client-private-key",
+		AllowedIPs: "10.0.0.0/24",
+		DNS:        "10.0.0.1",
+	}
+}
+
+func TestGenerateClientConfigKeepsClientKey(t *testing.T) {
+	s := &Service{
+		config: &config.VPNConfig{
+			Endpoint: "vpn.test:51820",
+			KeyPair:  &wgKeyGen.KeyPair{PublicKey: "server-public-key", PrivateKey: "server-private-key"},
+		},
+		clients: newClientCache(),
+	}
+
+	client := testClient()
+	id := getClientID(client.UserID, client.GroupID)
+	s.clients.put(client)
+
+	cached, _ := s.clients.get(id)
+	clientConfig, err := s.generateClientConfig(cached)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(clientConfig, "PublicKey = server-public-key") || strings.Contains(clientConfig, "client-public-key") {
+		t.Fatalf("config has to contain only the server public key:\n%s", clientConfig)
+	}
+
+	stored, _ := s.clients.get(id)
+	for name, c := range map[string]*model.Client{"config client": cached, "cached client": stored} {
+		if c.PublicKey != "client-public-key" {
+			t.Errorf("%s public key is overwritten with %q", name, c.PublicKey)
+		}
+	}
+}
+
+func TestClientCacheReturnsCopies(t *testing.T) {
+	s := &Service{clients: newClientCache()}
+
+	client := testClient()
+	id := getClientID(client.UserID, client.GroupID)
+	s.clients.put(client)
+
+	// the client put in the cache is copied
+	client.PublicKey = "changed-after-put"
+
+	got, _ := s.clients.get(id)
+	got.PublicKey, got.Banned = "changed-copy", true
+
+	for _, c := range s.getFilteredClients(client.UserID, uuid.Nil, nil) {
+		c.PublicKey, c.Address = "changed-list-copy", "10.128.0.3/32"
+	}
+
+	for _, c := range s.clients.list(nil) {
+		c.PublicKey = "changed-list-copy"
+	}
+
+	cached, ok := s.clients.get(id)
+	if !ok {
+		t.Fatal("client is not cached")
+	}
+	if cached.PublicKey != "client-public-key" || cached.Address != "10.128.0.2/32" || cached.Banned {
+		t.Fatalf("snapshot is changed by the copies: %+v", cached)
+	}
+}
+
+func TestClientCacheFindByAddress(t *testing.T) {
+	c := newClientCache()
+
+	client, other := testClient(), testClient()
+	other.Address = "10.128.0.3/32"
+	c.put(client, other)
+
+	found, ok := c.findByAddress("10.128.0.2")
+	if !ok || found.UserID != client.UserID {
+		t.Fatalf("found %+v, %t, want the client of the address", found, ok)
+	}
+
+	// the index is rebuilt with the snapshot, so the removed client is not found by its address
+	c.remove(client)
+	if _, ok = c.findByAddress("10.128.0.2"); ok {
+		t.Fatal("removed client is found by its address")
+	}
+	if found, ok = c.findByAddress("10.128.0.3"); !ok || found.UserID != other.UserID {
+		t.Fatalf("found %+v, %t, want the other client", found, ok)
+	}
+
+	found.Address = "10.128.0.4/32"
+	if _, ok = c.findByAddress("10.128.0.3"); !ok {
+		t.Fatal("changing the found client changed the cache")
+	}
+}
+
+// clientRepository returns the stored client, other methods of the repository are not used by the details
+type clientRepository struct {
+	Repository
+	client postgres.VpnClient
+}
+
+func (r *clientRepository) GetVPNClient(context.Context, postgres.GetVPNClientParams) (postgres.VpnClient, error) {
+	return r.client, nil
+}
+
+func TestClientDetailsDNSMatchesClientConfig(t *testing.T) {
+	client := testClient()
+	for _, enabled := range []bool{false, true} {
+		s := &Service{
+			config: &config.VPNConfig{
+				Address: "10.128.0.1",
+				KeyPair: &wgKeyGen.KeyPair{},
+				DNS:     config.DNSConfig{Enabled: enabled},
+				Node:    config.NodeConfig{ID: "other"},
+			},
+			repository: &clientRepository{client: postgres.VpnClient{
+				UserID:         client.UserID,
+				GroupID:        client.GroupID,
+				NodeID:         "client-node",
+				IpAddress:      netip.MustParsePrefix(client.Address),
+				LaboratoryCidr: netip.MustParsePrefix(client.AllowedIPs),
+			}},
+		}
+
+		details, err := s.GetClientDetails(context.Background(), client.UserID, client.GroupID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		clientConfig, err := s.generateClientConfig(client)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !strings.Contains(clientConfig, "DNS = "+details.DNS+"\n") {
+			t.Errorf("DNS server %q of the details is not in the client config with DNS enabled %t:\n%s", details.DNS, enabled, clientConfig)
+		}
+	}
+}
@@ -0,0 +1,355 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"github.com/cybericebox/lib/pkg/ipam"
+	"github.com/cybericebox/wireguard/internal/delivery/repository/postgres"
+	"github.com/cybericebox/wireguard/internal/model"
+	"github.com/cybericebox/wireguard/pkg/appError"
+	"github.com/gofrs/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/rs/zerolog/log"
+	"net/netip"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// defaultClientsPageSize is the number of clients returned when the page size is not set
+	defaultClientsPageSize = 100
+	// maxClientsPageSize is the maximum number of clients returned at once
+	maxClientsPageSize = 1000
+	// noPeerEndpoint is the endpoint of the peer which has not connected yet
+	noPeerEndpoint = "(none)"
+	// pageTokenFingerprintSize is the size of the query hash kept in the page token
+	pageTokenFingerprintSize = 12
+)
+
+// pageToken is the position of the next page with the state of the query it continues
+type pageToken struct {
+	Offset int32 `json:"o"`
+	// OnlineSince is the unix nanoseconds of the online filter fixed by the first page, zero without the filter
+	OnlineSince int64 `json:"s,omitempty"`
+	// Query is the fingerprint of the query, the token of other queries is rejected
+	Query string `json:"q"`
+}
+
+// ListClients returns the page of clients of all nodes matching the query.
+// The last seen time is as recent as the last handshake sync of the client node.
+func (s *Service) ListClients(ctx context.Context, query model.ClientsQuery) (*model.ClientsPage, error) {
+	params, token, err := listClientsParams(query)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Debug().Str("userID", query.UserID.String()).Str("groupID", query.GroupID.String()).Str("sortBy", params.SortBy).Msg("Getting clients from db")
+	rows, err := s.repository.ListVPNClients(ctx, params)
+	if err != nil {
+		return nil, appError.ErrClient.WithWrappedError(appError.ErrPostgres.WithError(err)).WithMessage("Failed to get clients").Err()
+	}
+
+	page := &model.ClientsPage{}
+	// one more client is requested to know whether the next page exists
+	if pageSize := params.PageSize - 1; len(rows) > int(pageSize) {
+		rows = rows[:pageSize]
+		token.Offset = params.PageOffset + int32(len(rows))
+		if page.NextPageToken, err = encodePageToken(token); err != nil {
+			return nil, err
+		}
+	}
+
+	now := time.Now()
+	page.Clients = make([]*model.Client, 0, len(rows))
+	for _, c := range rows {
+		client := &model.Client{
+			UserID:    c.UserID,
+			GroupID:   c.GroupID,
+			NodeID:    c.NodeID,
+			Address:   c.IpAddress.String(),
+			Banned:    c.Banned,
+			LastSeen:  -1,
+			CreatedAt: c.CreatedAt,
+		}
+		if c.LastHandshakeAt.Valid {
+			client.LastSeen = int64(now.Sub(c.LastHandshakeAt.Time).Seconds())
+		}
+		page.Clients = append(page.Clients, client)
+	}
+
+	log.Debug().Int("clients", len(page.Clients)).Bool("hasNextPage", page.NextPageToken != "").Msg("Returning clients")
+	return page, nil
+}
+
+// listClientsParams validates the query and converts it to the query parameters.
+// The returned token is the state of the query the next page token continues.
+func listClientsParams(query model.ClientsQuery) (postgres.ListVPNClientsParams, pageToken, error) {
+	params := postgres.ListVPNClientsParams{
+		UserID:   nullUUID(query.UserID),
+		GroupID:  nullUUID(query.GroupID),
+		SortBy:   query.SortBy,
+		SortDesc: query.SortDescending,
+		PageSize: query.PageSize,
+	}
+
+	switch params.SortBy {
+	case "":
+		params.SortBy = model.ClientsSortByCreated
+	case model.ClientsSortByCreated, model.ClientsSortByLastSeen, model.ClientsSortByAddress:
+	default:
+		return params, pageToken{}, appError.ErrClientInvalidSortBy.WithContext("sortBy", query.SortBy).Err()
+	}
+
+	if params.PageSize <= 0 {
+		params.PageSize = defaultClientsPageSize
+	}
+
+	if params.PageSize > maxClientsPageSize {
+		params.PageSize = maxClientsPageSize
+	}
+	params.PageSize++
+
+	if query.Banned != nil {
+		params.Banned = pgtype.Bool{Bool: *query.Banned, Valid: true}
+	}
+
+	if query.OnlineWithin < 0 {
+		return params, pageToken{}, appError.ErrClientInvalidFilter.WithMessage("Online within must not be negative").Err()
+	}
+
+	if query.AddressCIDR != "" {
+		prefix, err := netip.ParsePrefix(query.AddressCIDR)
+		if err != nil {
+			return params, pageToken{}, appError.ErrClientInvalidFilter.WithError(err).WithMessage("Invalid address CIDR").Err()
+		}
+		prefix = prefix.Masked()
+		params.AddressCidr = &prefix
+	}
+
+	if !query.CreatedAfter.IsZero() {
+		params.CreatedAfter = pgtype.Timestamptz{Time: query.CreatedAfter, Valid: true}
+	}
+
+	token := pageToken{Query: queryFingerprint(params, query.OnlineWithin)}
+	if query.OnlineWithin > 0 {
+		token.OnlineSince = time.Now().Add(-query.OnlineWithin).UnixNano()
+	}
+
+	if query.PageToken != "" {
+		previous, err := decodePageToken(query.PageToken)
+		if err != nil {
+			return params, pageToken{}, err
+		}
+
+		if previous.Query != token.Query {
+			return params, pageToken{}, appError.ErrClientInvalidPageToken.WithMessage("Page token does not match the query").Err()
+		}
+
+		// the online filter of the next pages is the one of the first page, so the clients do not move between the pages
+		params.PageOffset, token.OnlineSince = previous.Offset, previous.OnlineSince
+	}
+
+	if token.OnlineSince != 0 {
+		params.OnlineSince = pgtype.Timestamptz{Time: time.Unix(0, token.OnlineSince), Valid: true}
+	}
+
+	return params, token, nil
+}
+
+// queryFingerprint returns the hash of the filters and the order of the query, the pages of the same query have the same fingerprint
+func queryFingerprint(params postgres.ListVPNClientsParams, onlineWithin time.Duration) string {
+	var addressCIDR, createdAfter string
+	if params.AddressCidr != nil {
+		addressCIDR = params.AddressCidr.String()
+	}
+	if params.CreatedAfter.Valid {
+		createdAfter = strconv.FormatInt(params.CreatedAfter.Time.UnixNano(), 10)
+	}
+
+	hash := sha256.Sum256([]byte(strings.Join([]string{
+		params.UserID.UUID.String(),
+		params.GroupID.UUID.String(),
+		params.SortBy,
+		strconv.FormatBool(params.SortDesc),
+		strconv.FormatInt(int64(params.PageSize), 10),
+		strconv.FormatBool(params.Banned.Valid),
+		strconv.FormatBool(params.Banned.Bool),
+		onlineWithin.String(),
+		addressCIDR,
+		createdAfter,
+	}, "\n")))
+	return base64.RawURLEncoding.EncodeToString(hash[:pageTokenFingerprintSize])
+}
+
+// encodePageToken returns the opaque token of the next page
+func encodePageToken(token pageToken) (string, error) {
+	data, err := json.Marshal(token)
+	if err != nil {
+		return "", appError.ErrClient.WithError(err).WithMessage("Failed to encode page token").Err()
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+// decodePageToken returns the state of the query the token continues
+func decodePageToken(encoded string) (pageToken, error) {
+	var token pageToken
+
+	decoded, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return token, appError.ErrClientInvalidPageToken.WithError(err).Err()
+	}
+
+	if err = json.Unmarshal(decoded, &token); err != nil {
+		return token, appError.ErrClientInvalidPageToken.WithError(err).Err()
+	}
+
+	if token.Offset < 0 || token.Query == "" {
+		return token, appError.ErrClientInvalidPageToken.Err()
+	}
+
+	return token, nil
+}
+
+// RunHandshakeSync periodically stores the last handshakes of the node peers until the context is done
+func (s *Service) RunHandshakeSync(ctx context.Context) {
+	ticker := time.NewTicker(s.config.HandshakeSyncInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Debug().Msg("Handshake sync stopped")
+			return
+		case <-ticker.C:
+			if err := s.SyncHandshakes(ctx); err != nil {
+				log.Error().Err(err).Msg("Failed to sync handshakes")
+			}
+		}
+	}
+}
+
+// SyncHandshakes stores the last handshakes of the node peers, the peers without handshake are skipped
+func (s *Service) SyncHandshakes(ctx context.Context) error {
+	peers, err := s.getPeersLastHandshake()
+	if err != nil {
+		return appError.ErrClient.WithError(err).WithMessage("Failed to get peers last handshake").Err()
+	}
+
+	params := postgres.UpdateVPNClientsLastHandshakeParams{
+		NodeID:         s.config.Node.ID,
+		PublicKeys:     make([]string, 0, len(peers)),
+		LastHandshakes: make([]time.Time, 0, len(peers)),
+	}
+	for publicKey, lastHandshake := range peers {
+		if lastHandshake <= 0 {
+			continue
+		}
+		params.PublicKeys = append(params.PublicKeys, publicKey)
+		params.LastHandshakes = append(params.LastHandshakes, time.Unix(int64(lastHandshake), 0))
+	}
+
+	if len(params.PublicKeys) == 0 {
+		return nil
+	}
+
+	// the clients without the stored handshake are connecting for the first time
+	var first []postgres.GetVPNClientsWithoutHandshakeRow
+	if s.webhookEnabled(model.WebhookEventClientFirstHandshake) {
+		if first, err = s.repository.GetVPNClientsWithoutHandshake(ctx, postgres.GetVPNClientsWithoutHandshakeParams{
+			NodeID:     s.config.Node.ID,
+			PublicKeys: params.PublicKeys,
+		}); err != nil {
+			return appError.ErrClient.WithWrappedError(appError.ErrPostgres.WithError(err)).WithMessage("Failed to get clients without handshake").Err()
+		}
+	}
+
+	events := make([]webhookEvent, 0, len(first))
+	for _, c := range first {
+		events = append(events, webhookEvent{client: &model.Client{UserID: c.UserID, GroupID: c.GroupID}, data: map[string]any{
+			"handshakeAt": time.Unix(int64(peers[c.PublicKey]), 0).UTC(),
+		}})
+	}
+
+	var affected int64
+	if err = s.storeWithEvents(ctx, model.WebhookEventClientFirstHandshake, events, func(q postgres.TxQuerier) (err error) {
+		affected, err = q.UpdateVPNClientsLastHandshake(ctx, params)
+		return err
+	}); err != nil {
+		return appError.ErrClient.WithWrappedError(appError.ErrPostgres.WithError(err)).WithMessage("Failed to update clients last handshake").Err()
+	}
+
+	log.Debug().Int64("clients", affected).Msg("Handshakes synced")
+	return nil
+}
+
+// GetClientDetails returns the client with its database record and the state of its peer on the current node
+func (s *Service) GetClientDetails(ctx context.Context, userID, groupID uuid.UUID) (*model.ClientDetails, error) {
+	log.Debug().Str("userID", userID.String()).Str("groupID", groupID.String()).Msg("Getting client from db")
+	row, err := s.repository.GetVPNClient(ctx, postgres.GetVPNClientParams{
+		UserID:  userID,
+		GroupID: groupID,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, appError.ErrClientNotFound.WithContext("userID", userID.String()).WithContext("groupID", groupID.String()).Err()
+		}
+		return nil, appError.ErrClient.WithWrappedError(appError.ErrPostgres.WithError(err)).WithMessage("Failed to get client").Err()
+	}
+
+	details := &model.ClientDetails{
+		Client: model.Client{
+			UserID:         row.UserID,
+			GroupID:        row.GroupID,
+			NodeID:         row.NodeID,
+			Address:        row.IpAddress.String(),
+			PublicKey:      row.PublicKey,
+			AllowedIPs:     row.LaboratoryCidr.String(),
+			ServerEndpoint: s.config.Endpoint,
+			Banned:         row.Banned,
+			LastSeen:       -1,
+			CreatedAt:      row.CreatedAt,
+			ExpiresAt:      row.ExpiresAt.Time,
+			TransferQuota:  row.TransferQuota,
+		},
+		BanReason:       row.BanReason,
+		UpdatedAt:       row.UpdatedAt.Time,
+		LastHandshakeAt: row.LastHandshakeAt.Time,
+	}
+
+	if details.DNS, err = ipam.GetFirstCIDRIP(details.AllowedIPs); err != nil {
+		return nil, appError.ErrClient.WithError(err).WithMessage("Failed to generate client DNS ip").Err()
+	}
+	// the details show the DNS servers of the client config
+	details.DNS = s.clientDNS(&details.Client)
+
+	// the peer exists only on the client node
+	if row.NodeID == s.config.Node.ID {
+		peers, err := s.peers.GetPeers()
+		if err != nil {
+			return nil, appError.ErrClient.WithError(err).WithMessage("Failed to get client peer").Err()
+		}
+
+		if p, ok := peers[row.PublicKey]; ok {
+			// the endpoint is unknown until the first handshake
+			if p.Endpoint != noPeerEndpoint {
+				details.PeerEndpoint = p.Endpoint
+			}
+			details.TransferRx = p.TransferRx
+			details.TransferTx = p.TransferTx
+			if p.LatestHandshake > 0 {
+				details.LastHandshakeAt = time.Unix(int64(p.LatestHandshake), 0)
+			}
+		}
+	}
+
+	if !details.LastHandshakeAt.IsZero() {
+		details.LastSeen = int64(time.Since(details.LastHandshakeAt).Seconds())
+	}
+
+	return details, nil
+}
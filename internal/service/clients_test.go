@@ -0,0 +1,66 @@
+package service
+
+import (
+	"errors"
+	"github.com/cybericebox/wireguard/internal/model"
+	"github.com/cybericebox/wireguard/pkg/appError"
+	"github.com/gofrs/uuid"
+	"testing"
+	"time"
+)
+
+func TestPageTokenContinuesQuery(t *testing.T) {
+	query := model.ClientsQuery{
+		GroupID:      uuid.Must(uuid.NewV4()),
+		PageSize:     10,
+		SortBy:       model.ClientsSortByLastSeen,
+		OnlineWithin: time.Minute,
+	}
+
+	first, token, err := listClientsParams(query)
+	if err != nil {
+		t.Fatal(err)
+	}
+	token.Offset = 10
+	if query.PageToken, err = encodePageToken(token); err != nil {
+		t.Fatal(err)
+	}
+
+	time.Sleep(10 * time.Millisecond)
+	next, _, err := listClientsParams(query)
+	if err != nil {
+		t.Fatalf("next page: %v", err)
+	}
+	if next.PageOffset != 10 {
+		t.Fatalf("expected offset 10, got %d", next.PageOffset)
+	}
+	if !next.OnlineSince.Time.Equal(first.OnlineSince.Time) {
+		t.Fatalf("online filter moved from %v to %v between the pages", first.OnlineSince.Time, next.OnlineSince.Time)
+	}
+
+	banned := true
+	changed := map[string]func(q *model.ClientsQuery){
+		"group":         func(q *model.ClientsQuery) { q.GroupID = uuid.Must(uuid.NewV4()) },
+		"page size":     func(q *model.ClientsQuery) { q.PageSize = 20 },
+		"sort":          func(q *model.ClientsQuery) { q.SortBy = model.ClientsSortByAddress },
+		"order":         func(q *model.ClientsQuery) { q.SortDescending = true },
+		"banned":        func(q *model.ClientsQuery) { q.Banned = &banned },
+		"online within": func(q *model.ClientsQuery) { q.OnlineWithin = time.Hour },
+		"address":       func(q *model.ClientsQuery) { q.AddressCIDR = "10.128.0.0/24" },
+		"created after": func(q *model.ClientsQuery) { q.CreatedAfter = time.Now() },
+	}
+	for name, change := range changed {
+		other := query
+		change(&other)
+		if _, _, err = listClientsParams(other); !errors.Is(err, appError.ErrClientInvalidPageToken.Err()) {
+			t.Errorf("%s: expected invalid page token, got %v", name, err)
+		}
+	}
+
+	for _, invalid := range []string{"MTA", "not base64!", "e30"} {
+		query.PageToken = invalid
+		if _, _, err = listClientsParams(query); !errors.Is(err, appError.ErrClientInvalidPageToken.Err()) {
+			t.Errorf("%q: expected invalid page token, got %v", invalid, err)
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package service
+
+import (
+	"context"
+	"github.com/cybericebox/wireguard/internal/model"
+	"github.com/cybericebox/wireguard/pkg/appError"
+)
+
+// creation is the creation of a client in flight, the concurrent callers wait for it instead of creating the client again.
+// It works like singleflight, but a batch can lead the creations of many clients at once.
+type creation struct {
+	done   chan struct{}
+	client *model.Client
+	err    error
+}
+
+// claimCreation returns the cached client, or the creation of the client in flight to wait for,
+// or registers the creation the caller has to lead and finish
+func (s *Service) claimCreation(id string) (cached *model.Client, c *creation, leader bool) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	if client, ok := s.clients.get(id); ok {
+		return client, nil, false
+	}
+
+	if c, ok := s.creations[id]; ok {
+		return nil, c, false
+	}
+
+	c = &creation{done: make(chan struct{})}
+	s.creations[id] = c
+	return nil, c, true
+}
+
+// finishCreation publishes the result of the led creation to the waiting callers
+func (s *Service) finishCreation(id string, c *creation, client *model.Client, err error) {
+	s.m.Lock()
+	delete(s.creations, id)
+	s.m.Unlock()
+
+	c.client, c.err = client, err
+	close(c.done)
+}
+
+// wait returns the result of the creation unless the context is done first
+func (c *creation) wait(ctx context.Context) (*model.Client, error) {
+	select {
+	case <-c.done:
+		if c.err != nil {
+			return nil, c.err
+		}
+		// every waiting caller gets its own copy
+		clientCopy := *c.client
+		return &clientCopy, nil
+	case <-ctx.Done():
+		return nil, appError.ErrClient.WithError(ctx.Err()).WithMessage("Failed to wait for client creation").Err()
+	}
+}
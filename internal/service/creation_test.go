@@ -0,0 +1,63 @@
+package service_test
+
+import (
+	"context"
+	"github.com/cybericebox/wireguard/pkg/controller/grpc/client"
+	"github.com/cybericebox/wireguard/pkg/controller/grpc/protobuf"
+	"github.com/cybericebox/wireguard/pkg/wgtest"
+	"github.com/gofrs/uuid"
+	"sync"
+	"testing"
+)
+
+func TestConcurrentGetClientConfigCreatesClientOnce(t *testing.T) {
+	h := wgtest.NewHarness(t)
+	c := h.Client(t, []string{client.RoleOperator}, "")
+
+	request := &protobuf.ClientConfigRequest{
+		UserID:   uuid.Must(uuid.NewV4()).String(),
+		GroupID:  uuid.Must(uuid.NewV4()).String(),
+		DestCIDR: "10.0.0.0/24",
+	}
+
+	const callers = 32
+	configs := make([]string, callers)
+	errs := make([]error, callers)
+
+	var wg sync.WaitGroup
+	start := make(chan struct{})
+	for i := range callers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+			resp, err := c.GetClientConfig(context.Background(), request)
+			configs[i], errs[i] = resp.GetConfig(), err
+		}()
+	}
+	close(start)
+	wg.Wait()
+
+	for i, err := range errs {
+		if err != nil {
+			t.Fatalf("caller %d: %v", i, err)
+		}
+		if configs[i] == "" || configs[i] != configs[0] {
+			t.Fatalf("caller %d got another config", i)
+		}
+	}
+
+	// the server address and the address of the client
+	if acquired := h.IPAManager.Acquired(); len(acquired) != 2 {
+		t.Errorf("expected 1 client address, got %v", acquired)
+	}
+	if peers := h.PeerBackend.Peers(); len(peers) != 1 {
+		t.Errorf("expected 1 peer, got %d", len(peers))
+	}
+	if rules := h.Firewall.Rules(); len(rules.NAT) != 1 {
+		t.Errorf("expected 1 NAT rule, got %d", len(rules.NAT))
+	}
+	if rows := h.Repository.Clients(); len(rows) != 1 {
+		t.Errorf("expected 1 client row, got %d", len(rows))
+	}
+}
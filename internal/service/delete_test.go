@@ -0,0 +1,53 @@
+package service_test
+
+import (
+	"context"
+	"github.com/cybericebox/wireguard/pkg/controller/grpc/client"
+	"github.com/cybericebox/wireguard/pkg/controller/grpc/protobuf"
+	"github.com/cybericebox/wireguard/pkg/wgtest"
+	"github.com/gofrs/uuid"
+	"strings"
+	"testing"
+)
+
+func TestDeleteClientsRemovesClientPeer(t *testing.T) {
+	h := wgtest.NewHarness(t)
+	c := h.Client(t, []string{client.RoleAdmin}, "")
+	ctx := context.Background()
+
+	groupID := uuid.Must(uuid.NewV4()).String()
+	deleted, kept := uuid.Must(uuid.NewV4()).String(), uuid.Must(uuid.NewV4()).String()
+
+	var serverKey string
+	keys := make(map[string]string)
+	for _, userID := range []string{deleted, kept} {
+		resp, err := c.GetClientConfig(ctx, &protobuf.ClientConfigRequest{UserID: userID, GroupID: groupID, DestCIDR: "10.0.0.0/24"})
+		if err != nil {
+			t.Fatalf("get client config: %v", err)
+		}
+		// the peer of the client config is the server
+		_, serverKey, _ = strings.Cut(resp.GetConfig(), "PublicKey = ")
+		serverKey, _, _ = strings.Cut(serverKey, "\n")
+
+		details, err := c.GetClient(ctx, &protobuf.ClientRequest{UserID: userID, GroupID: groupID})
+		if err != nil {
+			t.Fatalf("get client: %v", err)
+		}
+		keys[userID] = details.GetClient().GetPublicKey()
+	}
+
+	if _, err := c.DeleteClients(ctx, &protobuf.ClientsRequest{UserID: deleted, GroupID: groupID}); err != nil {
+		t.Fatalf("delete client: %v", err)
+	}
+
+	peers := h.PeerBackend.Peers()
+	if _, ok := peers[keys[deleted]]; ok {
+		t.Fatal("peer of the deleted client is left")
+	}
+	if _, ok := peers[keys[kept]]; !ok || len(peers) != 1 {
+		t.Fatalf("expected only the peer of the kept client, got %v", peers)
+	}
+	if serverKey == "" || keys[deleted] == serverKey || keys[kept] == serverKey {
+		t.Fatal("client has the server public key")
+	}
+}
@@ -0,0 +1,304 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"github.com/cybericebox/wireguard/internal/delivery/repository/postgres"
+	"github.com/cybericebox/wireguard/internal/model"
+	"github.com/cybericebox/wireguard/pkg/appError"
+	"github.com/gofrs/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/rs/zerolog/log"
+	"net/netip"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// maxDNSNameLength and maxDNSLabelLength are the limits of the hostname and its labels without the trailing dot
+	maxDNSNameLength  = 253
+	maxDNSLabelLength = 63
+)
+
+// dnsRecordCache is the cache of the lab hostnames by name and group.
+// As the client cache, readers load the current snapshot without locking and writers replace it.
+type dnsRecordCache struct {
+	// m serializes the writers
+	m        sync.Mutex
+	snapshot atomic.Pointer[map[string][]model.DNSRecord]
+}
+
+func newDNSRecordCache() *dnsRecordCache {
+	c := &dnsRecordCache{}
+	c.snapshot.Store(&map[string][]model.DNSRecord{})
+	return c
+}
+
+// get returns the records of the name of all groups
+func (c *dnsRecordCache) get(name string) []model.DNSRecord {
+	return (*c.snapshot.Load())[name]
+}
+
+// update replaces the snapshot with the copy changed by the function
+func (c *dnsRecordCache) update(fn func(records map[string][]model.DNSRecord)) {
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	current := *c.snapshot.Load()
+	next := make(map[string][]model.DNSRecord, len(current))
+	for name, records := range current {
+		next[name] = records
+	}
+	fn(next)
+	c.snapshot.Store(&next)
+}
+
+// put caches the records, the slices of the snapshot are never appended in place
+func (c *dnsRecordCache) put(records ...model.DNSRecord) {
+	c.update(func(snapshot map[string][]model.DNSRecord) {
+		for _, r := range records {
+			snapshot[r.Name] = append(append([]model.DNSRecord{}, snapshot[r.Name]...), r)
+		}
+	})
+}
+
+// remove deletes the records by ID
+func (c *dnsRecordCache) remove(records ...model.DNSRecord) {
+	ids := make(map[uuid.UUID]struct{}, len(records))
+	for _, r := range records {
+		ids[r.ID] = struct{}{}
+	}
+
+	c.update(func(snapshot map[string][]model.DNSRecord) {
+		for _, r := range records {
+			kept := make([]model.DNSRecord, 0, len(snapshot[r.Name]))
+			for _, cached := range snapshot[r.Name] {
+				if _, ok := ids[cached.ID]; !ok {
+					kept = append(kept, cached)
+				}
+			}
+
+			if len(kept) == 0 {
+				delete(snapshot, r.Name)
+				continue
+			}
+			snapshot[r.Name] = kept
+		}
+	})
+}
+
+// replace replaces the cached records with the loaded ones
+func (c *dnsRecordCache) replace(records []model.DNSRecord) {
+	c.update(func(snapshot map[string][]model.DNSRecord) {
+		clear(snapshot)
+		for _, r := range records {
+			snapshot[r.Name] = append(snapshot[r.Name], r)
+		}
+	})
+}
+
+// normalizeDNSName returns the lowercase name with the trailing dot, the names are cached and looked up by it
+func normalizeDNSName(name string) string {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if !strings.HasSuffix(name, ".") {
+		name += "."
+	}
+	return name
+}
+
+// LookupLabHost resolves the name for the client of the source address.
+// Only the active clients are served, the lab hostnames of their group are found,
+// the lab hostnames of other groups are refused and other names are left to the upstreams.
+func (s *Service) LookupLabHost(source, name string) model.DNSLookup {
+	client, ok := s.clients.findByAddress(source)
+	if !ok || client.Banned {
+		log.Debug().Str("source", source).Str("name", name).Msg("Refusing DNS query of unknown or banned client")
+		return model.DNSLookup{Result: model.DNSLookupRefused}
+	}
+
+	records := s.dnsRecords.get(normalizeDNSName(name))
+	if len(records) == 0 {
+		return model.DNSLookup{Result: model.DNSLookupForward}
+	}
+
+	addresses := make([]string, 0, len(records))
+	for _, r := range records {
+		if r.GroupID == client.GroupID {
+			addresses = append(addresses, r.Address)
+		}
+	}
+
+	// the name is registered by other groups only
+	if len(addresses) == 0 {
+		log.Debug().Str("source", source).Str("name", name).Str("groupID", client.GroupID.String()).Msg("Refusing DNS query of lab hostname of another group")
+		return model.DNSLookup{Result: model.DNSLookupRefused}
+	}
+
+	return model.DNSLookup{Result: model.DNSLookupFound, Addresses: addresses}
+}
+
+// validDNSName reports whether the normalized name is the hostname of letters, digits and hyphens
+func validDNSName(name string) bool {
+	// the trailing dot is not counted
+	if len(name) < 2 || len(name) > maxDNSNameLength+1 {
+		return false
+	}
+
+	for _, label := range strings.Split(strings.TrimSuffix(name, "."), ".") {
+		if len(label) == 0 || len(label) > maxDNSLabelLength || label[0] == '-' || label[len(label)-1] == '-' {
+			return false
+		}
+		for _, ch := range label {
+			if (ch < 'a' || ch > 'z') && (ch < '0' || ch > '9') && ch != '-' {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+func dnsRecordFromRow(row postgres.VpnDnsRecord) model.DNSRecord {
+	return model.DNSRecord{
+		ID:             row.ID,
+		GroupID:        row.GroupID,
+		Name:           row.Name,
+		Address:        row.Address.String(),
+		LaboratoryCIDR: row.LaboratoryCidr.String(),
+		CreatedAt:      row.CreatedAt,
+	}
+}
+
+// CreateDNSRecord registers the lab hostname of the group, the address has to be in a lab CIDR of the group clients.
+// The lab CIDR the address is in is stored with the record.
+func (s *Service) CreateDNSRecord(ctx context.Context, groupID uuid.UUID, name, address string) (*model.DNSRecord, error) {
+	if groupID.IsNil() {
+		return nil, appError.ErrDNSMissingGroupID.Err()
+	}
+
+	name = normalizeDNSName(name)
+	if !validDNSName(name) {
+		return nil, appError.ErrDNSInvalidName.WithContext("name", name).Err()
+	}
+
+	addr, err := netip.ParseAddr(address)
+	if err != nil {
+		return nil, appError.ErrDNSInvalidAddress.WithError(err).WithContext("address", address).Err()
+	}
+	addr = addr.Unmap()
+
+	log.Debug().Str("groupID", groupID.String()).Str("name", name).Msg("Getting group lab CIDRs from db")
+	cidrs, err := s.repository.GetVPNGroupLaboratoryCIDRs(ctx, groupID)
+	if err != nil {
+		return nil, appError.ErrDNS.WithWrappedError(appError.ErrPostgres.WithError(err)).WithMessage("Failed to get group lab CIDRs").Err()
+	}
+
+	i := slices.IndexFunc(cidrs, func(cidr netip.Prefix) bool { return cidr.Contains(addr) })
+	if i < 0 {
+		return nil, appError.ErrDNSAddressOutOfLab.WithContext("address", addr.String()).WithContext("groupID", groupID.String()).Err()
+	}
+	cidr := cidrs[i]
+
+	id, err := uuid.NewV4()
+	if err != nil {
+		return nil, appError.ErrDNS.WithError(err).WithMessage("Failed to generate DNS record ID").Err()
+	}
+
+	log.Debug().Str("groupID", groupID.String()).Str("name", name).Str("address", addr.String()).Msg("Creating DNS record in db")
+	row, err := s.repository.CreateVPNDNSRecord(ctx, postgres.CreateVPNDNSRecordParams{
+		ID:             id,
+		GroupID:        groupID,
+		Name:           name,
+		Address:        addr,
+		LaboratoryCidr: cidr,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, appError.ErrDNSRecordExists.WithContext("name", name).WithContext("address", addr.String()).Err()
+		}
+		return nil, appError.ErrDNS.WithWrappedError(appError.ErrPostgres.WithError(err)).WithMessage("Failed to create DNS record").Err()
+	}
+
+	record := dnsRecordFromRow(row)
+	// the other nodes load the record with the next sync
+	s.dnsRecords.put(record)
+
+	return &record, nil
+}
+
+// ListDNSRecords returns the lab hostnames of the group or of all groups if the group is not set
+func (s *Service) ListDNSRecords(ctx context.Context, groupID uuid.UUID) ([]*model.DNSRecord, error) {
+	log.Debug().Str("groupID", groupID.String()).Msg("Getting DNS records from db")
+	rows, err := s.repository.ListVPNDNSRecords(ctx, nullUUID(groupID))
+	if err != nil {
+		return nil, appError.ErrDNS.WithWrappedError(appError.ErrPostgres.WithError(err)).WithMessage("Failed to get DNS records").Err()
+	}
+
+	records := make([]*model.DNSRecord, 0, len(rows))
+	for _, row := range rows {
+		record := dnsRecordFromRow(row)
+		records = append(records, &record)
+	}
+
+	return records, nil
+}
+
+// DeleteDNSRecord deletes the lab hostname of the group
+func (s *Service) DeleteDNSRecord(ctx context.Context, groupID, id uuid.UUID) error {
+	if groupID.IsNil() {
+		return appError.ErrDNSMissingGroupID.Err()
+	}
+
+	log.Debug().Str("groupID", groupID.String()).Str("id", id.String()).Msg("Deleting DNS record from db")
+	row, err := s.repository.DeleteVPNDNSRecord(ctx, postgres.DeleteVPNDNSRecordParams{
+		ID:      id,
+		GroupID: groupID,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return appError.ErrDNSRecordNotFound.WithContext("id", id.String()).WithContext("groupID", groupID.String()).Err()
+		}
+		return appError.ErrDNS.WithWrappedError(appError.ErrPostgres.WithError(err)).WithMessage("Failed to delete DNS record").Err()
+	}
+
+	s.dnsRecords.remove(dnsRecordFromRow(row))
+
+	return nil
+}
+
+// SyncDNSRecords reloads the lab hostnames of all groups, so the records changed on other nodes are resolved
+func (s *Service) SyncDNSRecords(ctx context.Context) error {
+	rows, err := s.repository.ListVPNDNSRecords(ctx, uuid.NullUUID{})
+	if err != nil {
+		return appError.ErrDNS.WithWrappedError(appError.ErrPostgres.WithError(err)).WithMessage("Failed to get DNS records").Err()
+	}
+
+	records := make([]model.DNSRecord, 0, len(rows))
+	for _, row := range rows {
+		records = append(records, dnsRecordFromRow(row))
+	}
+	s.dnsRecords.replace(records)
+
+	log.Debug().Int("records", len(records)).Msg("DNS records synced")
+	return nil
+}
+
+func (s *Service) RunDNSRecordSync(ctx context.Context) {
+	ticker := time.NewTicker(s.config.DNS.SyncInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Debug().Msg("DNS record sync stopped")
+			return
+		case <-ticker.C:
+			if err := s.SyncDNSRecords(ctx); err != nil {
+				log.Error().Err(err).Msg("Failed to sync DNS records")
+			}
+		}
+	}
+}
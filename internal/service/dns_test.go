@@ -0,0 +1,50 @@
+package service_test
+
+import (
+	"context"
+	"github.com/cybericebox/wireguard/pkg/controller/grpc/client"
+	"github.com/cybericebox/wireguard/pkg/controller/grpc/protobuf"
+	"github.com/cybericebox/wireguard/pkg/wgtest"
+	"github.com/gofrs/uuid"
+	"testing"
+)
+
+func TestCreateDNSRecordValidatesGroupLabCIDRs(t *testing.T) {
+	h := wgtest.NewHarness(t)
+	c := h.Client(t, []string{client.RoleAdmin}, "")
+	ctx := context.Background()
+
+	groupID, otherGroupID := uuid.Must(uuid.NewV4()).String(), uuid.Must(uuid.NewV4()).String()
+
+	// the group without clients has no lab CIDRs
+	if _, err := c.CreateDNSRecord(ctx, &protobuf.CreateDNSRecordRequest{GroupID: groupID, Name: "web.lab", Address: "10.0.0.5"}); err == nil {
+		t.Fatal("record was created for the group without lab CIDRs")
+	}
+
+	for id, cidr := range map[string]string{groupID: "10.0.0.0/24", otherGroupID: "10.0.1.0/24"} {
+		if _, err := c.GetClientConfig(ctx, &protobuf.ClientConfigRequest{UserID: uuid.Must(uuid.NewV4()).String(), GroupID: id, DestCIDR: cidr}); err != nil {
+			t.Fatalf("get client config: %v", err)
+		}
+	}
+
+	resp, err := c.CreateDNSRecord(ctx, &protobuf.CreateDNSRecordRequest{GroupID: groupID, Name: "web.lab", Address: "10.0.0.5"})
+	if err != nil {
+		t.Fatalf("create DNS record: %v", err)
+	}
+	if resp.GetRecord().GetLaboratoryCIDR() != "10.0.0.0/24" {
+		t.Fatalf("record lab CIDR = %q, want 10.0.0.0/24", resp.GetRecord().GetLaboratoryCIDR())
+	}
+
+	// the lab CIDR of another group does not allow the address
+	if _, err = c.CreateDNSRecord(ctx, &protobuf.CreateDNSRecordRequest{GroupID: groupID, Name: "db.lab", Address: "10.0.1.5"}); err == nil {
+		t.Fatal("record was created with the address of another group lab")
+	}
+
+	records, err := c.ListDNSRecords(ctx, &protobuf.DNSRecordsRequest{GroupID: groupID})
+	if err != nil {
+		t.Fatalf("list DNS records: %v", err)
+	}
+	if len(records.GetRecords()) != 1 || records.GetRecords()[0].GetAddress() != "10.0.0.5" {
+		t.Fatalf("got records %v, want the created one", records.GetRecords())
+	}
+}
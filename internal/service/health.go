@@ -0,0 +1,105 @@
+package service
+
+import (
+	"context"
+	"github.com/cybericebox/wireguard/internal/model"
+	"github.com/cybericebox/wireguard/pkg/appError"
+	"time"
+)
+
+// reconcileStaleAfter is the number of missed reconcile intervals after which the reconciliation is considered stale
+const reconcileStaleAfter = 3
+
+// CheckHealth runs all health checks, the server is ready only if all of them pass
+func (s *Service) CheckHealth(ctx context.Context) *model.Health {
+	health := &model.Health{Ready: true}
+
+	for _, check := range []struct {
+		name string
+		fn   func(ctx context.Context) error
+	}{
+		{name: model.HealthCheckDatabase, fn: s.repository.Ping},
+		{name: model.HealthCheckInterface, fn: s.checkInterface},
+		{name: model.HealthCheckListenPort, fn: s.checkListenPort},
+		{name: model.HealthCheckFirewall, fn: s.checkFirewall},
+		{name: model.HealthCheckReconcile, fn: s.checkReconcile},
+	} {
+		result := model.HealthCheck{Name: check.name, Healthy: true}
+		if err := check.fn(ctx); err != nil {
+			result.Healthy, result.Error = false, err.Error()
+			health.Ready = false
+		}
+		health.Checks = append(health.Checks, result)
+	}
+
+	return health
+}
+
+// checkInterface checks that the interface exists and it is up
+func (s *Service) checkInterface(_ context.Context) error {
+	return s.peers.CheckInterface()
+}
+
+// checkListenPort checks that the interface is bound to the configured port
+func (s *Service) checkListenPort(_ context.Context) error {
+	return s.peers.CheckListenPort()
+}
+
+// checkFirewall checks that the forward rules and the rules of all clients are in place.
+// The clients are listed before the rules, so the clients created while the rules are read are checked by the next check,
+// and the clients changed while the rules are read are not reported.
+func (s *Service) checkFirewall(_ context.Context) error {
+	// the reconciliation replaces the rules while it holds the kernel for writing
+	s.kernel.RLock()
+	defer s.kernel.RUnlock()
+
+	if err := s.firewall.CheckForwardRules(); err != nil {
+		return appError.ErrIptables.WithError(err).WithMessage("Failed to check forward rules").Err()
+	}
+
+	clients := s.clients.list(nil)
+
+	existing, err := s.firewall.GetRules()
+	if err != nil {
+		return appError.ErrIptables.WithError(err).WithMessage("Failed to get rules").Err()
+	}
+
+	natRules, blockRules := groupRulesByID(existing.NAT), groupRulesByID(existing.Block)
+
+	for _, c := range clients {
+		id := getClientID(c.UserID, c.GroupID)
+		natMissing := len(natRules[id]) == 0
+		blockMissing := c.Banned && len(blockRules[id]) == 0
+		if !natMissing && !blockMissing {
+			continue
+		}
+
+		// the rules of the client deleted, recreated, banned or unbanned while the rules are read are checked by the next check
+		if current, ok := s.clients.get(id); !ok || current.PublicKey != c.PublicKey || current.Address != c.Address || current.Banned != c.Banned {
+			continue
+		}
+
+		if natMissing {
+			return appError.ErrIptables.WithMessage("Client NAT rule is missing").WithContext("id", id).Err()
+		}
+		return appError.ErrIptables.WithMessage("Client blocking rule is missing").WithContext("id", id).Err()
+	}
+
+	return nil
+}
+
+// checkReconcile checks that the last reconciliation succeeded and it is fresh
+func (s *Service) checkReconcile(_ context.Context) error {
+	s.reconcile.m.RLock()
+	defer s.reconcile.m.RUnlock()
+
+	if s.reconcile.err != nil {
+		return appError.ErrPlatform.WithError(s.reconcile.err).WithMessage("Last reconciliation failed").Err()
+	}
+
+	if time.Since(s.reconcile.lastSuccess) > reconcileStaleAfter*s.config.ReconcileInterval {
+		return appError.ErrPlatform.WithMessage("Reconciliation is stale").WithContext("lastSuccess", s.reconcile.lastSuccess).Err()
+	}
+
+	return nil
+}
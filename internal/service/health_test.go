@@ -0,0 +1,45 @@
+package service
+
+import (
+	"context"
+	"github.com/cybericebox/wireguard/internal/model"
+	"testing"
+)
+
+// changingFirewall runs the change of the clients while the rules are read, other methods of the firewall are not used by the check
+type changingFirewall struct {
+	Firewall
+	rules  *model.FirewallRules
+	change func()
+}
+
+func (f *changingFirewall) CheckForwardRules() error {
+	return nil
+}
+
+func (f *changingFirewall) GetRules() (*model.FirewallRules, error) {
+	f.change()
+	return f.rules, nil
+}
+
+func TestCheckFirewallToleratesChangedClients(t *testing.T) {
+	deleted, stable := testClient(), testClient()
+	firewall := &changingFirewall{rules: &model.FirewallRules{}}
+	s := &Service{clients: newClientCache(), firewall: firewall}
+	s.clients.put(deleted)
+	s.clients.put(stable)
+
+	// the client is deleted with its rules while the rules are read
+	firewall.change = func() { s.clients.remove(deleted) }
+	firewall.rules.NAT = []model.FirewallRule{{ID: getClientID(stable.UserID, stable.GroupID), Source: stable.Address}}
+	if err := s.checkFirewall(context.Background()); err != nil {
+		t.Fatalf("check firewall: %v", err)
+	}
+
+	// the rule of the unchanged client is missing
+	firewall.change = func() {}
+	firewall.rules.NAT = nil
+	if err := s.checkFirewall(context.Background()); err == nil {
+		t.Fatal("missing NAT rule of the client is not reported")
+	}
+}
@@ -0,0 +1,125 @@
+package service
+
+import (
+	"context"
+	"github.com/cybericebox/wireguard/internal/delivery/repository/postgres"
+	"github.com/cybericebox/wireguard/internal/model"
+	"github.com/cybericebox/wireguard/pkg/appError"
+	"github.com/gofrs/uuid"
+	"github.com/hashicorp/go-multierror"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/rs/zerolog/log"
+	"time"
+)
+
+// SetClientLimits sets the expiration time and the transfer quota of the clients, zero values remove the limits
+func (s *Service) SetClientLimits(ctx context.Context, userID, groupID uuid.UUID, expiresAt time.Time, transferQuota int64) (int64, error) {
+	if transferQuota < 0 {
+		return 0, appError.ErrClientInvalidLimits.WithContext("transferQuota", transferQuota).Err()
+	}
+
+	s.kernel.RLock()
+	defer s.kernel.RUnlock()
+
+	log.Debug().Str("userID", userID.String()).Str("groupID", groupID.String()).Msg("Get clients for setting limits")
+	clients := s.getFilteredClients(userID, groupID, operationFilter(model.ClientsOperationSetLimits))
+
+	if len(clients) == 0 {
+		log.Debug().Str("userID", userID.String()).Str("groupID", groupID.String()).Msg("No clients found for setting limits")
+		return 0, nil
+	}
+
+	log.Debug().Str("userID", userID.String()).Str("groupID", groupID.String()).Msg("Updating clients limits in db")
+	affected, err := s.repository.UpdateVPNClientsLimits(ctx, postgres.UpdateVPNClientsLimitsParams{
+		ExpiresAt: pgtype.Timestamptz{
+			Time:  expiresAt,
+			Valid: !expiresAt.IsZero(),
+		},
+		TransferQuota: transferQuota,
+		NodeID:        s.config.Node.ID,
+		UserID:        nullUUID(userID),
+		GroupID:       nullUUID(groupID),
+	})
+	if err != nil {
+		return 0, appError.ErrClient.WithWrappedError(appError.ErrPostgres.WithError(err)).WithMessage("Failed to update clients limits in db").Err()
+	}
+
+	log.Debug().Str("userID", userID.String()).Str("groupID", groupID.String()).Msg("Updating clients limits in cache")
+	s.clients.setLimits(expiresAt, transferQuota, clients...)
+
+	return affected, nil
+}
+
+// EnforceClientLimits blocks the clients which are expired or exceeded their transfer quota
+func (s *Service) EnforceClientLimits(ctx context.Context) error {
+	s.kernel.RLock()
+	defer s.kernel.RUnlock()
+
+	var errs error
+	now := time.Now()
+	withQuota := false
+
+	clients := s.clients.list(func(_ string, c *model.Client) bool {
+		return !c.Banned && (!c.ExpiresAt.IsZero() || c.TransferQuota > 0)
+	})
+
+	for _, c := range clients {
+		if !c.ExpiresAt.IsZero() && !c.ExpiresAt.After(now) {
+			log.Debug().Str("userID", c.UserID.String()).Str("groupID", c.GroupID.String()).Msg("Blocking expired client")
+			if err := s.blockClient(ctx, c, model.WebhookEventClientExpired, model.ClientBanReasonExpired, map[string]any{"expiresAt": c.ExpiresAt.UTC()}); err != nil {
+				errs = multierror.Append(errs, err)
+			}
+			continue
+		}
+		if c.TransferQuota > 0 {
+			withQuota = true
+		}
+	}
+
+	if !withQuota {
+		return errs
+	}
+
+	transfers, err := s.repository.GetVPNClientsTransfer(ctx, s.config.Node.ID)
+	if err != nil {
+		return multierror.Append(errs, appError.ErrClient.WithWrappedError(appError.ErrPostgres.WithError(err)).WithMessage("Failed to get clients transfer from db").Err())
+	}
+
+	for _, t := range transfers {
+		c, ok := s.clients.get(getClientID(t.UserID, t.GroupID))
+		// the expired clients are already blocked
+		if !ok || c.Banned || c.TransferQuota <= 0 || t.Transfer < c.TransferQuota {
+			continue
+		}
+
+		log.Debug().Str("userID", c.UserID.String()).Str("groupID", c.GroupID.String()).Int64("transfer", t.Transfer).Msg("Blocking client over the transfer quota")
+		if err = s.blockClient(ctx, c, model.WebhookEventClientQuotaExceeded, model.ClientBanReasonQuotaExceeded, map[string]any{"transferQuota": c.TransferQuota, "transfer": t.Transfer}); err != nil {
+			errs = multierror.Append(errs, err)
+		}
+	}
+
+	return errs
+}
+
+// blockClient bans the client by its limit and queues the event of the limit
+func (s *Service) blockClient(ctx context.Context, c *model.Client, eventType, reason string, data map[string]any) error {
+	if err := s.firewall.AddBlockRule(getClientID(c.UserID, c.GroupID), c.Address); err != nil {
+		return appError.ErrClient.WithError(err).WithMessage("Failed to add client blocking rule").WithContext("userID", c.UserID).WithContext("groupID", c.GroupID).Err()
+	}
+
+	if err := s.storeWithEvents(ctx, eventType, clientEvents([]*model.Client{c}, data), func(q postgres.TxQuerier) error {
+		_, err := q.UpdateVPNClientsBanStatus(ctx, postgres.UpdateVPNClientsBanStatusParams{
+			NodeID:    s.config.Node.ID,
+			UserID:    nullUUID(c.UserID),
+			GroupID:   nullUUID(c.GroupID),
+			Banned:    true,
+			BanReason: reason,
+		})
+		return err
+	}); err != nil {
+		return appError.ErrClient.WithWrappedError(appError.ErrPostgres.WithError(err)).WithMessage("Failed to update client ban status in db").WithContext("userID", c.UserID).WithContext("groupID", c.GroupID).Err()
+	}
+
+	s.clients.setBanned(true, c)
+	return nil
+}
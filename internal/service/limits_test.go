@@ -0,0 +1,95 @@
+package service_test
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/cybericebox/wireguard/internal/model"
+	"github.com/cybericebox/wireguard/pkg/controller/grpc/client"
+	"github.com/cybericebox/wireguard/pkg/controller/grpc/protobuf"
+	"github.com/cybericebox/wireguard/pkg/wgtest"
+	"github.com/gofrs/uuid"
+	"testing"
+	"time"
+)
+
+func TestClientLimitsBlockClientsAndQueueEvents(t *testing.T) {
+	h := wgtest.NewHarness(t)
+	h.SetWebhookURLs("https://hooks.wgtest/limits")
+	c := h.Client(t, []string{client.RoleAdmin}, "")
+	ctx := context.Background()
+
+	groupID := uuid.Must(uuid.NewV4()).String()
+	expiringID, quotaID := uuid.Must(uuid.NewV4()).String(), uuid.Must(uuid.NewV4()).String()
+	for _, userID := range []string{expiringID, quotaID} {
+		if _, err := c.GetClientConfig(ctx, &protobuf.ClientConfigRequest{UserID: userID, GroupID: groupID, DestCIDR: "10.0.0.0/24"}); err != nil {
+			t.Fatalf("get client config: %v", err)
+		}
+	}
+
+	if _, err := c.SetClientLimits(ctx, &protobuf.ClientsRequest{UserID: quotaID, GroupID: groupID, TransferQuota: -1}); err == nil {
+		t.Fatal("negative transfer quota was set")
+	}
+	if _, err := c.SetClientLimits(ctx, &protobuf.ClientsRequest{UserID: expiringID, GroupID: groupID, ExpiresAt: time.Now().Add(-time.Minute).Unix()}); err != nil {
+		t.Fatalf("set expiration: %v", err)
+	}
+	if _, err := c.SetClientLimits(ctx, &protobuf.ClientsRequest{UserID: quotaID, GroupID: groupID, TransferQuota: 1000}); err != nil {
+		t.Fatalf("set transfer quota: %v", err)
+	}
+
+	details, err := c.GetClient(ctx, &protobuf.ClientRequest{UserID: quotaID, GroupID: groupID})
+	if err != nil {
+		t.Fatalf("get client: %v", err)
+	}
+	if details.GetClient().GetTransferQuota() != 1000 {
+		t.Fatalf("expected transfer quota 1000, got %d", details.GetClient().GetTransferQuota())
+	}
+
+	// the first sample opens the session of the client, the second one records its transfer over the quota
+	publicKey := details.GetClient().GetPublicKey()
+	h.PeerBackend.SetHandshake(publicKey, "203.0.113.1:51820", time.Now(), 100, 100)
+	if err = h.Sample(ctx); err != nil {
+		t.Fatalf("sample: %v", err)
+	}
+	h.PeerBackend.SetHandshake(publicKey, "203.0.113.1:51820", time.Now(), 600, 600)
+	if err = h.Sample(ctx); err != nil {
+		t.Fatalf("sample: %v", err)
+	}
+
+	reasons := make(map[string]string)
+	for _, row := range h.Repository.Clients() {
+		if row.Banned {
+			reasons[row.UserID.String()] = row.BanReason
+		}
+	}
+	if reasons[expiringID] != model.ClientBanReasonExpired || reasons[quotaID] != model.ClientBanReasonQuotaExceeded {
+		t.Fatalf("expected the clients to be banned by their limits, got %v", reasons)
+	}
+	if rules := h.Firewall.Rules(); len(rules.Block) != 2 {
+		t.Fatalf("expected 2 blocking rules, got %d", len(rules.Block))
+	}
+
+	events := make(map[string]string)
+	for _, d := range h.Repository.WebhookDeliveries() {
+		var payload struct {
+			UserID string `json:"userID"`
+		}
+		if err = json.Unmarshal(d.Payload, &payload); err != nil {
+			t.Fatalf("unmarshal payload: %v", err)
+		}
+		if d.EventType != model.WebhookEventClientCreated {
+			events[payload.UserID] = d.EventType
+		}
+	}
+	if len(events) != 2 || events[expiringID] != model.WebhookEventClientExpired || events[quotaID] != model.WebhookEventClientQuotaExceeded {
+		t.Fatalf("expected the expired and quota exceeded events, got %v", events)
+	}
+
+	// the blocked clients are not blocked again
+	deliveries := len(h.Repository.WebhookDeliveries())
+	if err = h.Sample(ctx); err != nil {
+		t.Fatalf("sample: %v", err)
+	}
+	if after := len(h.Repository.WebhookDeliveries()); after != deliveries {
+		t.Fatalf("expected no more deliveries, got %d more", after-deliveries)
+	}
+}
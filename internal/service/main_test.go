@@ -0,0 +1,15 @@
+package service_test
+
+import (
+	"os"
+	"testing"
+)
+
+// TestMain runs the tests from the module root as the application runs,
+// the errors trim the working directory from the paths of the files they are created in
+func TestMain(m *testing.M) {
+	if err := os.Chdir("../.."); err != nil {
+		panic(err)
+	}
+	os.Exit(m.Run())
+}
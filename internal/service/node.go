@@ -0,0 +1,278 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"github.com/cybericebox/wireguard/internal/config"
+	"github.com/cybericebox/wireguard/internal/delivery/repository/postgres"
+	"github.com/cybericebox/wireguard/internal/model"
+	"github.com/cybericebox/wireguard/pkg/appError"
+	"github.com/gofrs/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/rs/zerolog/log"
+	"time"
+)
+
+// NodeID returns the ID of the current VPN node
+func (s *Service) NodeID() string {
+	return s.config.Node.ID
+}
+
+// keyPairSettingsKey returns the platform settings key of the current node key pair
+func (s *Service) keyPairSettingsKey() string {
+	// keep the key of the single node deployments unchanged
+	if s.config.Node.ID == config.DefaultNodeID {
+		return config.VPNKeyPair
+	}
+	return config.VPNKeyPair + "-" + s.config.Node.ID
+}
+
+// InitNode registers the current node in the node registry
+func (s *Service) InitNode(ctx context.Context) error {
+	log.Debug().Str("nodeID", s.config.Node.ID).Str("grpcEndpoint", s.config.Node.GRPCEndpoint).Msg("Registering node")
+	if err := s.repository.UpsertVPNNode(ctx, postgres.UpsertVPNNodeParams{
+		ID:           s.config.Node.ID,
+		Endpoint:     s.config.Endpoint,
+		PublicKey:    s.config.KeyPair.PublicKey,
+		GrpcEndpoint: s.config.Node.GRPCEndpoint,
+	}); err != nil {
+		return appError.ErrPlatform.WithWrappedError(appError.ErrPostgres.WithError(err)).WithMessage("Failed to register node").Err()
+	}
+
+	log.Debug().Str("nodeID", s.config.Node.ID).Msg("Node registered")
+	return nil
+}
+
+// RunNodeHeartbeat periodically updates the heartbeat of the current node until the context is done
+func (s *Service) RunNodeHeartbeat(ctx context.Context) {
+	ticker := time.NewTicker(s.config.Node.HeartbeatInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Debug().Str("nodeID", s.config.Node.ID).Msg("Node heartbeat stopped")
+			return
+		case <-ticker.C:
+			affected, err := s.repository.UpdateVPNNodeHeartbeat(ctx, s.config.Node.ID)
+			if err != nil {
+				log.Error().Err(err).Str("nodeID", s.config.Node.ID).Msg("Failed to update node heartbeat")
+				continue
+			}
+
+			// node was removed from the registry, register it again
+			if affected == 0 {
+				if err = s.InitNode(ctx); err != nil {
+					log.Error().Err(err).Str("nodeID", s.config.Node.ID).Msg("Failed to register node again")
+				}
+			}
+		}
+	}
+}
+
+// GetNodes returns all alive nodes of the registry
+func (s *Service) GetNodes(ctx context.Context) ([]*model.Node, error) {
+	nodes, err := s.repository.GetAliveVPNNodes(ctx, s.aliveNodesSince())
+	if err != nil {
+		return nil, appError.ErrPlatform.WithWrappedError(appError.ErrPostgres.WithError(err)).WithMessage("Failed to get alive nodes").Err()
+	}
+
+	result := make([]*model.Node, 0, len(nodes))
+	for _, n := range nodes {
+		result = append(result, nodeFromRow(n))
+	}
+
+	return result, nil
+}
+
+// GetClientNode returns the node which serves the client or the node the new client has to be assigned to
+func (s *Service) GetClientNode(ctx context.Context, userID, groupID uuid.UUID) (*model.Node, error) {
+	return s.newNodePlanner().clientNode(ctx, userID, groupID)
+}
+
+// GetClientsNodes returns the nodes of the clients in the order of the items with their own errors.
+// The new clients are assigned as a whole batch, so the clients assigned earlier in the batch count in the load of the nodes.
+func (s *Service) GetClientsNodes(ctx context.Context, items []*model.ProvisionItem) []*model.ClientNode {
+	planner := s.newNodePlanner()
+
+	result := make([]*model.ClientNode, 0, len(items))
+	for _, item := range items {
+		node, err := planner.clientNode(ctx, item.UserID, item.GroupID)
+		result = append(result, &model.ClientNode{
+			UserID:  item.UserID,
+			GroupID: item.GroupID,
+			Node:    node,
+			Err:     err,
+		})
+	}
+
+	return result
+}
+
+// nodePlanner assigns the new clients to the nodes, it keeps the assignments of the clients planned before
+type nodePlanner struct {
+	s *Service
+	// load is the number of clients of the alive nodes with the planned ones, it is loaded with the first assignment by load
+	load []postgres.GetAliveVPNNodesLoadRow
+	// loaded is set when the load is loaded
+	loaded bool
+	// groups are the nodes of the groups by group ID
+	groups map[uuid.UUID]*model.Node
+	// nodes are the loaded nodes by node ID
+	nodes map[string]*model.Node
+}
+
+func (s *Service) newNodePlanner() *nodePlanner {
+	return &nodePlanner{
+		s:      s,
+		groups: make(map[uuid.UUID]*model.Node),
+		nodes:  make(map[string]*model.Node),
+	}
+}
+
+// clientNode returns the node which serves the client or the node the new client has to be assigned to
+func (p *nodePlanner) clientNode(ctx context.Context, userID, groupID uuid.UUID) (*model.Node, error) {
+	s := p.s
+	ex := s.clients.has(getClientID(userID, groupID))
+
+	if ex {
+		return s.currentNode(), nil
+	}
+
+	log.Debug().Str("userID", userID.String()).Str("groupID", groupID.String()).Msg("Getting client node from db")
+	nodeID, err := s.repository.GetVPNClientNodeID(ctx, postgres.GetVPNClientNodeIDParams{
+		UserID:  userID,
+		GroupID: groupID,
+	})
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return nil, appError.ErrPlatform.WithWrappedError(appError.ErrPostgres.WithError(err)).WithMessage("Failed to get client node").Err()
+	}
+
+	// client already exists, it has to be served by its node even if the node is down
+	if err == nil {
+		return p.node(ctx, nodeID)
+	}
+
+	log.Debug().Str("userID", userID.String()).Str("groupID", groupID.String()).Str("assignment", s.config.Node.Assignment).Msg("Assigning client to node")
+	if s.config.Node.Assignment != config.NodeAssignmentGroup {
+		return p.leastLoadedNode(ctx)
+	}
+
+	// the group is already assigned by the planned clients
+	if node, ok := p.groups[groupID]; ok {
+		return node, nil
+	}
+
+	nodeID, err = s.repository.GetVPNGroupNodeID(ctx, groupID)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return nil, appError.ErrPlatform.WithWrappedError(appError.ErrPostgres.WithError(err)).WithMessage("Failed to get group node").Err()
+	}
+
+	if err == nil {
+		node, err := p.node(ctx, nodeID)
+		if err != nil {
+			return nil, appError.ErrPlatform.WithError(err).WithMessage("Failed to get group node").Err()
+		}
+
+		if node.HeartbeatAt.After(s.aliveNodesSince()) {
+			p.groups[groupID] = node
+			return node, nil
+		}
+		log.Warn().Str("nodeID", nodeID).Str("groupID", groupID.String()).Msg("Group node is not alive, assigning client by load")
+	}
+
+	node, err := p.leastLoadedNode(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	p.groups[groupID] = node
+	return node, nil
+}
+
+// leastLoadedNode returns the alive node with the least clients and counts the client in its load
+func (p *nodePlanner) leastLoadedNode(ctx context.Context) (*model.Node, error) {
+	if !p.loaded {
+		load, err := p.s.repository.GetAliveVPNNodesLoad(ctx, p.s.aliveNodesSince())
+		if err != nil {
+			return nil, appError.ErrPlatform.WithWrappedError(appError.ErrPostgres.WithError(err)).WithMessage("Failed to get nodes load").Err()
+		}
+		p.load, p.loaded = load, true
+	}
+
+	// no alive nodes in registry, serve the client by the current node
+	if len(p.load) == 0 {
+		return p.s.currentNode(), nil
+	}
+
+	// the ties are taken in the order of the node IDs as the load is ordered
+	least := 0
+	for i, n := range p.load {
+		if n.Clients < p.load[least].Clients || n.Clients == p.load[least].Clients && n.ID < p.load[least].ID {
+			least = i
+		}
+	}
+
+	node, err := p.node(ctx, p.load[least].ID)
+	if err != nil {
+		return nil, err
+	}
+
+	p.load[least].Clients++
+	return node, nil
+}
+
+// node returns the node by its ID, the nodes are loaded once per planner
+func (p *nodePlanner) node(ctx context.Context, nodeID string) (*model.Node, error) {
+	if node, ok := p.nodes[nodeID]; ok {
+		return node, nil
+	}
+
+	node, err := p.s.getNode(ctx, nodeID)
+	if err != nil {
+		return nil, err
+	}
+
+	p.nodes[nodeID] = node
+	return node, nil
+}
+
+func (s *Service) getNode(ctx context.Context, nodeID string) (*model.Node, error) {
+	if nodeID == s.config.Node.ID {
+		return s.currentNode(), nil
+	}
+
+	node, err := s.repository.GetVPNNode(ctx, nodeID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, appError.ErrNodeNotFound.WithContext("nodeID", nodeID).Err()
+		}
+		return nil, appError.ErrPlatform.WithWrappedError(appError.ErrPostgres.WithError(err)).WithMessage("Failed to get node").Err()
+	}
+
+	return nodeFromRow(node), nil
+}
+
+func (s *Service) currentNode() *model.Node {
+	return &model.Node{
+		ID:           s.config.Node.ID,
+		Endpoint:     s.config.Endpoint,
+		PublicKey:    s.config.KeyPair.PublicKey,
+		GRPCEndpoint: s.config.Node.GRPCEndpoint,
+		HeartbeatAt:  time.Now(),
+	}
+}
+
+func (s *Service) aliveNodesSince() time.Time {
+	return time.Now().Add(-s.config.Node.HeartbeatTimeout)
+}
+
+func nodeFromRow(n postgres.VpnNode) *model.Node {
+	return &model.Node{
+		ID:           n.ID,
+		Endpoint:     n.Endpoint,
+		PublicKey:    n.PublicKey,
+		GRPCEndpoint: n.GrpcEndpoint,
+		HeartbeatAt:  n.HeartbeatAt,
+	}
+}
@@ -0,0 +1,89 @@
+package service
+
+import (
+	"context"
+	"github.com/cybericebox/lib/pkg/wgKeyGen"
+	"github.com/cybericebox/wireguard/internal/config"
+	"github.com/cybericebox/wireguard/internal/delivery/repository/postgres"
+	"github.com/cybericebox/wireguard/internal/model"
+	"github.com/gofrs/uuid"
+	"github.com/jackc/pgx/v5"
+	"slices"
+	"testing"
+	"time"
+)
+
+// nodeRepository is the registry of the nodes without clients, other methods of the repository are not used by the assignment
+type nodeRepository struct {
+	Repository
+	load []postgres.GetAliveVPNNodesLoadRow
+}
+
+func (r *nodeRepository) GetVPNClientNodeID(context.Context, postgres.GetVPNClientNodeIDParams) (string, error) {
+	return "", pgx.ErrNoRows
+}
+
+func (r *nodeRepository) GetVPNGroupNodeID(context.Context, uuid.UUID) (string, error) {
+	return "", pgx.ErrNoRows
+}
+
+func (r *nodeRepository) GetAliveVPNNodesLoad(context.Context, time.Time) ([]postgres.GetAliveVPNNodesLoadRow, error) {
+	return r.load, nil
+}
+
+func (r *nodeRepository) GetVPNNode(_ context.Context, id string) (postgres.VpnNode, error) {
+	return postgres.VpnNode{ID: id, HeartbeatAt: time.Now()}, nil
+}
+
+func TestGetClientsNodesPlansTheBatch(t *testing.T) {
+	newService := func(assignment string) *Service {
+		return &Service{
+			config: &config.VPNConfig{
+				KeyPair: &wgKeyGen.KeyPair{},
+				Node: config.NodeConfig{
+					ID:               "a",
+					Assignment:       assignment,
+					HeartbeatTimeout: time.Minute,
+				},
+			},
+			clients: newClientCache(),
+			repository: &nodeRepository{load: []postgres.GetAliveVPNNodesLoadRow{
+				{ID: "b", Clients: 0},
+				{ID: "a", Clients: 1},
+			}},
+		}
+	}
+	nodeIDs := func(nodes []*model.ClientNode) []string {
+		t.Helper()
+
+		ids := make([]string, 0, len(nodes))
+		for _, n := range nodes {
+			if n.Err != nil {
+				t.Fatalf("get client node: %v", n.Err)
+			}
+			ids = append(ids, n.Node.ID)
+		}
+		return ids
+	}
+	items := func(groupIDs ...uuid.UUID) []*model.ProvisionItem {
+		result := make([]*model.ProvisionItem, 0, len(groupIDs))
+		for _, groupID := range groupIDs {
+			result = append(result, &model.ProvisionItem{UserID: uuid.Must(uuid.NewV4()), GroupID: groupID})
+		}
+		return result
+	}
+
+	// the clients assigned earlier in the batch count in the load of the nodes
+	ctx := context.Background()
+	first, second := uuid.Must(uuid.NewV4()), uuid.Must(uuid.NewV4())
+	got := nodeIDs(newService(config.NodeAssignmentLoad).GetClientsNodes(ctx, items(first, first, second, second)))
+	if want := []string{"b", "a", "b", "a"}; !slices.Equal(got, want) {
+		t.Fatalf("load assignment = %v, want %v", got, want)
+	}
+
+	// the clients of the new group are assigned to the node of the first client of the group
+	got = nodeIDs(newService(config.NodeAssignmentGroup).GetClientsNodes(ctx, items(first, first, second, second)))
+	if want := []string{"b", "b", "a", "a"}; !slices.Equal(got, want) {
+		t.Fatalf("group assignment = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,89 @@
+package service
+
+import (
+	"context"
+	"github.com/cybericebox/wireguard/internal/model"
+	"github.com/cybericebox/wireguard/pkg/appError"
+	"github.com/gofrs/uuid"
+	"github.com/rs/zerolog/log"
+	"net/netip"
+)
+
+// PlanClientsOperation returns the clients the operation would change and the changes it would make, without making them
+func (s *Service) PlanClientsOperation(_ context.Context, operation string, userID, groupID uuid.UUID) ([]*model.Client, []*model.PlannedChange) {
+	log.Debug().Str("operation", operation).Str("userID", userID.String()).Str("groupID", groupID.String()).Msg("Planning clients operation")
+	clients := s.getFilteredClients(userID, groupID, operationFilter(operation))
+
+	plan := make([]*model.PlannedChange, 0, len(clients))
+	for _, c := range clients {
+		id := getClientID(c.UserID, c.GroupID)
+		change := s.plannedChange(c.UserID, c.GroupID)
+
+		switch operation {
+		case model.ClientsOperationDelete:
+			plan = append(plan,
+				change(model.PlanResourcePeer, model.PlanActionDelete, c.PublicKey),
+				change(model.PlanResourceNATRule, model.PlanActionDelete, s.firewall.DescribeNATRule(model.PlanActionDelete, id, c.Address, c.AllowedIPs)),
+			)
+			if c.Banned {
+				plan = append(plan, change(model.PlanResourceBlockRule, model.PlanActionDelete, s.firewall.DescribeBlockRule(model.PlanActionDelete, id, c.Address)))
+			}
+			plan = append(plan,
+				change(model.PlanResourceIP, model.PlanActionDelete, c.Address),
+				change(model.PlanResourceDatabase, model.PlanActionDelete, id),
+			)
+		case model.ClientsOperationBan:
+			plan = append(plan,
+				change(model.PlanResourceBlockRule, model.PlanActionAdd, s.firewall.DescribeBlockRule(model.PlanActionAdd, id, c.Address)),
+				change(model.PlanResourceDatabase, model.PlanActionUpdate, id),
+			)
+		case model.ClientsOperationUnBan:
+			plan = append(plan,
+				change(model.PlanResourceBlockRule, model.PlanActionDelete, s.firewall.DescribeBlockRule(model.PlanActionDelete, id, c.Address)),
+				change(model.PlanResourceDatabase, model.PlanActionUpdate, id),
+			)
+		case model.ClientsOperationSetLimits:
+			plan = append(plan, change(model.PlanResourceDatabase, model.PlanActionUpdate, id))
+		}
+	}
+
+	return clients, plan
+}
+
+// PlanClientConfig returns the changes getting the client config would make, the existing clients need no changes.
+// The address and the keys of the new client are allocated only when it is created, so their targets are empty.
+func (s *Service) PlanClientConfig(_ context.Context, userID, groupID uuid.UUID, destCIDR string) ([]*model.PlannedChange, error) {
+	ex := s.clients.has(getClientID(userID, groupID))
+
+	if ex {
+		return []*model.PlannedChange{}, nil
+	}
+
+	if _, err := netip.ParsePrefix(destCIDR); err != nil {
+		return nil, appError.ErrClientInvalidAllowedIPs.WithError(err).Err()
+	}
+
+	log.Debug().Str("userID", userID.String()).Str("groupID", groupID.String()).Msg("Planning client creation")
+	id := getClientID(userID, groupID)
+	change := s.plannedChange(userID, groupID)
+
+	return []*model.PlannedChange{
+		change(model.PlanResourceIP, model.PlanActionAdd, ""),
+		change(model.PlanResourcePeer, model.PlanActionAdd, ""),
+		change(model.PlanResourceNATRule, model.PlanActionAdd, s.firewall.DescribeNATRule(model.PlanActionAdd, id, "<address>", destCIDR)),
+		change(model.PlanResourceDatabase, model.PlanActionAdd, id),
+	}, nil
+}
+
+func (s *Service) plannedChange(userID, groupID uuid.UUID) func(resource, action, target string) *model.PlannedChange {
+	return func(resource, action, target string) *model.PlannedChange {
+		return &model.PlannedChange{
+			UserID:   userID,
+			GroupID:  groupID,
+			NodeID:   s.config.Node.ID,
+			Resource: resource,
+			Action:   action,
+			Target:   target,
+		}
+	}
+}
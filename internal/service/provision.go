@@ -0,0 +1,251 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"github.com/cybericebox/lib/pkg/ipam"
+	"github.com/cybericebox/wireguard/internal/delivery/repository/postgres"
+	"github.com/cybericebox/wireguard/internal/model"
+	"github.com/cybericebox/wireguard/pkg/appError"
+	"github.com/rs/zerolog/log"
+	"net/netip"
+	"strings"
+)
+
+// ProvisionClients returns the configs of the clients creating the missing ones in bulk.
+// The result of every client is returned in the order of the items with its own error.
+func (s *Service) ProvisionClients(ctx context.Context, items []*model.ProvisionItem) []*model.ProvisionResult {
+	results := make([]*model.ProvisionResult, len(items))
+	pending := make([]*model.Client, 0, len(items))
+	pendingResults := make([]*model.ProvisionResult, 0, len(items))
+	pendingCreations := make([]*creation, 0, len(items))
+	var waiting []*creation
+	var waitingResults []*model.ProvisionResult
+	seen := make(map[string]bool, len(items))
+
+	for i, item := range items {
+		results[i] = &model.ProvisionResult{
+			UserID:  item.UserID,
+			GroupID: item.GroupID,
+		}
+
+		id := getClientID(item.UserID, item.GroupID)
+		if seen[id] {
+			results[i].Err = appError.ErrClientDuplicate.WithContext("userID", item.UserID.String()).WithContext("groupID", item.GroupID.String()).Err()
+			continue
+		}
+		seen[id] = true
+
+		if _, err := netip.ParsePrefix(item.DestCIDR); err != nil {
+			results[i].Err = appError.ErrClientInvalidAllowedIPs.WithError(err).Err()
+			continue
+		}
+
+		client, c, leader := s.claimCreation(id)
+		switch {
+		case client != nil:
+			if results[i].Config, results[i].Err = s.generateClientConfig(client); results[i].Err != nil {
+				results[i].Err = appError.ErrClient.WithError(results[i].Err).WithMessage("Failed to generate client config").Err()
+			}
+		case leader:
+			pending = append(pending, &model.Client{
+				UserID:     item.UserID,
+				GroupID:    item.GroupID,
+				NodeID:     s.config.Node.ID,
+				AllowedIPs: item.DestCIDR,
+			})
+			pendingResults = append(pendingResults, results[i])
+			pendingCreations = append(pendingCreations, c)
+		default:
+			// the client is being created by a concurrent request
+			waiting = append(waiting, c)
+			waitingResults = append(waitingResults, results[i])
+		}
+	}
+
+	log.Debug().Int("clients", len(items)).Int("new", len(pending)).Msg("Provisioning clients")
+	// the waiting requests share the creations, so they are not canceled with the batch
+	for i, err := range s.createClients(context.WithoutCancel(ctx), pending) {
+		client := pending[i]
+		if err != nil {
+			client = nil
+		}
+		s.finishCreation(getClientID(pending[i].UserID, pending[i].GroupID), pendingCreations[i], client, err)
+
+		if err != nil {
+			pendingResults[i].Err = err
+			continue
+		}
+
+		if pendingResults[i].Config, err = s.generateClientConfig(pending[i]); err != nil {
+			pendingResults[i].Err = appError.ErrClient.WithError(err).WithMessage("Failed to generate client config").Err()
+		}
+	}
+
+	for i, c := range waiting {
+		client, err := c.wait(ctx)
+		if err == nil {
+			waitingResults[i].Config, err = s.generateClientConfig(client)
+		}
+		if err != nil {
+			waitingResults[i].Err = appError.ErrClient.WithError(err).WithMessage("Failed to generate client config").Err()
+		}
+	}
+
+	return results
+}
+
+// createClients creates the clients with single calls of the backends and returns the error of every client.
+// The peers, rules and addresses of the clients which were not created are removed.
+func (s *Service) createClients(ctx context.Context, clients []*model.Client) []error {
+	errs := make([]error, len(clients))
+	if len(clients) == 0 {
+		return errs
+	}
+
+	s.kernel.RLock()
+	defer s.kernel.RUnlock()
+
+	log.Debug().Int("clients", len(clients)).Msg("Acquiring client ips")
+	addrs, err := s.ipaManager.AcquireIPs(ctx, len(clients))
+	if err != nil {
+		// the clients without the address fail, the others are created
+		for i := len(addrs); i < len(clients); i++ {
+			errs[i] = appError.ErrClient.WithError(err).WithMessage("Failed to acquire client ip").Err()
+		}
+	}
+
+	prepared := make([]*model.Client, 0, len(addrs))
+	peers := make([]model.Peer, 0, len(addrs))
+	natRules := make([]model.FirewallRule, 0, len(addrs))
+	preparedIndexes := make([]int, 0, len(addrs))
+	params := make([]postgres.CreateVpnClientsParams, 0, len(addrs))
+
+	for i, addr := range addrs {
+		client := clients[i]
+		if errs[i] = s.prepareClient(client, addr); errs[i] != nil {
+			s.releaseIP(ctx, addr)
+			continue
+		}
+
+		ip, _ := netip.ParsePrefix(client.Address)
+		allowedIPs, _ := netip.ParsePrefix(client.AllowedIPs)
+		params = append(params, postgres.CreateVpnClientsParams{
+			UserID:         client.UserID,
+			GroupID:        client.GroupID,
+			IpAddress:      ip,
+			PublicKey:      client.PublicKey,
+			PrivateKey:     client.PrivateKey,
+			LaboratoryCidr: allowedIPs,
+			NodeID:         client.NodeID,
+		})
+		peers = append(peers, model.Peer{
+			PublicKey:  client.PublicKey,
+			AllowedIPs: client.Address,
+		})
+		natRules = append(natRules, model.FirewallRule{
+			ID:          getClientID(client.UserID, client.GroupID),
+			Source:      client.Address,
+			Destination: client.AllowedIPs,
+		})
+		prepared = append(prepared, client)
+		preparedIndexes = append(preparedIndexes, i)
+	}
+
+	if len(prepared) == 0 {
+		return errs
+	}
+
+	// fail fails all prepared clients and removes what was added for them
+	fail := func(err error, peersAdded, rulesAdded bool) []error {
+		for j, i := range preparedIndexes {
+			errs[i] = err
+			s.discardClient(ctx, prepared[j], peersAdded, rulesAdded)
+		}
+		return errs
+	}
+
+	if err = s.peers.AddPeers(peers); err != nil {
+		// the peers added before the failure are removed
+		return fail(appError.ErrClient.WithError(err).WithMessage("Failed to add client peers").Err(), true, false)
+	}
+
+	if err = s.firewall.AddNATRules(natRules); err != nil {
+		return fail(appError.ErrClient.WithError(err).WithMessage("Failed to add client NAT rules").Err(), true, true)
+	}
+
+	log.Debug().Int("clients", len(params)).Msg("Creating clients in db")
+	created := prepared
+	if err = s.storeClients(ctx, params, prepared); err != nil {
+		// the batch fails as a whole, e.g. on the conflict of a single row, so the rows are created one by one to fail only the conflicting ones
+		log.Warn().Err(err).Int("clients", len(params)).Msg("Failed to create clients in db in bulk, creating them one by one")
+		created = make([]*model.Client, 0, len(prepared))
+		for j, i := range preparedIndexes {
+			if err = s.storeClients(ctx, params[j:j+1], prepared[j:j+1]); err != nil {
+				errs[i] = appError.ErrClient.WithWrappedError(appError.ErrPostgres.WithError(err)).WithMessage("Failed to create client in db").Err()
+				s.discardClient(ctx, prepared[j], true, true)
+				continue
+			}
+			created = append(created, prepared[j])
+		}
+	}
+
+	log.Debug().Int("clients", len(created)).Msg("Adding clients to cache")
+	s.clients.put(created...)
+
+	return errs
+}
+
+// storeClients stores the clients of the parameters with their created events
+func (s *Service) storeClients(ctx context.Context, params []postgres.CreateVpnClientsParams, clients []*model.Client) error {
+	return s.storeWithEvents(ctx, model.WebhookEventClientCreated, createdEvents(clients...), func(q postgres.TxQuerier) error {
+		_, err := q.CreateVpnClients(ctx, params)
+		return err
+	})
+}
+
+// discardClient removes the peer and the NAT rule of the client which was not created and releases its address.
+// The failures are only logged, the reconcile removes what is left as the client is not cached.
+func (s *Service) discardClient(ctx context.Context, client *model.Client, peerAdded, ruleAdded bool) {
+	if peerAdded {
+		if err := s.peers.DeletePeer(client.Address, client.PublicKey); err != nil {
+			log.Error().Err(err).Str("userID", client.UserID.String()).Str("groupID", client.GroupID.String()).Msg("Failed to delete peer of not created client")
+		}
+	}
+
+	if ruleAdded {
+		if err := s.firewall.DeleteNATRule(getClientID(client.UserID, client.GroupID), client.Address, client.AllowedIPs); err != nil {
+			log.Error().Err(err).Str("userID", client.UserID.String()).Str("groupID", client.GroupID.String()).Msg("Failed to delete NAT rule of not created client")
+		}
+	}
+
+	s.releaseIP(ctx, client.Address)
+}
+
+// prepareClient sets the address, keys and DNS address of the new client
+func (s *Service) prepareClient(client *model.Client, addr string) (err error) {
+	client.Address = fmt.Sprintf("%s/32", addr)
+
+	log.Debug().Str("userID", client.UserID.String()).Str("groupID", client.GroupID.String()).Msg("Generating client key pair")
+	keys, err := s.keyGenerator.NewKeyPair()
+	if err != nil {
+		return appError.ErrClient.WithError(err).WithMessage("Failed to generate client key pair").Err()
+	}
+	client.PublicKey, client.PrivateKey = keys.PublicKey, keys.PrivateKey
+
+	client.DNS, err = ipam.GetFirstCIDRIP(client.AllowedIPs)
+	if err != nil {
+		return appError.ErrClient.WithError(err).WithMessage("Failed to generate client DNS ip").Err()
+	}
+
+	return nil
+}
+
+// releaseIP releases the address of the client which was not created, the failure is only logged
+func (s *Service) releaseIP(ctx context.Context, addr string) {
+	// cut mask from address, because release function get only ip
+	addr, _ = strings.CutSuffix(addr, "/32")
+	if err := s.ipaManager.ReleaseSingleIP(ctx, addr); err != nil {
+		log.Error().Err(err).Str("address", addr).Msg("Failed to release client ip")
+	}
+}
@@ -0,0 +1,101 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"github.com/cybericebox/wireguard/internal/delivery/repository/postgres"
+	"github.com/cybericebox/wireguard/pkg/controller/grpc/client"
+	"github.com/cybericebox/wireguard/pkg/controller/grpc/protobuf"
+	"github.com/cybericebox/wireguard/pkg/wgtest"
+	"github.com/gofrs/uuid"
+	"net/netip"
+	"testing"
+)
+
+// provisionRequest returns the request of new clients of the group
+func provisionRequest(groupID uuid.UUID, userIDs ...uuid.UUID) *protobuf.ProvisionClientsRequest {
+	request := &protobuf.ProvisionClientsRequest{}
+	for _, userID := range userIDs {
+		request.Clients = append(request.Clients, &protobuf.ProvisionClient{UserID: userID.String(), GroupID: groupID.String(), DestCIDR: "10.0.0.0/24"})
+	}
+	return request
+}
+
+func TestProvisionConflictFailsOnlyConflictingClient(t *testing.T) {
+	h := wgtest.NewHarness(t)
+	c := h.Client(t, []string{client.RoleAdmin}, "")
+	ctx := context.Background()
+
+	groupID := uuid.Must(uuid.NewV4())
+	conflicting, first, second := uuid.Must(uuid.NewV4()), uuid.Must(uuid.NewV4()), uuid.Must(uuid.NewV4())
+
+	// the stored row is not in the cache, so the node creates the client again
+	if _, err := h.Repository.CreateVpnClients(ctx, []postgres.CreateVpnClientsParams{{
+		UserID:         conflicting,
+		GroupID:        groupID,
+		IpAddress:      netip.MustParsePrefix("10.128.255.1/32"),
+		PublicKey:      "other-node-key",
+		LaboratoryCidr: netip.MustParsePrefix("10.0.0.0/24"),
+		NodeID:         wgtest.NodeID,
+	}}); err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := c.ProvisionClients(ctx, provisionRequest(groupID, conflicting, first, second))
+	if err != nil {
+		t.Fatalf("provision clients: %v", err)
+	}
+
+	results := resp.GetClients()
+	if results[0].GetError() == "" {
+		t.Fatal("conflicting client was provisioned")
+	}
+	for _, r := range results[1:] {
+		if r.GetError() != "" || r.GetConfig() == "" {
+			t.Fatalf("client %s failed with the conflicting one: %s", r.GetUserID(), r.GetError())
+		}
+	}
+
+	if peers := h.PeerBackend.Peers(); len(peers) != 2 {
+		t.Errorf("expected 2 peers, got %d", len(peers))
+	}
+	if rules := h.Firewall.Rules(); len(rules.NAT) != 2 {
+		t.Errorf("expected 2 NAT rules, got %d", len(rules.NAT))
+	}
+	// the server address and the addresses of the created clients
+	if acquired := h.IPAManager.Acquired(); len(acquired) != 3 {
+		t.Errorf("expected 3 acquired addresses, got %v", acquired)
+	}
+	if rows := h.Repository.Clients(); len(rows) != 3 {
+		t.Errorf("expected 3 client rows, got %d", len(rows))
+	}
+}
+
+func TestProvisionFailureRemovesAddedPeers(t *testing.T) {
+	h := wgtest.NewHarness(t)
+	c := h.Client(t, []string{client.RoleAdmin}, "")
+
+	h.Firewall.SetError("AddNATRules", errors.New("nat table is locked"))
+
+	groupID := uuid.Must(uuid.NewV4())
+	resp, err := c.ProvisionClients(context.Background(), provisionRequest(groupID, uuid.Must(uuid.NewV4()), uuid.Must(uuid.NewV4())))
+	if err != nil {
+		t.Fatalf("provision clients: %v", err)
+	}
+
+	for _, r := range resp.GetClients() {
+		if r.GetError() == "" {
+			t.Fatalf("client %s was provisioned without NAT rule", r.GetUserID())
+		}
+	}
+
+	if peers := h.PeerBackend.Peers(); len(peers) != 0 {
+		t.Errorf("expected the peers removed, got %d", len(peers))
+	}
+	if acquired := h.IPAManager.Acquired(); len(acquired) != 1 {
+		t.Errorf("expected only the server address acquired, got %v", acquired)
+	}
+	if rows := h.Repository.Clients(); len(rows) != 0 {
+		t.Errorf("expected no client rows, got %d", len(rows))
+	}
+}
@@ -0,0 +1,231 @@
+package service
+
+import (
+	"context"
+	"github.com/cybericebox/wireguard/internal/config"
+	"github.com/cybericebox/wireguard/internal/model"
+	"github.com/cybericebox/wireguard/pkg/appError"
+	"github.com/hashicorp/go-multierror"
+	"github.com/rs/zerolog/log"
+	"net/netip"
+	"sync"
+	"time"
+)
+
+// reconcileState is the outcome of the last reconciliation
+type reconcileState struct {
+	m           sync.RWMutex
+	lastSuccess time.Time
+	err         error
+}
+
+// converge brings the existing peers and service-owned rules to the state of the clients.
+// Existing peers and rules are adopted, missing ones are added and stale or duplicated ones are deleted.
+// It returns the clients which were converged successfully.
+func (s *Service) converge(clients []*model.Client) ([]*model.Client, error) {
+	var errs error
+
+	log.Debug().Msg("Getting existing peers")
+	peers, err := s.peers.GetPeers()
+	if err != nil {
+		return nil, appError.ErrPlatform.WithError(err).WithMessage("Failed to get existing peers").Err()
+	}
+
+	log.Debug().Msg("Getting existing rules")
+	existing, err := s.firewall.GetRules()
+	if err != nil {
+		return nil, appError.ErrPlatform.WithError(err).WithMessage("Failed to get existing rules").Err()
+	}
+
+	natRules, blockRules := groupRulesByID(existing.NAT), groupRulesByID(existing.Block)
+
+	converged := make([]*model.Client, 0, len(clients))
+	for _, client := range clients {
+		id := getClientID(client.UserID, client.GroupID)
+
+		// add peer only if it does not exist with the same address
+		if p, ok := peers[client.PublicKey]; ok && p.AllowedIPs == client.Address {
+			log.Debug().Str("userID", client.UserID.String()).Str("groupID", client.GroupID.String()).Msg("Adopting existing client peer")
+		} else {
+			log.Debug().Str("userID", client.UserID.String()).Str("groupID", client.GroupID.String()).Msg("Adding client peer")
+			if err = s.peers.AddPeer(client.Address, client.PublicKey); err != nil {
+				errs = multierror.Append(errs, appError.ErrPlatform.WithError(err).WithMessage("Failed to add client peer").Err())
+				continue
+			}
+		}
+		delete(peers, client.PublicKey)
+
+		log.Debug().Str("userID", client.UserID.String()).Str("groupID", client.GroupID.String()).Msg("Converging client NAT rule")
+		if err = s.convergeNATRule(client, natRules[id]); err != nil {
+			errs = multierror.Append(errs, appError.ErrPlatform.WithError(err).WithMessage("Failed to converge client NAT rule").Err())
+			continue
+		}
+		delete(natRules, id)
+
+		log.Debug().Str("userID", client.UserID.String()).Str("groupID", client.GroupID.String()).Bool("banned", client.Banned).Msg("Converging client blocking rule")
+		if err = s.convergeBlockRule(client, blockRules[id]); err != nil {
+			errs = multierror.Append(errs, appError.ErrPlatform.WithError(err).WithMessage("Failed to converge client blocking rule").Err())
+			continue
+		}
+		delete(blockRules, id)
+
+		converged = append(converged, client)
+	}
+
+	// whatever is left does not belong to any client, it is not fatal if it can not be removed
+	for _, p := range peers {
+		log.Info().Str("publicKey", p.PublicKey).Str("allowedIPs", p.AllowedIPs).Msg("Deleting stale peer")
+		if err = s.peers.DeleteStalePeer(p); err != nil {
+			log.Warn().Err(err).Str("publicKey", p.PublicKey).Msg("Failed to delete stale peer")
+		}
+	}
+
+	for id, rs := range natRules {
+		for _, r := range rs {
+			log.Info().Str("id", id).Str("source", r.Source).Msg("Deleting stale NAT rule")
+			if err = s.firewall.DeleteNATRuleSpec(r); err != nil {
+				log.Warn().Err(err).Str("id", id).Msg("Failed to delete stale NAT rule")
+			}
+		}
+	}
+
+	for id, rs := range blockRules {
+		for _, r := range rs {
+			log.Info().Str("id", id).Str("source", r.Source).Msg("Deleting stale blocking rule")
+			if err = s.firewall.DeleteBlockRuleSpec(r); err != nil {
+				log.Warn().Err(err).Str("id", id).Msg("Failed to delete stale blocking rule")
+			}
+		}
+	}
+
+	if errs != nil {
+		return converged, appError.ErrPlatform.WithError(errs).WithMessage("Failed to converge clients").Err()
+	}
+
+	return converged, nil
+}
+
+// convergeNATRule keeps the first rule matching the client, deletes the others and adds the rule if it is missing
+func (s *Service) convergeNATRule(client *model.Client, existing []model.FirewallRule) error {
+	destination := normalizePrefix(client.AllowedIPs)
+
+	matched := false
+	for _, r := range existing {
+		if !matched && r.Source == client.Address && r.Destination == destination {
+			matched = true
+			continue
+		}
+
+		if err := s.firewall.DeleteNATRuleSpec(r); err != nil {
+			return appError.ErrPlatform.WithError(err).WithMessage("Failed to delete outdated NAT rule").Err()
+		}
+	}
+
+	if matched {
+		return nil
+	}
+
+	return s.firewall.AddNATRule(getClientID(client.UserID, client.GroupID), client.Address, client.AllowedIPs)
+}
+
+// convergeBlockRule keeps the first rule matching the banned client, deletes the others and adds the rule if it is missing
+func (s *Service) convergeBlockRule(client *model.Client, existing []model.FirewallRule) error {
+	matched := false
+	for _, r := range existing {
+		if client.Banned && !matched && r.Source == client.Address {
+			matched = true
+			continue
+		}
+
+		if err := s.firewall.DeleteBlockRuleSpec(r); err != nil {
+			return appError.ErrPlatform.WithError(err).WithMessage("Failed to delete outdated blocking rule").Err()
+		}
+	}
+
+	if matched || !client.Banned {
+		return nil
+	}
+
+	return s.firewall.AddBlockRule(getClientID(client.UserID, client.GroupID), client.Address)
+}
+
+func groupRulesByID(rs []model.FirewallRule) map[string][]model.FirewallRule {
+	grouped := make(map[string][]model.FirewallRule)
+	for _, r := range rs {
+		grouped[r.ID] = append(grouped[r.ID], r)
+	}
+	return grouped
+}
+
+// normalizePrefix returns the prefix as iptables lists it, e.g. 10.0.0.1/24 is listed as 10.0.0.0/24
+func normalizePrefix(prefix string) string {
+	p, err := netip.ParsePrefix(prefix)
+	if err != nil {
+		return prefix
+	}
+	return p.Masked().String()
+}
+
+// RunReconcile periodically converges the peers and rules to the cached clients until the context is done
+func (s *Service) RunReconcile(ctx context.Context) {
+	ticker := time.NewTicker(s.config.ReconcileInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Debug().Msg("Reconcile stopped")
+			return
+		case <-ticker.C:
+			if err := s.Reconcile(); err != nil {
+				log.Error().Err(err).Msg("Failed to reconcile clients")
+			}
+		}
+	}
+}
+
+// Reconcile converges the peers and rules to the cached clients and records the outcome for the health checks
+func (s *Service) Reconcile() error {
+	s.kernel.Lock()
+	defer s.kernel.Unlock()
+
+	err := s.reconcileClients()
+	s.setReconcileResult(err)
+
+	return err
+}
+
+func (s *Service) setReconcileResult(err error) {
+	s.reconcile.m.Lock()
+	defer s.reconcile.m.Unlock()
+
+	s.reconcile.err = err
+	if err == nil {
+		s.reconcile.lastSuccess = time.Now()
+	}
+}
+
+func (s *Service) reconcileClients() error {
+	// interface is gone, there is nothing to converge to
+	exists, err := s.peers.InterfaceExists()
+	if err != nil {
+		return appError.ErrPlatform.WithError(err).WithMessage("Failed to check server interface").Err()
+	}
+
+	if !exists {
+		return appError.ErrPlatform.WithMessage("Server interface does not exist").WithContext("interface", config.VPNInterface).Err()
+	}
+
+	if err = s.firewall.EnsureForwardRules(); err != nil {
+		return appError.ErrPlatform.WithError(err).WithMessage("Failed to ensure forward rules").Err()
+	}
+
+	clients := s.clients.list(nil)
+
+	log.Debug().Int("clients", len(clients)).Msg("Reconciling clients")
+	if _, err = s.converge(clients); err != nil {
+		return appError.ErrPlatform.WithError(err).WithMessage("Failed to converge clients").Err()
+	}
+
+	return nil
+}
@@ -23,49 +23,156 @@ import (
 
 type (
 	Service struct {
-		m            sync.RWMutex
 		config       *config.VPNConfig
-		clients      map[string]*model.Client
+		clients      *clientCache
+		dnsRecords   *dnsRecordCache
+		sessions     *sessionSampler
 		keyGenerator *wgKeyGen.KeyGenerator
 		repository   Repository
 		ipaManager   IPAManager
+		peers        PeerBackend
+		firewall     Firewall
+		webhooks     WebhookSender
+
+		// m guards the creations of clients in flight by client ID
+		m         sync.Mutex
+		creations map[string]*creation
+
+		// kernel is held for reading while client peers and rules are changed and for writing while they are reconciled
+		kernel    sync.RWMutex
+		reconcile reconcileState
 	}
 
 	Repository interface {
 		CreateVpnClient(ctx context.Context, arg postgres.CreateVpnClientParams) error
+		CreateVpnClients(ctx context.Context, arg []postgres.CreateVpnClientsParams) (int64, error)
 
-		GetVPNClients(ctx context.Context) ([]postgres.VpnClient, error)
+		GetNodeVPNClients(ctx context.Context, nodeID string) ([]postgres.VpnClient, error)
+		GetVPNClient(ctx context.Context, arg postgres.GetVPNClientParams) (postgres.VpnClient, error)
+		ListVPNClients(ctx context.Context, arg postgres.ListVPNClientsParams) ([]postgres.ListVPNClientsRow, error)
+		GetVPNClientNodeID(ctx context.Context, arg postgres.GetVPNClientNodeIDParams) (string, error)
+		GetVPNGroupNodeID(ctx context.Context, groupID uuid.UUID) (string, error)
+
+		GetVPNGroupLaboratoryCIDRs(ctx context.Context, groupID uuid.UUID) ([]netip.Prefix, error)
 
 		UpdateVPNClientsBanStatus(ctx context.Context, arg postgres.UpdateVPNClientsBanStatusParams) (int64, error)
+		UpdateVPNClientsLastHandshake(ctx context.Context, arg postgres.UpdateVPNClientsLastHandshakeParams) (int64, error)
+		UpdateVPNClientsLimits(ctx context.Context, arg postgres.UpdateVPNClientsLimitsParams) (int64, error)
+		GetVPNClientsTransfer(ctx context.Context, nodeID string) ([]postgres.GetVPNClientsTransferRow, error)
 
 		DeleteVPNClients(ctx context.Context, arg postgres.DeleteVPNClientsParams) (int64, error)
 
+		Ping(ctx context.Context) error
+
 		GetPlatformSettings(ctx context.Context, key string) ([]byte, error)
 		CreatePlatformSettings(ctx context.Context, arg postgres.CreatePlatformSettingsParams) error
 		//UpdatePlatformSettings(ctx context.Context, arg postgres.UpdatePlatformSettingsParams) (int64, error)
+
+		UpsertVPNNode(ctx context.Context, arg postgres.UpsertVPNNodeParams) error
+		UpdateVPNNodeHeartbeat(ctx context.Context, id string) (int64, error)
+		GetVPNNode(ctx context.Context, id string) (postgres.VpnNode, error)
+		GetAliveVPNNodes(ctx context.Context, heartbeatAt time.Time) ([]postgres.VpnNode, error)
+		GetAliveVPNNodesLoad(ctx context.Context, heartbeatAt time.Time) ([]postgres.GetAliveVPNNodesLoadRow, error)
+
+		CreateAuditLogEntry(ctx context.Context, arg postgres.CreateAuditLogEntryParams) error
+		GetAuditLog(ctx context.Context, arg postgres.GetAuditLogParams) ([]postgres.AuditLog, error)
+
+		CreateVPNDNSRecord(ctx context.Context, arg postgres.CreateVPNDNSRecordParams) (postgres.VpnDnsRecord, error)
+		ListVPNDNSRecords(ctx context.Context, groupID uuid.NullUUID) ([]postgres.VpnDnsRecord, error)
+		DeleteVPNDNSRecord(ctx context.Context, arg postgres.DeleteVPNDNSRecordParams) (postgres.VpnDnsRecord, error)
+
+		CreateVPNSession(ctx context.Context, arg postgres.CreateVPNSessionParams) (int64, error)
+		UpdateVPNSession(ctx context.Context, arg postgres.UpdateVPNSessionParams) error
+		EndVPNNodeSessions(ctx context.Context, nodeID string) (int64, error)
+		GetVPNClientSessions(ctx context.Context, arg postgres.GetVPNClientSessionsParams) ([]postgres.VpnSession, error)
+
+		GetVPNClientsWithoutHandshake(ctx context.Context, arg postgres.GetVPNClientsWithoutHandshakeParams) ([]postgres.GetVPNClientsWithoutHandshakeRow, error)
+		CreateVPNWebhookDelivery(ctx context.Context, arg postgres.CreateVPNWebhookDeliveryParams) error
+		ClaimVPNWebhookDeliveries(ctx context.Context, arg postgres.ClaimVPNWebhookDeliveriesParams) ([]postgres.VpnWebhookDelivery, error)
+		MarkVPNWebhookDelivered(ctx context.Context, arg postgres.MarkVPNWebhookDeliveredParams) error
+		FailVPNWebhookDelivery(ctx context.Context, arg postgres.FailVPNWebhookDeliveryParams) error
+		ReplayVPNWebhookDeliveries(ctx context.Context, arg postgres.ReplayVPNWebhookDeliveriesParams) (int64, error)
+
+		// InTx runs the function with the queries bound to the transaction, the transaction is committed if the function succeeds
+		InTx(ctx context.Context, fn func(q postgres.TxQuerier) error) error
 	}
 
 	IPAManager interface {
 		AcquireSingleIP(ctx context.Context, ip ...string) (string, error)
+		// AcquireIPs acquires the free addresses for the batch, the addresses acquired before the failure are returned with the error
+		AcquireIPs(ctx context.Context, count int) ([]string, error)
 		ReleaseSingleIP(ctx context.Context, ip string) error
 		GetFirstIP() (string, error)
 	}
 
+	// PeerBackend manages the server interface and its peers
+	PeerBackend interface {
+		InterfaceExists() (bool, error)
+		CreateInterface() error
+		AdoptInterface() error
+		DownInterface() error
+		CheckInterface() error
+		CheckListenPort() error
+
+		// GetPeers returns a map of peers of the interface map[publicKey]peer
+		GetPeers() (map[string]model.Peer, error)
+		AddPeer(ip, publicKey string) error
+		AddPeers(peers []model.Peer) error
+		DeletePeer(ip, publicKey string) error
+		DeleteStalePeer(p model.Peer) error
+	}
+
+	// Firewall manages the forward rules of the interface and the NAT and blocking rules of the clients
+	Firewall interface {
+		EnsureForwardRules() error
+		CheckForwardRules() error
+
+		AddNATRule(id, ip, destCidr string) error
+		AddNATRules(rules []model.FirewallRule) error
+		DeleteNATRule(id, ip, destCidr string) error
+		AddBlockRule(id, ip string) error
+		DeleteBlockRule(id, ip string) error
+
+		GetRules() (*model.FirewallRules, error)
+		DeleteNATRuleSpec(r model.FirewallRule) error
+		DeleteBlockRuleSpec(r model.FirewallRule) error
+		// DeleteRules deletes all NAT and blocking rules of the clients at once
+		DeleteRules(ctx context.Context) error
+
+		// DescribeNATRule and DescribeBlockRule return the rule change of the planned action as the firewall applies it
+		DescribeNATRule(action, id, ip, destCidr string) string
+		DescribeBlockRule(action, id, ip string) string
+	}
+
+	// WebhookSender sends the queued client events to the webhook URLs
+	WebhookSender interface {
+		Send(ctx context.Context, delivery model.WebhookDelivery) error
+	}
+
 	Dependencies struct {
-		Repository   Repository
-		IPAManager   IPAManager
-		KeyGenerator *wgKeyGen.KeyGenerator
-		Config       *config.VPNConfig
+		Repository    Repository
+		IPAManager    IPAManager
+		PeerBackend   PeerBackend
+		Firewall      Firewall
+		WebhookSender WebhookSender
+		KeyGenerator  *wgKeyGen.KeyGenerator
+		Config        *config.VPNConfig
 	}
 )
 
 func NewService(deps Dependencies) *Service {
 	return &Service{
 		config:       deps.Config,
-		clients:      make(map[string]*model.Client),
+		clients:      newClientCache(),
+		dnsRecords:   newDNSRecordCache(),
+		sessions:     newSessionSampler(),
+		creations:    make(map[string]*creation),
 		keyGenerator: deps.KeyGenerator,
 		repository:   deps.Repository,
 		ipaManager:   deps.IPAManager,
+		peers:        deps.PeerBackend,
+		firewall:     deps.Firewall,
+		webhooks:     deps.WebhookSender,
 	}
 }
 
@@ -73,48 +180,39 @@ func getClientID(userID, groupID uuid.UUID) string {
 	return fmt.Sprintf("%s-%s", userID, groupID)
 }
 
-func (s *Service) GetClients(_ context.Context, userID, groupID uuid.UUID) ([]*model.Client, error) {
-	log.Debug().Str("userID", userID.String()).Str("groupID", groupID.String()).Msg("Getting clients")
-	clients := s.getFilteredClients(userID, groupID, nil)
-
-	log.Debug().Str("userID", userID.String()).Str("groupID", groupID.String()).Msg("Returning clients")
-	peers, err := s.getPeersLastHandshake()
-	if err != nil {
-		return nil, appError.ErrClient.WithError(err).WithMessage("Failed to get peers last handshake").Err()
-	}
-
-	for _, c := range clients {
-		if lastHandshake, ok := peers[c.PublicKey]; ok {
-			c.LastSeen = -1
-			if lastHandshake > 0 {
-				c.LastSeen = time.Now().Unix() - int64(lastHandshake)
-			}
-		}
-	}
-
-	log.Debug().Str("userID", userID.String()).Str("groupID", groupID.String()).Msg("Returning clients")
-	return clients, nil
-}
-
 func (s *Service) GetClientConfig(ctx context.Context, userID, groupID uuid.UUID, destCIDR string) (string, error) {
-	s.m.RLock()
+	id := getClientID(userID, groupID)
 
-	// check if user exists
+	// check if user exists or is being created by a concurrent request
 	log.Debug().Str("userID", userID.String()).Str("groupID", groupID.String()).Msg("Getting client config from cache")
-	client, ex := s.clients[getClientID(userID, groupID)]
+	client, c, leader := s.claimCreation(id)
 
-	s.m.RUnlock()
-	// if user does not exist create new user
-	if !ex {
+	switch {
+	case client != nil:
+	case leader:
+		// if user does not exist create new user
 		client = &model.Client{
 			UserID:     userID,
 			GroupID:    groupID,
+			NodeID:     s.config.Node.ID,
 			AllowedIPs: destCIDR,
 		}
 		log.Debug().Str("userID", userID.String()).Str("groupID", groupID.String()).Msg("Creating new client")
-		// create user
-		if err := s.createClient(ctx, client); err != nil {
-			return "", appError.ErrClient.WithError(err).WithMessage("Failed to create client").Err()
+		// the waiting requests share the creation, so it is not canceled with the leading request
+		err := s.createClient(context.WithoutCancel(ctx), client)
+		if err != nil {
+			client = nil
+			err = appError.ErrClient.WithError(err).WithMessage("Failed to create client").Err()
+		}
+		s.finishCreation(id, c, client, err)
+		if err != nil {
+			return "", err
+		}
+	default:
+		log.Debug().Str("userID", userID.String()).Str("groupID", groupID.String()).Msg("Waiting for client creation")
+		var err error
+		if client, err = c.wait(ctx); err != nil {
+			return "", err
 		}
 	}
 
@@ -130,40 +228,37 @@ func (s *Service) GetClientConfig(ctx context.Context, userID, groupID uuid.UUID
 }
 
 func (s *Service) getFilteredClients(userID, groupID uuid.UUID, filter func(*model.Client) bool) []*model.Client {
-	s.m.RLock()
-	defer s.m.RUnlock()
-
 	log.Debug().Str("userID", userID.String()).Str("groupID", groupID.String()).Msg("Getting filtered clients")
-	clients := make([]*model.Client, 0, len(s.clients))
-	for id, c := range s.clients {
-		if filter != nil {
-			if !filter(c) {
-				continue
-			}
-		}
-		if !userID.IsNil() && !groupID.IsNil() {
-			if id == getClientID(userID, groupID) {
-				clients = append(clients, c)
-				break
-			}
-		} else if !userID.IsNil() {
-			if c.UserID == userID {
-				clients = append(clients, c)
-			}
-		} else if !groupID.IsNil() {
-			if c.GroupID == groupID {
-				clients = append(clients, c)
-			}
-		} else {
-			clients = append(clients, c)
+	clients := s.clients.list(func(_ string, c *model.Client) bool {
+		if filter != nil && !filter(c) {
+			return false
 		}
-	}
+		return (userID.IsNil() || c.UserID == userID) && (groupID.IsNil() || c.GroupID == groupID)
+	})
 
 	log.Debug().Str("userID", userID.String()).Str("groupID", groupID.String()).Msg("Returning filtered clients")
 	return clients
 }
 
+// operationFilter returns the filter of the clients the operation changes, banning skips banned clients and unbanning skips not banned ones
+func operationFilter(operation string) func(*model.Client) bool {
+	switch operation {
+	case model.ClientsOperationBan:
+		return func(c *model.Client) bool {
+			return !c.Banned
+		}
+	case model.ClientsOperationUnBan:
+		return func(c *model.Client) bool {
+			return c.Banned
+		}
+	}
+	return nil
+}
+
 func (s *Service) DeleteClients(ctx context.Context, userID, groupID uuid.UUID) (int64, error) {
+	s.kernel.RLock()
+	defer s.kernel.RUnlock()
+
 	var errs error
 
 	log.Debug().Str("userID", userID.String()).Str("groupID", groupID.String()).Msg("Get clients for deletion")
@@ -178,14 +273,14 @@ func (s *Service) DeleteClients(ctx context.Context, userID, groupID uuid.UUID)
 	for _, c := range clients {
 		// delete user peer
 		log.Debug().Str("userID", userID.String()).Str("groupID", groupID.String()).Str("address", c.Address).Msg("Deleting client peer")
-		if err := s.deletePeer(c.Address, c.PublicKey); err != nil {
+		if err := s.peers.DeletePeer(c.Address, c.PublicKey); err != nil {
 			errs = multierror.Append(errs, appError.ErrClient.WithError(err).WithMessage("Failed to delete client peer").Err())
 			continue
 		}
 
 		// delete nat rule
 		log.Debug().Str("userID", userID.String()).Str("groupID", groupID.String()).Str("address", c.Address).Msg("Deleting client NAT rule")
-		if err := s.deleteNATRule(getClientID(c.UserID, c.GroupID), c.Address, c.AllowedIPs); err != nil {
+		if err := s.firewall.DeleteNATRule(getClientID(c.UserID, c.GroupID), c.Address, c.AllowedIPs); err != nil {
 			errs = multierror.Append(errs, appError.ErrClient.WithError(err).WithMessage("Failed to delete client NAT rule").Err())
 			continue
 		}
@@ -193,7 +288,7 @@ func (s *Service) DeleteClients(ctx context.Context, userID, groupID uuid.UUID)
 		// delete ban rule if user is banned
 		log.Debug().Str("userID", userID.String()).Str("groupID", groupID.String()).Str("address", c.Address).Msg("Deleting client blocking rule")
 		if c.Banned {
-			if err := s.deleteBlockRule(getClientID(c.UserID, c.GroupID), c.Address); err != nil {
+			if err := s.firewall.DeleteBlockRule(getClientID(c.UserID, c.GroupID), c.Address); err != nil {
 				errs = multierror.Append(errs, appError.ErrClient.WithError(err).WithMessage("Failed to delete client blocking rule").Err())
 				continue
 			}
@@ -215,38 +310,40 @@ func (s *Service) DeleteClients(ctx context.Context, userID, groupID uuid.UUID)
 	}
 
 	log.Debug().Str("userID", userID.String()).Str("groupID", groupID.String()).Msg("Deleting client from db")
-	affected, err := s.repository.DeleteVPNClients(ctx, postgres.DeleteVPNClientsParams{
-		UserID: uuid.NullUUID{
-			UUID:  userID,
-			Valid: !userID.IsNil(),
-		},
-		GroupID: uuid.NullUUID{
-			UUID:  groupID,
-			Valid: !groupID.IsNil(),
-		},
-	})
-	if err != nil {
+	var affected int64
+	if err := s.storeWithEvents(ctx, model.WebhookEventClientDeleted, clientEvents(clients, nil), func(q postgres.TxQuerier) (err error) {
+		affected, err = q.DeleteVPNClients(ctx, postgres.DeleteVPNClientsParams{
+			NodeID: s.config.Node.ID,
+			UserID: uuid.NullUUID{
+				UUID:  userID,
+				Valid: !userID.IsNil(),
+			},
+			GroupID: uuid.NullUUID{
+				UUID:  groupID,
+				Valid: !groupID.IsNil(),
+			},
+		})
+		return err
+	}); err != nil {
 		return 0, appError.ErrClient.WithWrappedError(appError.ErrPostgres.WithError(err)).WithMessage("Failed to delete clients from db").Err()
 	}
 
-	s.m.Lock()
-	defer s.m.Unlock()
 	log.Debug().Str("userID", userID.String()).Str("groupID", groupID.String()).Msg("Deleting clients from cache")
-	for _, c := range clients {
-		delete(s.clients, getClientID(c.UserID, c.GroupID))
-	}
+	s.clients.remove(clients...)
 
 	log.Debug().Str("userID", userID.String()).Str("groupID", groupID.String()).Msg("Returning clients deletion")
 	return affected, nil
 }
 
-func (s *Service) BanClients(ctx context.Context, userID, groupID uuid.UUID) (int64, error) {
+// BanClients blocks the clients and stores the reason of the ban
+func (s *Service) BanClients(ctx context.Context, userID, groupID uuid.UUID, reason string) (int64, error) {
+	s.kernel.RLock()
+	defer s.kernel.RUnlock()
+
 	var errs error
 
 	log.Debug().Str("userID", userID.String()).Str("groupID", groupID.String()).Msg("Get clients for banning")
-	clients := s.getFilteredClients(userID, groupID, func(c *model.Client) bool {
-		return !c.Banned
-	})
+	clients := s.getFilteredClients(userID, groupID, operationFilter(model.ClientsOperationBan))
 
 	if len(clients) == 0 {
 		log.Debug().Str("userID", userID.String()).Str("groupID", groupID.String()).Msg("No clients found for banning")
@@ -257,7 +354,7 @@ func (s *Service) BanClients(ctx context.Context, userID, groupID uuid.UUID) (in
 	for _, c := range clients {
 		// ban user
 		log.Debug().Str("userID", userID.String()).Str("groupID", groupID.String()).Str("address", c.Address).Msg("Adding client blocking rule")
-		if err := s.addBlockRule(getClientID(c.UserID, c.GroupID), c.Address); err != nil {
+		if err := s.firewall.AddBlockRule(getClientID(c.UserID, c.GroupID), c.Address); err != nil {
 			errs = multierror.Append(errs, appError.ErrClient.WithError(err).WithMessage("Failed to add client blocking rule").Err())
 			continue
 		}
@@ -268,28 +365,28 @@ func (s *Service) BanClients(ctx context.Context, userID, groupID uuid.UUID) (in
 	}
 
 	log.Debug().Str("userID", userID.String()).Str("groupID", groupID.String()).Msg("Updating clients ban status in db")
-	affected, err := s.repository.UpdateVPNClientsBanStatus(ctx, postgres.UpdateVPNClientsBanStatusParams{
-		UserID: uuid.NullUUID{
-			UUID:  userID,
-			Valid: !userID.IsNil(),
-		},
-		GroupID: uuid.NullUUID{
-			UUID:  groupID,
-			Valid: !groupID.IsNil(),
-		},
-		Banned: true,
-	})
-
-	if err != nil {
+	var affected int64
+	if err := s.storeWithEvents(ctx, model.WebhookEventClientBanned, clientEvents(clients, map[string]any{"reason": reason}), func(q postgres.TxQuerier) (err error) {
+		affected, err = q.UpdateVPNClientsBanStatus(ctx, postgres.UpdateVPNClientsBanStatusParams{
+			NodeID: s.config.Node.ID,
+			UserID: uuid.NullUUID{
+				UUID:  userID,
+				Valid: !userID.IsNil(),
+			},
+			GroupID: uuid.NullUUID{
+				UUID:  groupID,
+				Valid: !groupID.IsNil(),
+			},
+			Banned:    true,
+			BanReason: reason,
+		})
+		return err
+	}); err != nil {
 		return 0, appError.ErrClient.WithWrappedError(appError.ErrPostgres.WithError(err)).WithMessage("Failed to update clients ban status in db").Err()
 	}
 
-	s.m.Lock()
-	defer s.m.Unlock()
 	log.Debug().Str("userID", userID.String()).Str("groupID", groupID.String()).Msg("Updating clients ban status in cache")
-	for _, c := range clients {
-		s.clients[getClientID(c.UserID, c.GroupID)].Banned = true
-	}
+	s.clients.setBanned(true, clients...)
 
 	log.Debug().Str("userID", userID.String()).Str("groupID", groupID.String()).Msg("Returning clients banning")
 	return affected, nil
@@ -297,12 +394,13 @@ func (s *Service) BanClients(ctx context.Context, userID, groupID uuid.UUID) (in
 }
 
 func (s *Service) UnBanClients(ctx context.Context, userID, groupID uuid.UUID) (int64, error) {
+	s.kernel.RLock()
+	defer s.kernel.RUnlock()
+
 	var errs error
 
 	log.Debug().Str("userID", userID.String()).Str("groupID", groupID.String()).Msg("Get clients for unbanning")
-	clients := s.getFilteredClients(userID, groupID, func(c *model.Client) bool {
-		return c.Banned
-	})
+	clients := s.getFilteredClients(userID, groupID, operationFilter(model.ClientsOperationUnBan))
 
 	if len(clients) == 0 {
 		log.Debug().Str("userID", userID.String()).Str("groupID", groupID.String()).Msg("No clients found for unbanning")
@@ -313,7 +411,7 @@ func (s *Service) UnBanClients(ctx context.Context, userID, groupID uuid.UUID) (
 	for _, c := range clients {
 		// ban user
 		log.Debug().Str("userID", userID.String()).Str("groupID", groupID.String()).Str("address", c.Address).Msg("Deleting client blocking rule")
-		if err := s.deleteBlockRule(getClientID(c.UserID, c.GroupID), c.Address); err != nil {
+		if err := s.firewall.DeleteBlockRule(getClientID(c.UserID, c.GroupID), c.Address); err != nil {
 			errs = multierror.Append(errs, appError.ErrClient.WithError(err).WithMessage("Failed to delete client blocking rule").Err())
 			continue
 		}
@@ -325,28 +423,27 @@ func (s *Service) UnBanClients(ctx context.Context, userID, groupID uuid.UUID) (
 	}
 
 	log.Debug().Str("userID", userID.String()).Str("groupID", groupID.String()).Msg("Updating clients ban status in db")
-	affected, err := s.repository.UpdateVPNClientsBanStatus(ctx, postgres.UpdateVPNClientsBanStatusParams{
-		UserID: uuid.NullUUID{
-			UUID:  userID,
-			Valid: !userID.IsNil(),
-		},
-		GroupID: uuid.NullUUID{
-			UUID:  groupID,
-			Valid: !groupID.IsNil(),
-		},
-		Banned: false,
-	})
-
-	if err != nil {
+	var affected int64
+	if err := s.storeWithEvents(ctx, model.WebhookEventClientUnBanned, clientEvents(clients, nil), func(q postgres.TxQuerier) (err error) {
+		affected, err = q.UpdateVPNClientsBanStatus(ctx, postgres.UpdateVPNClientsBanStatusParams{
+			NodeID: s.config.Node.ID,
+			UserID: uuid.NullUUID{
+				UUID:  userID,
+				Valid: !userID.IsNil(),
+			},
+			GroupID: uuid.NullUUID{
+				UUID:  groupID,
+				Valid: !groupID.IsNil(),
+			},
+			Banned: false,
+		})
+		return err
+	}); err != nil {
 		return 0, appError.ErrClient.WithWrappedError(appError.ErrPostgres.WithError(err)).WithMessage("Failed to update clients ban status in db").Err()
 	}
 
-	s.m.Lock()
-	defer s.m.Unlock()
 	log.Debug().Str("userID", userID.String()).Str("groupID", groupID.String()).Msg("Updating clients ban status in cache")
-	for _, c := range clients {
-		s.clients[getClientID(c.UserID, c.GroupID)].Banned = false
-	}
+	s.clients.setBanned(false, clients...)
 
 	log.Debug().Str("userID", userID.String()).Str("groupID", groupID.String()).Msg("Returning clients unbanning")
 	return affected, nil
@@ -354,6 +451,9 @@ func (s *Service) UnBanClients(ctx context.Context, userID, groupID uuid.UUID) (
 }
 
 func (s *Service) createClient(ctx context.Context, client *model.Client) (err error) {
+	s.kernel.RLock()
+	defer s.kernel.RUnlock()
+
 	// generate client address
 	log.Debug().Str("userID", client.UserID.String()).Str("groupID", client.GroupID.String()).Msg("Creating new client")
 	log.Debug().Str("userID", client.UserID.String()).Str("groupID", client.GroupID.String()).Msg("Acquiring client ip")
@@ -376,7 +476,7 @@ func (s *Service) createClient(ctx context.Context, client *model.Client) (err e
 
 	// add client peer
 	log.Debug().Str("userID", client.UserID.String()).Str("groupID", client.GroupID.String()).Msg("Adding client peer")
-	if err = s.addPeer(client.Address, client.PublicKey); err != nil {
+	if err = s.peers.AddPeer(client.Address, client.PublicKey); err != nil {
 		return appError.ErrClient.WithError(err).WithMessage("Failed to add client peer").Err()
 	}
 
@@ -392,19 +492,22 @@ func (s *Service) createClient(ctx context.Context, client *model.Client) (err e
 
 	// add nat rule
 	log.Debug().Str("userID", client.UserID.String()).Str("groupID", client.GroupID.String()).Msg("Adding client NAT rule")
-	if err = s.addNATRule(getClientID(client.UserID, client.GroupID), client.Address, client.AllowedIPs); err != nil {
+	if err = s.firewall.AddNATRule(getClientID(client.UserID, client.GroupID), client.Address, client.AllowedIPs); err != nil {
 		return appError.ErrClient.WithError(err).WithMessage("Failed to add client NAT rule").Err()
 	}
 
 	// add client to db
 	log.Debug().Str("userID", client.UserID.String()).Str("groupID", client.GroupID.String()).Msg("Creating client in db")
-	if err = s.repository.CreateVpnClient(ctx, postgres.CreateVpnClientParams{
-		UserID:         client.UserID,
-		GroupID:        client.GroupID,
-		IpAddress:      ip,
-		PublicKey:      client.PublicKey,
-		PrivateKey:     client.PrivateKey,
-		LaboratoryCidr: allowedIPs,
+	if err = s.storeWithEvents(ctx, model.WebhookEventClientCreated, createdEvents(client), func(q postgres.TxQuerier) error {
+		return q.CreateVpnClient(ctx, postgres.CreateVpnClientParams{
+			UserID:         client.UserID,
+			GroupID:        client.GroupID,
+			IpAddress:      ip,
+			PublicKey:      client.PublicKey,
+			PrivateKey:     client.PrivateKey,
+			LaboratoryCidr: allowedIPs,
+			NodeID:         client.NodeID,
+		})
 	}); err != nil {
 		return appError.ErrClient.WithWrappedError(appError.ErrPostgres.WithError(err)).WithMessage("Failed to create client in db").Err()
 	}
@@ -416,11 +519,8 @@ func (s *Service) createClient(ctx context.Context, client *model.Client) (err e
 		return appError.ErrClient.WithError(err).WithMessage("Failed to generate client DNS ip").Err()
 	}
 
-	s.m.Lock()
-	defer s.m.Unlock()
-
 	log.Debug().Str("userID", client.UserID.String()).Str("groupID", client.GroupID.String()).Msg("Adding client to cache")
-	s.clients[getClientID(client.UserID, client.GroupID)] = client
+	s.clients.put(client)
 
 	log.Debug().Str("userID", client.UserID.String()).Str("groupID", client.GroupID.String()).Msg("Client created")
 	return nil
@@ -443,7 +543,7 @@ func (s *Service) InitServer(ctx context.Context) error {
 
 	// get server private key
 	log.Debug().Msg("Getting server key pair from db")
-	keyPairData, err := s.repository.GetPlatformSettings(ctx, config.VPNKeyPair)
+	keyPairData, err := s.repository.GetPlatformSettings(ctx, s.keyPairSettingsKey())
 	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
 		return appError.ErrPlatform.WithWrappedError(appError.ErrPostgres.WithError(err)).WithMessage("Failed to get server key pair from db").Err()
 	}
@@ -471,7 +571,7 @@ func (s *Service) InitServer(ctx context.Context) error {
 		}
 
 		if err = s.repository.CreatePlatformSettings(ctx, postgres.CreatePlatformSettingsParams{
-			Key:   config.VPNKeyPair,
+			Key:   s.keyPairSettingsKey(),
 			Value: keyPairData,
 		}); err != nil {
 			return appError.ErrPlatform.WithWrappedError(appError.ErrPostgres.WithError(err)).WithMessage("Failed to save server key pair to db").Err()
@@ -479,39 +579,57 @@ func (s *Service) InitServer(ctx context.Context) error {
 
 	}
 
-	log.Debug().Msg("Create server")
-	if err = s.createServer(); err != nil {
-		return appError.ErrPlatform.WithError(err).WithMessage("Failed to create server").Err()
+	exists, err := s.peers.InterfaceExists()
+	if err != nil {
+		return appError.ErrPlatform.WithError(err).WithMessage("Failed to check server interface").Err()
+	}
+
+	// adopt the interface left by the previous run instead of failing on wg-quick up
+	if exists {
+		log.Info().Str("interface", config.VPNInterface).Msg("Interface already exists, adopting it")
+		if err = s.peers.AdoptInterface(); err != nil {
+			return appError.ErrPlatform.WithError(err).WithMessage("Failed to adopt server").Err()
+		}
+	} else {
+		log.Debug().Msg("Create server")
+		if err = s.peers.CreateInterface(); err != nil {
+			return appError.ErrPlatform.WithError(err).WithMessage("Failed to create server").Err()
+		}
+	}
+
+	if err = s.firewall.EnsureForwardRules(); err != nil {
+		return appError.ErrPlatform.WithError(err).WithMessage("Failed to ensure forward rules").Err()
 	}
 
 	log.Debug().Str("Address: ", s.config.Address).
-		Str("ListenPort: ", s.config.Port).Msgf("Interface %s created and it is up", nic)
+		Str("ListenPort: ", s.config.Port).Msgf("Interface %s created and it is up", config.VPNInterface)
 
 	return nil
 }
 
 func (s *Service) InitServerClients(ctx context.Context) (errs error) {
-	s.m.Lock()
-	defer s.m.Unlock()
-
 	// get all users from db
 	log.Debug().Msg("Getting clients from db")
-	clients, err := s.repository.GetVPNClients(ctx)
+	clients, err := s.repository.GetNodeVPNClients(ctx, s.config.Node.ID)
 	if err != nil {
 		return appError.ErrPlatform.WithError(appError.ErrPostgres.WithError(err).Err()).WithMessage("Failed to get clients from db").Err()
 	}
 	// create users
+	desired := make([]*model.Client, 0, len(clients))
 	for _, c := range clients {
 		client := &model.Client{
-			UserID:     c.UserID,
-			GroupID:    c.GroupID,
-			Address:    c.IpAddress.String(),
-			DNS:        "",
-			PrivateKey: c.PrivateKey,
-			PublicKey:  c.PublicKey,
-			AllowedIPs: c.LaboratoryCidr.String(),
-			Endpoint:   "",
-			Banned:     c.Banned,
+			UserID:        c.UserID,
+			GroupID:       c.GroupID,
+			NodeID:        c.NodeID,
+			Address:       c.IpAddress.String(),
+			DNS:           "",
+			PrivateKey:    c.PrivateKey,
+			PublicKey:     c.PublicKey,
+			AllowedIPs:    c.LaboratoryCidr.String(),
+			Banned:        c.Banned,
+			CreatedAt:     c.CreatedAt,
+			ExpiresAt:     c.ExpiresAt.Time,
+			TransferQuota: c.TransferQuota,
 		}
 		// generate user DNS address
 		log.Debug().Str("userID", client.UserID.String()).Str("groupID", client.GroupID.String()).Msg("Generating client DNS ip")
@@ -520,32 +638,20 @@ func (s *Service) InitServerClients(ctx context.Context) (errs error) {
 			errs = multierror.Append(errs, appError.ErrPlatform.WithError(err).WithMessage("Failed to generate client DNS ip").Err())
 			continue
 		}
-		log.Debug().Str("userID", client.UserID.String()).Str("groupID", client.GroupID.String()).Msg("Adding client peer")
-		if err = s.addPeer(client.Address, client.PublicKey); err != nil {
-			errs = multierror.Append(errs, appError.ErrPlatform.WithError(err).WithMessage("Failed to add client peer").Err())
-			continue
-		}
 
-		// add nat rule
-		log.Debug().Str("userID", client.UserID.String()).Str("groupID", client.GroupID.String()).Msg("Adding client NAT rule")
-		if err = s.addNATRule(getClientID(client.UserID, client.GroupID), client.Address, client.AllowedIPs); err != nil {
-			errs = multierror.Append(errs, appError.ErrPlatform.WithError(err).WithMessage("Failed to add client NAT rule").Err())
-			continue
-		}
-
-		// if user is banned add block rule
-		log.Debug().Str("userID", client.UserID.String()).Str("groupID", client.GroupID.String()).Bool("banned", client.Banned).Msg("Adding client blocking rule if user is banned")
-		if client.Banned {
-			if err = s.addBlockRule(getClientID(client.UserID, client.GroupID), client.Address); err != nil {
-				errs = multierror.Append(errs, appError.ErrPlatform.WithError(err).WithMessage("Failed to add client blocking rule").Err())
-				continue
-			}
-		}
+		desired = append(desired, client)
+	}
 
-		log.Debug().Str("userID", client.UserID.String()).Str("groupID", client.GroupID.String()).Msg("Adding client to cache")
-		s.clients[getClientID(client.UserID, client.GroupID)] = client
+	// converge existing peers and rules to the clients
+	converged, err := s.converge(desired)
+	if err != nil {
+		errs = multierror.Append(errs, appError.ErrPlatform.WithError(err).WithMessage("Failed to converge clients").Err())
 	}
 
+	log.Debug().Int("clients", len(converged)).Msg("Adding clients to cache")
+	s.clients.put(converged...)
+	s.setReconcileResult(err)
+
 	if errs != nil {
 		return appError.ErrPlatform.WithError(errs).WithMessage("Failed to create clients").Err()
 	}
@@ -553,3 +659,31 @@ func (s *Service) InitServerClients(ctx context.Context) (errs error) {
 	log.Debug().Msg("Clients created")
 	return nil
 }
+
+// Shutdown removes all service-owned rules and brings the interface down.
+// If peers are kept on shutdown, the kernel state is left untouched for the next start.
+func (s *Service) Shutdown(ctx context.Context) (errs error) {
+	if s.config.KeepPeersOnShutdown {
+		log.Info().Msg("Keeping interface, peers and rules on shutdown")
+		return nil
+	}
+
+	// the rules are deleted at once, so the teardown does not grow with the number of clients
+	log.Debug().Msg("Deleting clients rules")
+	if err := s.firewall.DeleteRules(ctx); err != nil {
+		errs = multierror.Append(errs, appError.ErrPlatform.WithError(err).WithMessage("Failed to delete clients rules").Err())
+	}
+
+	// peers and routes are removed with the interface, PostDown rules are run by wg-quick
+	log.Debug().Msg("Bringing interface down")
+	if err := s.peers.DownInterface(); err != nil {
+		errs = multierror.Append(errs, appError.ErrPlatform.WithError(err).WithMessage("Failed to down interface").Err())
+	}
+
+	if errs != nil {
+		return appError.ErrPlatform.WithError(errs).WithMessage("Failed to shutdown server").Err()
+	}
+
+	log.Debug().Msg("Server is shut down")
+	return nil
+}
@@ -0,0 +1,310 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"github.com/cybericebox/wireguard/internal/delivery/repository/postgres"
+	"github.com/cybericebox/wireguard/internal/model"
+	"github.com/cybericebox/wireguard/pkg/appError"
+	"github.com/gofrs/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/rs/zerolog/log"
+	"sync"
+	"time"
+)
+
+const (
+	// defaultSessionsLimit is the number of sessions returned when the limit is not set
+	defaultSessionsLimit = 50
+	// maxSessionsLimit is the maximum number of sessions returned at once
+	maxSessionsLimit = 500
+)
+
+type (
+	// sessionSampler is the state of the connection sessions of the node peers between the samples
+	sessionSampler struct {
+		m sync.Mutex
+		// open are the recorded sessions of the online peers by public key
+		open map[string]*openSession
+		// counters are the transfer counters of the peers at the previous sample by public key
+		counters map[string]peerCounters
+	}
+
+	openSession struct {
+		id           int64
+		userID       uuid.UUID
+		groupID      uuid.UUID
+		endpoint     string
+		transferRx   int64
+		transferTx   int64
+		startedAt    time.Time
+		lastActiveAt time.Time
+	}
+
+	peerCounters struct {
+		rx int64
+		tx int64
+	}
+)
+
+func newSessionSampler() *sessionSampler {
+	return &sessionSampler{
+		open:     make(map[string]*openSession),
+		counters: make(map[string]peerCounters),
+	}
+}
+
+// transferDelta returns the transfer of the peer since the previous sample.
+// The peer seen for the first time has no delta, as its counters may include the traffic before the node started,
+// and the counters lower than the previous ones are reset, so they are the whole delta.
+func (ss *sessionSampler) transferDelta(p model.Peer) (rx, tx int64) {
+	prev, seen := ss.counters[p.PublicKey]
+	ss.counters[p.PublicKey] = peerCounters{rx: p.TransferRx, tx: p.TransferTx}
+
+	if !seen {
+		return 0, 0
+	}
+
+	if rx = p.TransferRx - prev.rx; rx < 0 {
+		rx = p.TransferRx
+	}
+	if tx = p.TransferTx - prev.tx; tx < 0 {
+		tx = p.TransferTx
+	}
+	return rx, tx
+}
+
+// peerOnline reports whether the peer has handshaked within the session timeout
+func (s *Service) peerOnline(p model.Peer, now time.Time) bool {
+	return p.LatestHandshake > 0 && now.Sub(time.Unix(int64(p.LatestHandshake), 0)) <= s.config.SessionTimeout
+}
+
+// SampleSessions records the connection sessions of the node peers.
+// The session starts with the handshake of the offline peer, its transfer and endpoint are updated every sample
+// and it ends at its last activity when the peer has not handshaked within the session timeout or is deleted.
+func (s *Service) SampleSessions(ctx context.Context) error {
+	s.sessions.m.Lock()
+	defer s.sessions.m.Unlock()
+
+	peers, err := s.peers.GetPeers()
+	if err != nil {
+		return appError.ErrClient.WithError(err).WithMessage("Failed to get peers").Err()
+	}
+
+	clients := make(map[string]*model.Client)
+	for _, c := range s.clients.list(nil) {
+		clients[c.PublicKey] = c
+	}
+
+	now := time.Now()
+	var errs []error
+
+	for key, p := range peers {
+		client, ok := clients[key]
+		if !ok {
+			// the stale peer is removed by the reconciliation
+			continue
+		}
+
+		rx, tx := s.sessions.transferDelta(p)
+		session, open := s.sessions.open[key]
+		online := s.peerOnline(p, now)
+
+		switch {
+		case online && !open:
+			session = &openSession{
+				userID:       client.UserID,
+				groupID:      client.GroupID,
+				endpoint:     peerEndpoint(p),
+				transferRx:   rx,
+				transferTx:   tx,
+				startedAt:    time.Unix(int64(p.LatestHandshake), 0),
+				lastActiveAt: now,
+			}
+
+			log.Debug().Str("userID", client.UserID.String()).Str("groupID", client.GroupID.String()).Msg("Creating VPN session in db")
+			if session.id, err = s.repository.CreateVPNSession(ctx, postgres.CreateVPNSessionParams{
+				UserID:       session.userID,
+				GroupID:      session.groupID,
+				NodeID:       s.config.Node.ID,
+				Endpoint:     session.endpoint,
+				TransferRx:   session.transferRx,
+				TransferTx:   session.transferTx,
+				StartedAt:    session.startedAt,
+				LastActiveAt: session.lastActiveAt,
+			}); err != nil {
+				// the session is created with the next sample
+				errs = append(errs, appError.ErrClient.WithWrappedError(appError.ErrPostgres.WithError(err)).WithMessage("Failed to create VPN session").WithContext("userID", client.UserID.String()).Err())
+				continue
+			}
+			s.sessions.open[key] = session
+		case open:
+			session.transferRx += rx
+			session.transferTx += tx
+			// only the received traffic is the activity of the client,
+			// the server keeps sending the keepalives and the retransmissions after the client is gone
+			if rx > 0 {
+				session.lastActiveAt = now
+			}
+			if endpoint := peerEndpoint(p); endpoint != "" {
+				session.endpoint = endpoint
+			}
+
+			// the client going offline ends the session
+			var offline []webhookEvent
+			if !online {
+				offline = append(offline, webhookEvent{client: client, data: map[string]any{
+					"endpoint":     session.endpoint,
+					"transferRx":   session.transferRx,
+					"transferTx":   session.transferTx,
+					"startedAt":    session.startedAt.UTC(),
+					"lastActiveAt": session.lastActiveAt.UTC(),
+				}})
+			}
+
+			if err = s.updateSession(ctx, key, session, !online, offline...); err != nil {
+				errs = append(errs, err)
+				continue
+			}
+		}
+	}
+
+	// the sessions of the deleted peers end with the peers
+	for key, session := range s.sessions.open {
+		if _, ok := peers[key]; ok {
+			if _, ok = clients[key]; ok {
+				continue
+			}
+		}
+
+		if err = s.updateSession(ctx, key, session, true); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	for key := range s.sessions.counters {
+		if _, ok := peers[key]; !ok {
+			delete(s.sessions.counters, key)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// updateSession stores the sampled session with the offline event of the client, the ended session is closed at its last activity
+func (s *Service) updateSession(ctx context.Context, key string, session *openSession, end bool, offline ...webhookEvent) error {
+	var endedAt pgtype.Timestamptz
+	if end {
+		endedAt = pgtype.Timestamptz{Time: session.lastActiveAt, Valid: true}
+	}
+
+	if err := s.storeWithEvents(ctx, model.WebhookEventClientOffline, offline, func(q postgres.TxQuerier) error {
+		return q.UpdateVPNSession(ctx, postgres.UpdateVPNSessionParams{
+			ID:           session.id,
+			Endpoint:     session.endpoint,
+			TransferRx:   session.transferRx,
+			TransferTx:   session.transferTx,
+			LastActiveAt: session.lastActiveAt,
+			EndedAt:      endedAt,
+		})
+	}); err != nil {
+		return appError.ErrClient.WithWrappedError(appError.ErrPostgres.WithError(err)).WithMessage("Failed to update VPN session").WithContext("userID", session.userID.String()).Err()
+	}
+
+	if end {
+		log.Debug().Str("userID", session.userID.String()).Str("groupID", session.groupID.String()).Msg("VPN session ended")
+		delete(s.sessions.open, key)
+	}
+
+	return nil
+}
+
+// peerEndpoint returns the endpoint of the peer, it is empty before the first handshake
+func peerEndpoint(p model.Peer) string {
+	if p.Endpoint == noPeerEndpoint {
+		return ""
+	}
+	return p.Endpoint
+}
+
+// RunSessionSampler ends the sessions left open by the previous run of the node and samples the peers until the context is done
+func (s *Service) RunSessionSampler(ctx context.Context) {
+	ended, err := s.repository.EndVPNNodeSessions(ctx, s.config.Node.ID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to end VPN sessions of the previous run")
+	} else {
+		log.Debug().Int64("sessions", ended).Msg("VPN sessions of the previous run ended")
+	}
+
+	ticker := time.NewTicker(s.config.SessionSampleInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Debug().Msg("VPN session sampler stopped")
+			return
+		case <-ticker.C:
+			if err = s.SampleSessions(ctx); err != nil {
+				log.Error().Err(err).Msg("Failed to sample VPN sessions")
+			}
+			// the sampled sessions are the transfer of the clients
+			if err = s.EnforceClientLimits(ctx); err != nil {
+				log.Error().Err(err).Msg("Failed to enforce client limits")
+			}
+		}
+	}
+}
+
+// GetClientSessions returns the connection sessions of the client started within the filter, the newest first
+func (s *Service) GetClientSessions(ctx context.Context, filter model.SessionsFilter) ([]*model.Session, error) {
+	if filter.UserID.IsNil() {
+		return nil, appError.ErrClientInvalidUserID.WithMessage("User ID is required").Err()
+	}
+
+	if filter.GroupID.IsNil() {
+		return nil, appError.ErrClientInvalidGroupID.WithMessage("Group ID is required").Err()
+	}
+
+	if filter.Until.IsZero() {
+		filter.Until = time.Now()
+	}
+
+	if filter.Limit <= 0 {
+		filter.Limit = defaultSessionsLimit
+	}
+
+	if filter.Limit > maxSessionsLimit {
+		filter.Limit = maxSessionsLimit
+	}
+
+	log.Debug().Str("userID", filter.UserID.String()).Str("groupID", filter.GroupID.String()).Msg("Getting client sessions from db")
+	rows, err := s.repository.GetVPNClientSessions(ctx, postgres.GetVPNClientSessionsParams{
+		UserID:   filter.UserID,
+		GroupID:  filter.GroupID,
+		Since:    filter.Since,
+		Until:    filter.Until,
+		RowLimit: filter.Limit,
+	})
+	if err != nil {
+		return nil, appError.ErrClient.WithWrappedError(appError.ErrPostgres.WithError(err)).WithMessage("Failed to get client sessions").Err()
+	}
+
+	sessions := make([]*model.Session, 0, len(rows))
+	for _, row := range rows {
+		sessions = append(sessions, &model.Session{
+			ID:           row.ID,
+			UserID:       row.UserID,
+			GroupID:      row.GroupID,
+			NodeID:       row.NodeID,
+			Endpoint:     row.Endpoint,
+			TransferRx:   row.TransferRx,
+			TransferTx:   row.TransferTx,
+			StartedAt:    row.StartedAt,
+			LastActiveAt: row.LastActiveAt,
+			EndedAt:      row.EndedAt.Time,
+		})
+	}
+
+	return sessions, nil
+}
@@ -0,0 +1,93 @@
+package service
+
+import (
+	"context"
+	"github.com/cybericebox/wireguard/internal/config"
+	"github.com/cybericebox/wireguard/internal/delivery/repository/postgres"
+	"github.com/cybericebox/wireguard/internal/model"
+	"testing"
+	"time"
+)
+
+type (
+	// samplePeers is the interface of the peers set by the test, other methods of the backend are not used by the sampler
+	samplePeers struct {
+		PeerBackend
+		peers map[string]model.Peer
+	}
+
+	// sessionRepository records the sessions of the sampler, other methods of the repository are not used by it
+	sessionRepository struct {
+		Repository
+		updates []postgres.UpdateVPNSessionParams
+	}
+)
+
+func (p *samplePeers) GetPeers() (map[string]model.Peer, error) {
+	return p.peers, nil
+}
+
+func (r *sessionRepository) CreateVPNSession(context.Context, postgres.CreateVPNSessionParams) (int64, error) {
+	return 1, nil
+}
+
+func (r *sessionRepository) UpdateVPNSession(_ context.Context, arg postgres.UpdateVPNSessionParams) error {
+	r.updates = append(r.updates, arg)
+	return nil
+}
+
+func TestSampleSessionsCountsOnlyReceivedTrafficAsActivity(t *testing.T) {
+	client := testClient()
+	peers := &samplePeers{}
+	repository := &sessionRepository{}
+	s := &Service{
+		config:     &config.VPNConfig{SessionTimeout: time.Minute},
+		clients:    newClientCache(),
+		sessions:   newSessionSampler(),
+		peers:      peers,
+		repository: repository,
+	}
+	s.clients.put(client)
+
+	ctx := context.Background()
+	sample := func(rx, tx int64) postgres.UpdateVPNSessionParams {
+		t.Helper()
+
+		peers.peers = map[string]model.Peer{client.PublicKey: {
+			PublicKey:       client.PublicKey,
+			Endpoint:        "203.0.113.1:51820",
+			AllowedIPs:      client.Address,
+			LatestHandshake: int(time.Now().Unix()),
+			TransferRx:      rx,
+			TransferTx:      tx,
+		}}
+		if err := s.SampleSessions(ctx); err != nil {
+			t.Fatalf("sample sessions: %v", err)
+		}
+		// the first sample opens the session, it is not updated
+		if len(repository.updates) == 0 {
+			return postgres.UpdateVPNSessionParams{}
+		}
+		return repository.updates[len(repository.updates)-1]
+	}
+
+	sample(100, 100)
+	time.Sleep(time.Millisecond)
+	active := sample(200, 200)
+
+	// the keepalives of the server are sent after the client is gone
+	time.Sleep(time.Millisecond)
+	idle := sample(200, 300)
+
+	if idle.TransferTx != 200 || idle.TransferRx != 100 {
+		t.Fatalf("session transfer = rx %d, tx %d, want rx 100, tx 200", idle.TransferRx, idle.TransferTx)
+	}
+	if !idle.LastActiveAt.Equal(active.LastActiveAt) {
+		t.Fatalf("sent traffic moved the last activity from %s to %s", active.LastActiveAt, idle.LastActiveAt)
+	}
+
+	time.Sleep(time.Millisecond)
+	if received := sample(300, 300); !received.LastActiveAt.After(active.LastActiveAt) {
+		t.Fatal("received traffic did not move the last activity")
+	}
+}
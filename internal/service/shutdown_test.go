@@ -0,0 +1,62 @@
+package service
+
+import (
+	"context"
+	"github.com/cybericebox/wireguard/internal/config"
+	"testing"
+)
+
+type (
+	// teardownFirewall records the deletion of the rules, other methods of the firewall are not used by the shutdown
+	teardownFirewall struct {
+		Firewall
+		deletions int
+	}
+
+	// teardownPeers records the interface going down, other methods of the backend are not used by the shutdown
+	teardownPeers struct {
+		PeerBackend
+		down bool
+	}
+)
+
+func (f *teardownFirewall) DeleteRules(ctx context.Context) error {
+	f.deletions++
+	return ctx.Err()
+}
+
+func (p *teardownPeers) DownInterface() error {
+	p.down = true
+	return nil
+}
+
+func TestShutdownDeletesRulesAtOnce(t *testing.T) {
+	firewall, peers := &teardownFirewall{}, &teardownPeers{}
+	s := &Service{
+		config:   &config.VPNConfig{},
+		clients:  newClientCache(),
+		firewall: firewall,
+		peers:    peers,
+	}
+	for range 3 {
+		s.clients.put(testClient())
+	}
+
+	if err := s.Shutdown(context.Background()); err != nil {
+		t.Fatalf("shutdown: %v", err)
+	}
+	if firewall.deletions != 1 || !peers.down {
+		t.Fatalf("rules deleted %d times, interface down %t, want the rules deleted once and the interface down", firewall.deletions, peers.down)
+	}
+
+	// the expired teardown fails, the interface is still brought down
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	peers.down = false
+	if err := s.Shutdown(ctx); err == nil {
+		t.Fatal("shutdown with the expired context succeeded")
+	}
+	if !peers.down {
+		t.Fatal("interface is not brought down after the rules failed")
+	}
+}
@@ -0,0 +1,250 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/cybericebox/wireguard/internal/delivery/repository/postgres"
+	"github.com/cybericebox/wireguard/internal/model"
+	"github.com/cybericebox/wireguard/pkg/appError"
+	"github.com/gofrs/uuid"
+	"github.com/hashicorp/go-multierror"
+	"github.com/rs/zerolog/log"
+	"slices"
+	"sync"
+	"time"
+)
+
+type (
+	// webhookPayload is the body of the webhook request of the client event
+	webhookPayload struct {
+		ID        uuid.UUID      `json:"id"`
+		Type      string         `json:"type"`
+		NodeID    string         `json:"nodeID"`
+		CreatedAt time.Time      `json:"createdAt"`
+		UserID    uuid.UUID      `json:"userID"`
+		GroupID   uuid.UUID      `json:"groupID"`
+		Data      map[string]any `json:"data,omitempty"`
+	}
+
+	// webhookEvent is the event of the client queued with the change of the client
+	webhookEvent struct {
+		client *model.Client
+		data   map[string]any
+	}
+)
+
+// webhookEnabled reports whether the events of the type are delivered
+func (s *Service) webhookEnabled(eventType string) bool {
+	cfg := s.config.Webhooks
+	return len(cfg.URLs) > 0 && (len(cfg.Events) == 0 || slices.Contains(cfg.Events, eventType))
+}
+
+// storeWithEvents stores the change and queues the webhook events of the type in the same transaction,
+// so the events are queued if and only if the change is stored.
+// The change is stored without the transaction if the events of the type are not delivered.
+func (s *Service) storeWithEvents(ctx context.Context, eventType string, events []webhookEvent, store func(q postgres.TxQuerier) error) error {
+	if len(events) == 0 || !s.webhookEnabled(eventType) {
+		return store(s.repository)
+	}
+
+	return s.repository.InTx(ctx, func(q postgres.TxQuerier) error {
+		if err := store(q); err != nil {
+			return err
+		}
+		return s.queueEvents(ctx, q, eventType, events)
+	})
+}
+
+// queueEvents stores the deliveries of the events to every webhook URL with the queries of the change transaction
+func (s *Service) queueEvents(ctx context.Context, q postgres.TxQuerier, eventType string, events []webhookEvent) error {
+	for _, event := range events {
+		id, err := uuid.NewV4()
+		if err != nil {
+			return appError.ErrWebhook.WithError(err).WithMessage("Failed to generate webhook event ID").WithContext("event", eventType).Err()
+		}
+
+		payload, err := json.Marshal(webhookPayload{
+			ID:        id,
+			Type:      eventType,
+			NodeID:    s.config.Node.ID,
+			CreatedAt: time.Now().UTC(),
+			UserID:    event.client.UserID,
+			GroupID:   event.client.GroupID,
+			Data:      event.data,
+		})
+		if err != nil {
+			return appError.ErrWebhook.WithError(err).WithMessage("Failed to marshal webhook event").WithContext("event", eventType).Err()
+		}
+
+		for _, url := range s.config.Webhooks.URLs {
+			log.Debug().Str("event", eventType).Str("userID", event.client.UserID.String()).Str("groupID", event.client.GroupID.String()).Msg("Queueing webhook event")
+			if err = q.CreateVPNWebhookDelivery(ctx, postgres.CreateVPNWebhookDeliveryParams{
+				EventID:   id,
+				EventType: eventType,
+				Url:       url,
+				Payload:   payload,
+			}); err != nil {
+				return appError.ErrWebhook.WithWrappedError(appError.ErrPostgres.WithError(err)).WithMessage("Failed to queue webhook event").WithContext("event", eventType).Err()
+			}
+		}
+	}
+
+	return nil
+}
+
+// clientEvents returns the events of the clients with the same data
+func clientEvents(clients []*model.Client, data map[string]any) []webhookEvent {
+	events := make([]webhookEvent, 0, len(clients))
+	for _, c := range clients {
+		events = append(events, webhookEvent{client: c, data: data})
+	}
+	return events
+}
+
+// createdEvents returns the client created events of the clients
+func createdEvents(clients ...*model.Client) []webhookEvent {
+	events := make([]webhookEvent, 0, len(clients))
+	for _, c := range clients {
+		events = append(events, webhookEvent{client: c, data: createdEventData(c)})
+	}
+	return events
+}
+
+// createdEventData is the data of the client created event
+func createdEventData(client *model.Client) map[string]any {
+	return map[string]any{
+		"address":    client.Address,
+		"allowedIPs": client.AllowedIPs,
+	}
+}
+
+// webhookBackoff returns the delay of the retry after the attempts, it doubles with every attempt up to the maximum
+func (s *Service) webhookBackoff(attempts int32) time.Duration {
+	backoff := s.config.Webhooks.RetryBackoff
+	for i := int32(1); i < attempts && backoff < s.config.Webhooks.MaxBackoff; i++ {
+		backoff *= 2
+	}
+	return min(backoff, s.config.Webhooks.MaxBackoff)
+}
+
+// DeliverWebhooks sends the due events of the queue concurrently.
+// The claimed events are leased, so the other nodes do not send them until the requests time out,
+// and the failed ones are retried with the backoff until the attempts run out.
+func (s *Service) DeliverWebhooks(ctx context.Context) error {
+	now := time.Now()
+	rows, err := s.repository.ClaimVPNWebhookDeliveries(ctx, postgres.ClaimVPNWebhookDeliveriesParams{
+		LeaseUntil:  now.Add(2 * s.config.Webhooks.Timeout),
+		MaxAttempts: s.config.Webhooks.MaxAttempts,
+		Now:         now,
+		RowLimit:    s.config.Webhooks.BatchSize,
+	})
+	if err != nil {
+		return appError.ErrWebhook.WithWrappedError(appError.ErrPostgres.WithError(err)).WithMessage("Failed to claim webhook deliveries").Err()
+	}
+
+	if len(rows) == 0 {
+		return nil
+	}
+
+	var (
+		m    sync.Mutex
+		errs error
+		wg   sync.WaitGroup
+	)
+	for _, row := range rows {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := s.deliverWebhook(ctx, row); err != nil {
+				m.Lock()
+				errs = multierror.Append(errs, err)
+				m.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	log.Debug().Int("deliveries", len(rows)).Msg("Webhook deliveries processed")
+	return errs
+}
+
+// deliverWebhook sends the event and stores the result, only the failures to store it are returned
+func (s *Service) deliverWebhook(ctx context.Context, row postgres.VpnWebhookDelivery) error {
+	sendErr := s.webhooks.Send(ctx, model.WebhookDelivery{
+		ID:        row.ID,
+		EventID:   row.EventID,
+		EventType: row.EventType,
+		URL:       row.Url,
+		Payload:   row.Payload,
+		Attempts:  row.Attempts,
+	})
+
+	if sendErr == nil {
+		if err := s.repository.MarkVPNWebhookDelivered(ctx, postgres.MarkVPNWebhookDeliveredParams{
+			ID:          row.ID,
+			DeliveredAt: time.Now(),
+		}); err != nil {
+			return appError.ErrWebhook.WithWrappedError(appError.ErrPostgres.WithError(err)).WithMessage("Failed to mark webhook delivered").WithContext("id", row.ID).Err()
+		}
+		return nil
+	}
+
+	attempts := row.Attempts + 1
+	if attempts >= s.config.Webhooks.MaxAttempts {
+		log.Error().Err(sendErr).Int64("id", row.ID).Str("event", row.EventType).Str("url", row.Url).Msg("Webhook delivery attempts ran out, the event can be replayed")
+	} else {
+		log.Warn().Err(sendErr).Int64("id", row.ID).Str("event", row.EventType).Str("url", row.Url).Int32("attempts", attempts).Msg("Failed to deliver webhook event")
+	}
+
+	if err := s.repository.FailVPNWebhookDelivery(ctx, postgres.FailVPNWebhookDeliveryParams{
+		ID:            row.ID,
+		NextAttemptAt: time.Now().Add(s.webhookBackoff(attempts)),
+		LastError:     sendErr.Error(),
+	}); err != nil {
+		return appError.ErrWebhook.WithWrappedError(appError.ErrPostgres.WithError(err)).WithMessage("Failed to store webhook delivery failure").WithContext("id", row.ID).Err()
+	}
+	return nil
+}
+
+func (s *Service) RunWebhookDelivery(ctx context.Context) {
+	ticker := time.NewTicker(s.config.Webhooks.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Debug().Msg("Webhook delivery stopped")
+			return
+		case <-ticker.C:
+			if err := s.DeliverWebhooks(ctx); err != nil {
+				log.Error().Err(err).Msg("Failed to deliver webhooks")
+			}
+		}
+	}
+}
+
+// ReplayWebhooks queues the events created within the filter for delivery again with fresh attempts
+func (s *Service) ReplayWebhooks(ctx context.Context, filter model.WebhookReplayFilter) (int64, error) {
+	if filter.EventType != "" && !slices.Contains(model.WebhookEventTypes, filter.EventType) {
+		return 0, appError.ErrWebhookInvalidEventType.WithContext("event", filter.EventType).Err()
+	}
+
+	now := time.Now()
+	if filter.Until.IsZero() {
+		filter.Until = now
+	}
+
+	log.Debug().Str("event", filter.EventType).Bool("undeliveredOnly", filter.UndeliveredOnly).Msg("Replaying webhook events in db")
+	affected, err := s.repository.ReplayVPNWebhookDeliveries(ctx, postgres.ReplayVPNWebhookDeliveriesParams{
+		Now:             now,
+		Since:           filter.Since,
+		Until:           filter.Until,
+		EventType:       filter.EventType,
+		UndeliveredOnly: filter.UndeliveredOnly,
+	})
+	if err != nil {
+		return 0, appError.ErrWebhook.WithWrappedError(appError.ErrPostgres.WithError(err)).WithMessage("Failed to replay webhook events").Err()
+	}
+
+	return affected, nil
+}
@@ -0,0 +1,68 @@
+package service_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/cybericebox/wireguard/internal/model"
+	"github.com/cybericebox/wireguard/pkg/controller/grpc/client"
+	"github.com/cybericebox/wireguard/pkg/controller/grpc/protobuf"
+	"github.com/cybericebox/wireguard/pkg/wgtest"
+	"github.com/gofrs/uuid"
+	"testing"
+)
+
+func TestWebhookEventsAreQueuedWithTheChange(t *testing.T) {
+	h := wgtest.NewHarness(t)
+	h.SetWebhookURLs("https://hooks.wgtest/first", "https://hooks.wgtest/second")
+	c := h.Client(t, []string{client.RoleAdmin}, "")
+	ctx := context.Background()
+
+	userID, groupID := uuid.Must(uuid.NewV4()).String(), uuid.Must(uuid.NewV4()).String()
+	if _, err := c.GetClientConfig(ctx, &protobuf.ClientConfigRequest{UserID: userID, GroupID: groupID, DestCIDR: "10.0.0.0/24"}); err != nil {
+		t.Fatalf("get client config: %v", err)
+	}
+	if _, err := c.BanClients(ctx, &protobuf.ClientsRequest{UserID: userID, GroupID: groupID, BanReason: "cheating"}); err != nil {
+		t.Fatalf("ban client: %v", err)
+	}
+
+	// every event is queued for every URL
+	deliveries := h.Repository.WebhookDeliveries()
+	if len(deliveries) != 4 {
+		t.Fatalf("expected 4 webhook deliveries, got %d", len(deliveries))
+	}
+	for i, eventType := range []string{model.WebhookEventClientCreated, model.WebhookEventClientBanned} {
+		for _, d := range deliveries[2*i : 2*i+2] {
+			var payload struct {
+				Type    string         `json:"type"`
+				UserID  string         `json:"userID"`
+				GroupID string         `json:"groupID"`
+				Data    map[string]any `json:"data"`
+			}
+			if err := json.Unmarshal(d.Payload, &payload); err != nil {
+				t.Fatalf("unmarshal payload: %v", err)
+			}
+			if d.EventType != eventType || payload.Type != eventType || payload.UserID != userID || payload.GroupID != groupID {
+				t.Fatalf("expected the %s event of the client, got %s with %+v", eventType, d.EventType, payload)
+			}
+		}
+	}
+
+	// the change is not stored if its events are not queued
+	h.Repository.SetWebhookDeliveryError(errors.New("outbox is unavailable"))
+	if _, err := c.UnBanClients(ctx, &protobuf.ClientsRequest{UserID: userID, GroupID: groupID}); err == nil {
+		t.Fatal("client was unbanned without the queued event")
+	}
+	if rows := h.Repository.Clients(); len(rows) != 1 || !rows[0].Banned {
+		t.Fatalf("expected the client to stay banned, got %+v", rows)
+	}
+	if _, err := c.DeleteClients(ctx, &protobuf.ClientsRequest{UserID: userID, GroupID: groupID}); err == nil {
+		t.Fatal("client was deleted without the queued event")
+	}
+	if rows := h.Repository.Clients(); len(rows) != 1 {
+		t.Fatalf("expected the client to stay stored, got %d clients", len(rows))
+	}
+	if after := h.Repository.WebhookDeliveries(); len(after) != len(deliveries) {
+		t.Fatalf("expected no deliveries of the failed changes, got %d more", len(after)-len(deliveries))
+	}
+}
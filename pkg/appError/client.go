@@ -8,4 +8,13 @@ var (
 	ErrClientInvalidAllowedIPs = err.ErrInvalidData.WithObjectCode(clientObjectCode).WithMessage("Invalid allowed IPs").WithDetailCode(1)
 	ErrClientInvalidUserID     = err.ErrInvalidData.WithObjectCode(clientObjectCode).WithMessage("Invalid user ID").WithDetailCode(2)
 	ErrClientInvalidGroupID    = err.ErrInvalidData.WithObjectCode(clientObjectCode).WithMessage("Invalid group ID").WithDetailCode(3)
+	ErrClientUnscopedOperation = err.ErrInvalidData.WithObjectCode(clientObjectCode).WithMessage("Operation targets all clients without AllClients confirmation").WithDetailCode(4)
+	ErrClientAmbiguousScope    = err.ErrInvalidData.WithObjectCode(clientObjectCode).WithMessage("AllClients can not be combined with user or group ID").WithDetailCode(5)
+	ErrClientInvalidPageToken  = err.ErrInvalidData.WithObjectCode(clientObjectCode).WithMessage("Invalid page token").WithDetailCode(6)
+	ErrClientInvalidSortBy     = err.ErrInvalidData.WithObjectCode(clientObjectCode).WithMessage("Invalid sort order").WithDetailCode(7)
+	ErrClientInvalidFilter     = err.ErrInvalidData.WithObjectCode(clientObjectCode).WithMessage("Invalid clients filter").WithDetailCode(8)
+	ErrClientNotFound          = err.ErrObjectNotFound.WithObjectCode(clientObjectCode).WithMessage("Client not found").WithDetailCode(9)
+	ErrClientDuplicate         = err.ErrInvalidData.WithObjectCode(clientObjectCode).WithMessage("Client is repeated in the batch").WithDetailCode(10)
+	ErrClientBatchTooLarge     = err.ErrInvalidData.WithObjectCode(clientObjectCode).WithMessage("Batch of clients is too large").WithDetailCode(11)
+	ErrClientInvalidLimits     = err.ErrInvalidData.WithObjectCode(clientObjectCode).WithMessage("Invalid client limits").WithDetailCode(12)
 )
@@ -10,12 +10,18 @@ const (
 	iptablesObjectCode
 	wireguardObjectCode
 	clientObjectCode
+	nodeObjectCode
+	sqliteObjectCode
+	ipamObjectCode
+	dnsObjectCode
+	webhookObjectCode
 )
 
 // base object errors
 var (
 	ErrPlatform  = err.ErrInternal.WithObjectCode(platformObjectCode)
 	ErrPostgres  = err.ErrInternal.WithObjectCode(postgresObjectCode)
+	ErrSQLite    = err.ErrInternal.WithObjectCode(sqliteObjectCode)
 	ErrIptables  = err.ErrInternal.WithObjectCode(iptablesObjectCode)
 	ErrWireguard = err.ErrInternal.WithObjectCode(wireguardObjectCode)
 )
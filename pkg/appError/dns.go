@@ -0,0 +1,15 @@
+package appError
+
+import "github.com/cybericebox/lib/pkg/err"
+
+var (
+	ErrDNS = err.ErrInternal.WithObjectCode(dnsObjectCode)
+
+	ErrDNSInvalidName     = err.ErrInvalidData.WithObjectCode(dnsObjectCode).WithMessage("Invalid hostname").WithDetailCode(1)
+	ErrDNSInvalidAddress  = err.ErrInvalidData.WithObjectCode(dnsObjectCode).WithMessage("Invalid record address").WithDetailCode(2)
+	ErrDNSAddressOutOfLab = err.ErrInvalidData.WithObjectCode(dnsObjectCode).WithMessage("Address is out of lab CIDRs of the group").WithDetailCode(3)
+	ErrDNSRecordNotFound  = err.ErrObjectNotFound.WithObjectCode(dnsObjectCode).WithMessage("DNS record not found").WithDetailCode(4)
+	ErrDNSRecordExists    = err.ErrInvalidData.WithObjectCode(dnsObjectCode).WithMessage("DNS record already exists").WithDetailCode(5)
+	ErrDNSInvalidRecordID = err.ErrInvalidData.WithObjectCode(dnsObjectCode).WithMessage("Invalid DNS record ID").WithDetailCode(6)
+	ErrDNSMissingGroupID  = err.ErrInvalidData.WithObjectCode(dnsObjectCode).WithMessage("DNS records are scoped to group, group ID is required").WithDetailCode(7)
+)
@@ -5,8 +5,16 @@ import "github.com/cybericebox/lib/pkg/err"
 var (
 	ErrGRPC = err.ErrInternal.WithObjectCode(gRPCObjectCode)
 
-	ErrGRPCUnauthenticated    = err.ErrUnauthenticated.WithObjectCode(gRPCObjectCode)
-	ErrGRPCMissingKey         = err.ErrInvalidData.WithObjectCode(gRPCObjectCode).WithDetailCode(1).WithMessage("Missing key")
-	ErrGRPCInvalidKey         = err.ErrInvalidData.WithObjectCode(gRPCObjectCode).WithDetailCode(2).WithMessage("Invalid key")
-	ErrGRPCInvalidTokenFormat = err.ErrInvalidData.WithObjectCode(gRPCObjectCode).WithDetailCode(3).WithMessage("Invalid token format")
+	ErrGRPCUnauthenticated      = err.ErrUnauthenticated.WithObjectCode(gRPCObjectCode)
+	ErrGRPCMissingKey           = err.ErrInvalidData.WithObjectCode(gRPCObjectCode).WithDetailCode(1).WithMessage("Missing key")
+	ErrGRPCInvalidKey           = err.ErrInvalidData.WithObjectCode(gRPCObjectCode).WithDetailCode(2).WithMessage("Invalid key")
+	ErrGRPCInvalidTokenFormat   = err.ErrInvalidData.WithObjectCode(gRPCObjectCode).WithDetailCode(3).WithMessage("Invalid token format")
+	ErrGRPCUnknownSigningKey    = err.ErrUnauthenticated.WithObjectCode(gRPCObjectCode).WithDetailCode(4).WithMessage("Unknown signing key")
+	ErrGRPCTokenExpired         = err.ErrUnauthenticated.WithObjectCode(gRPCObjectCode).WithDetailCode(5).WithMessage("Token is expired")
+	ErrGRPCInvalidTokenClaims   = err.ErrUnauthenticated.WithObjectCode(gRPCObjectCode).WithDetailCode(6).WithMessage("Invalid token claims")
+	ErrGRPCInsufficientRole     = err.ErrForbidden.WithObjectCode(gRPCObjectCode).WithDetailCode(7).WithMessage("Insufficient role")
+	ErrGRPCGroupForbidden       = err.ErrForbidden.WithObjectCode(gRPCObjectCode).WithDetailCode(8).WithMessage("Token is not allowed to act on group")
+	ErrGRPCMissingCertificate   = err.ErrUnauthenticated.WithObjectCode(gRPCObjectCode).WithDetailCode(9).WithMessage("Missing client certificate")
+	ErrGRPCUnknownCertificate   = err.ErrUnauthenticated.WithObjectCode(gRPCObjectCode).WithDetailCode(10).WithMessage("Unknown client certificate")
+	ErrGRPCInvalidNodeSignature = err.ErrUnauthenticated.WithObjectCode(gRPCObjectCode).WithDetailCode(11).WithMessage("Invalid node signature")
 )
@@ -0,0 +1,10 @@
+package appError
+
+import "github.com/cybericebox/lib/pkg/err"
+
+var (
+	ErrIPAM = err.ErrInternal.WithObjectCode(ipamObjectCode)
+
+	ErrIPAMNoFreeIP     = err.ErrInternal.WithObjectCode(ipamObjectCode).WithMessage("No free IP left in CIDR").WithDetailCode(1)
+	ErrIPAMIPOutOfRange = err.ErrInvalidData.WithObjectCode(ipamObjectCode).WithMessage("IP is out of CIDR").WithDetailCode(2)
+)
@@ -0,0 +1,12 @@
+package appError
+
+import "github.com/cybericebox/lib/pkg/err"
+
+var (
+	ErrNode = err.ErrInternal.WithObjectCode(nodeObjectCode)
+
+	ErrNodeNotFound    = err.ErrObjectNotFound.WithObjectCode(nodeObjectCode).WithMessage("Node not found").WithDetailCode(1)
+	ErrNodeUnavailable = err.ErrInternal.WithObjectCode(nodeObjectCode).WithMessage("Node is unavailable").WithDetailCode(2)
+	ErrNodeMissingKey  = err.ErrInternal.WithObjectCode(nodeObjectCode).WithMessage("Node key is required to forward requests").WithDetailCode(3)
+	ErrNodeMissingTLS  = err.ErrInternal.WithObjectCode(nodeObjectCode).WithMessage("TLS is required to forward requests").WithDetailCode(4)
+)
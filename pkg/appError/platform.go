@@ -0,0 +1,7 @@
+package appError
+
+import "github.com/cybericebox/lib/pkg/err"
+
+var (
+	ErrPlatformNotReady = err.ErrInternal.WithObjectCode(platformObjectCode).WithMessage("Server is not ready").WithDetailCode(1)
+)
@@ -0,0 +1,10 @@
+package appError
+
+import "github.com/cybericebox/lib/pkg/err"
+
+var (
+	ErrWebhook = err.ErrInternal.WithObjectCode(webhookObjectCode)
+
+	ErrWebhookInvalidEventType = err.ErrInvalidData.WithObjectCode(webhookObjectCode).WithMessage("Invalid webhook event type").WithDetailCode(1)
+	ErrWebhookRejected         = err.ErrInternal.WithObjectCode(webhookObjectCode).WithMessage("Webhook request was rejected").WithDetailCode(2)
+)
@@ -2,6 +2,12 @@ package client
 
 import (
 	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
 	"github.com/cybericebox/wireguard/pkg/appError"
 	"github.com/cybericebox/wireguard/pkg/controller/grpc/protobuf"
 	"github.com/golang-jwt/jwt"
@@ -10,36 +16,93 @@ import (
 	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/credentials/insecure"
 	"google.golang.org/grpc/status"
+	"os"
+	"sync"
+	"time"
 )
 
 const (
 	NoTokenErrMsg      = "token contains an invalid number of segments"
 	UnauthorizedErrMsg = "unauthorized"
 	AuthKey            = "authKey"
+	Subject            = "sub"
+	KeyID              = "kid"
+	Roles              = "roles"
+	GroupID            = "groupID"
+
+	// RoleViewer can read the clients and the monitoring
+	RoleViewer = "viewer"
+	// RoleOperator can also get the client configs and ban and unban the clients
+	RoleOperator = "operator"
+	// RoleAdmin can also delete the clients and read the audit log
+	RoleAdmin = "admin"
+
+	// DefaultAudience is the audience the wireguard service requires by default
+	DefaultAudience = "wireguard"
+	// DefaultTokenTTL is the lifetime of the tokens minted by the client
+	DefaultTokenTTL = 5 * time.Minute
 )
 
 type (
 	Credentials struct {
 		Token    string
 		Insecure bool
+
+		// source mints the token per request instead of the static token if set
+		source *tokenSource
 	}
 
 	Config struct {
 		Endpoint string
 		Auth     Auth
 		TLS      TLS
+		// DialOptions are appended to the options of the connection, e.g. to dial the in-memory listener in tests
+		DialOptions []grpc.DialOption
 	}
 
 	Auth struct {
 		AuthKey string
+		// SignKey signs the tokens with HMAC if neither PrivateKey nor PrivateKeyFile is set
 		SignKey string
+		// PrivateKey signs the tokens asymmetrically (*rsa.PrivateKey, *ecdsa.PrivateKey or ed25519.PrivateKey)
+		PrivateKey crypto.PrivateKey
+		// PrivateKeyFile is the PEM file of the PrivateKey
+		PrivateKeyFile string
+		// KeyID identifies the sign key on the server, the key without ID is used if it is empty
+		KeyID string
+		// Subject identifies the caller in the audit log
+		Subject  string
+		Issuer   string
+		Audience string
+		// Roles are granted to the caller, see RoleViewer, RoleOperator and RoleAdmin
+		Roles []string
+		// GroupID scopes the caller to the group if it is set
+		GroupID string
+		// TokenTTL is the lifetime of the tokens, DefaultTokenTTL is used if it is zero
+		TokenTTL time.Duration
+	}
+
+	// tokenSource mints short-lived tokens and refreshes them before they expire
+	tokenSource struct {
+		m         sync.Mutex
+		auth      Auth
+		method    jwt.SigningMethod
+		key       interface{}
+		token     string
+		expiresAt time.Time
 	}
 
 	TLS struct {
-		Enabled  bool
+		Enabled bool
+		// CertFile and CertKey are the client certificate presented to the server, it is required by the server with TLS enabled
 		CertFile string
 		CertKey  string
-		CaFile   string
+		// CaFile verifies the server certificate, the system roots are used if it is empty
+		CaFile string
+		// ServerName overrides the name the server certificate is verified against, the endpoint host is used if it is empty
+		ServerName string
+		// MinVersion is the minimum TLS version, TLS 1.2 is used if it is zero
+		MinVersion uint16
 	}
 
 	WireguardClient interface {
@@ -54,8 +117,16 @@ type (
 )
 
 func (c Credentials) GetRequestMetadata(context.Context, ...string) (map[string]string, error) {
+	token := c.Token
+	if c.source != nil {
+		var err error
+		if token, err = c.source.Token(); err != nil {
+			return nil, err
+		}
+	}
+
 	return map[string]string{
-		"token": c.Token,
+		"token": token,
 	}, nil
 }
 
@@ -65,15 +136,41 @@ func (c Credentials) RequireTransportSecurity() bool {
 
 func getCredentials(conf TLS) (credentials.TransportCredentials, error) {
 	log.Debug().Msg("Preparing credentials for RPC")
-	if conf.Enabled {
-		creds, err := credentials.NewServerTLSFromFile(conf.CertFile, conf.CertKey)
+	if !conf.Enabled {
+		return insecure.NewCredentials(), nil
+	}
+
+	tlsConfig := &tls.Config{
+		ServerName: conf.ServerName,
+		MinVersion: conf.MinVersion,
+	}
+
+	if tlsConfig.MinVersion == 0 {
+		tlsConfig.MinVersion = tls.VersionTLS12
+	}
+
+	if conf.CaFile != "" {
+		ca, err := os.ReadFile(conf.CaFile)
 		if err != nil {
-			return nil, appError.ErrGRPC.WithError(err).WithMessage("Failed to create server credentials").Err()
+			return nil, appError.ErrGRPC.WithError(err).WithMessage("Failed to read CA file").Err()
 		}
-		return creds, nil
-	} else {
-		return insecure.NewCredentials(), nil
+
+		certPool := x509.NewCertPool()
+		if ok := certPool.AppendCertsFromPEM(ca); !ok {
+			return nil, appError.ErrGRPC.WithMessage("Failed to append CA certificates").Err()
+		}
+		tlsConfig.RootCAs = certPool
 	}
+
+	if conf.CertFile != "" || conf.CertKey != "" {
+		certificate, err := tls.LoadX509KeyPair(conf.CertFile, conf.CertKey)
+		if err != nil {
+			return nil, appError.ErrGRPC.WithError(err).WithMessage("Failed to load client certificate").Err()
+		}
+		tlsConfig.Certificates = []tls.Certificate{certificate}
+	}
+
+	return credentials.NewTLS(tlsConfig), nil
 }
 
 func translateRPCErr(err error) error {
@@ -95,15 +192,134 @@ func translateRPCErr(err error) error {
 	return appError.ErrGRPC.WithError(err).WithMessage("Failed to perform RPC").Err()
 }
 
-func constructAuthCredentials(authKey, signKey string) (Credentials, error) {
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
-		AuthKey: authKey,
-	})
-	tokenString, err := token.SignedString([]byte(signKey))
+func newTokenSource(auth Auth) (*tokenSource, error) {
+	if auth.Audience == "" {
+		auth.Audience = DefaultAudience
+	}
+
+	if auth.TokenTTL <= 0 {
+		auth.TokenTTL = DefaultTokenTTL
+	}
+
+	if auth.PrivateKey == nil && auth.PrivateKeyFile != "" {
+		data, err := os.ReadFile(auth.PrivateKeyFile)
+		if err != nil {
+			return nil, appError.ErrGRPC.WithError(err).WithMessage("Failed to read private key file").Err()
+		}
+
+		if auth.PrivateKey, err = parsePrivateKey(data); err != nil {
+			return nil, appError.ErrGRPC.WithError(err).WithMessage("Failed to parse private key file").Err()
+		}
+	}
+
+	method, key, err := signingMethod(auth)
 	if err != nil {
+		return nil, appError.ErrGRPC.WithError(err).WithMessage("Failed to get signing method").Err()
+	}
+
+	return &tokenSource{auth: auth, method: method, key: key}, nil
+}
+
+// signingMethod returns the signing method of the private key, the tokens are signed with HMAC if there is no private key
+func signingMethod(auth Auth) (jwt.SigningMethod, interface{}, error) {
+	switch key := auth.PrivateKey.(type) {
+	case nil:
+		return jwt.SigningMethodHS256, []byte(auth.SignKey), nil
+	case *rsa.PrivateKey:
+		return jwt.SigningMethodRS256, key, nil
+	case *ecdsa.PrivateKey:
+		switch key.Curve.Params().BitSize {
+		case 256:
+			return jwt.SigningMethodES256, key, nil
+		case 384:
+			return jwt.SigningMethodES384, key, nil
+		case 521:
+			return jwt.SigningMethodES512, key, nil
+		}
+	case ed25519.PrivateKey:
+		return jwt.SigningMethodEdDSA, key, nil
+	}
+
+	return nil, nil, appError.ErrGRPC.WithMessage("Unsupported private key").Err()
+}
+
+// parsePrivateKey parses the RSA, ECDSA or Ed25519 private key in PEM format
+func parsePrivateKey(data []byte) (crypto.PrivateKey, error) {
+	if key, err := jwt.ParseRSAPrivateKeyFromPEM(data); err == nil {
+		return key, nil
+	}
+
+	if key, err := jwt.ParseECPrivateKeyFromPEM(data); err == nil {
+		return key, nil
+	}
+
+	key, err := jwt.ParseEdPrivateKeyFromPEM(data)
+	if err != nil {
+		return nil, appError.ErrGRPC.WithError(err).WithMessage("Unsupported private key format").Err()
+	}
+
+	return key, nil
+}
+
+// Token returns the current token and mints a new one when the current token has used up most of its lifetime
+func (s *tokenSource) Token() (string, error) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	if s.token != "" && time.Until(s.expiresAt) > s.auth.TokenTTL/5 {
+		return s.token, nil
+	}
+
+	now := time.Now()
+	expiresAt := now.Add(s.auth.TokenTTL)
+
+	claims := jwt.MapClaims{
+		AuthKey: s.auth.AuthKey,
+		"aud":   s.auth.Audience,
+		"iat":   now.Unix(),
+		"nbf":   now.Unix(),
+		"exp":   expiresAt.Unix(),
+	}
+	if s.auth.Subject != "" {
+		claims[Subject] = s.auth.Subject
+	}
+	if s.auth.Issuer != "" {
+		claims["iss"] = s.auth.Issuer
+	}
+	if len(s.auth.Roles) > 0 {
+		claims[Roles] = s.auth.Roles
+	}
+	if s.auth.GroupID != "" {
+		claims[GroupID] = s.auth.GroupID
+	}
+
+	token := jwt.NewWithClaims(s.method, claims)
+	if s.auth.KeyID != "" {
+		token.Header[KeyID] = s.auth.KeyID
+	}
+
+	tokenString, err := token.SignedString(s.key)
+	if err != nil {
+		return "", appError.ErrGRPC.WithError(err).WithMessage("Failed to sign token").Err()
+	}
+
+	log.Debug().Time("expiresAt", expiresAt).Msg("Token for wireguard is refreshed")
+	s.token, s.expiresAt = tokenString, expiresAt
+
+	return s.token, nil
+}
+
+func constructAuthCredentials(auth Auth) (Credentials, error) {
+	source, err := newTokenSource(auth)
+	if err != nil {
+		return Credentials{}, err
+	}
+
+	// mint the first token to fail early on invalid keys
+	if _, err := source.Token(); err != nil {
 		return Credentials{}, translateRPCErr(err)
 	}
-	authCreds := Credentials{Token: tokenString}
+	authCreds := Credentials{source: source}
 	return authCreds, nil
 }
 
@@ -111,7 +327,7 @@ func constructAuthCredentials(authKey, signKey string) (Credentials, error) {
 func NewWireguardConnection(config Config) (WireguardClient, error) {
 	log.Debug().Str("url", config.Endpoint).Msg("Connecting to wireguard")
 
-	authCreds, err := constructAuthCredentials(config.Auth.AuthKey, config.Auth.SignKey)
+	authCreds, err := constructAuthCredentials(config.Auth)
 	if err != nil {
 		return nil, appError.ErrGRPC.WithError(err).WithMessage("Failed to construct auth credentials").Err()
 	}
@@ -134,7 +350,7 @@ func NewWireguardConnection(config Config) (WireguardClient, error) {
 		}
 	}
 
-	conn, err := grpc.NewClient(config.Endpoint, dialOpts...)
+	conn, err := grpc.NewClient(config.Endpoint, append(dialOpts, config.DialOptions...)...)
 	if err != nil {
 		return nil, translateRPCErr(err)
 	}
@@ -19,13 +19,22 @@ import (
 const _ = grpc.SupportPackageIsVersion8
 
 const (
-	Wireguard_Ping_FullMethodName            = "/wireguard.Wireguard/Ping"
-	Wireguard_Monitoring_FullMethodName      = "/wireguard.Wireguard/Monitoring"
-	Wireguard_GetClients_FullMethodName      = "/wireguard.Wireguard/GetClients"
-	Wireguard_GetClientConfig_FullMethodName = "/wireguard.Wireguard/GetClientConfig"
-	Wireguard_DeleteClients_FullMethodName   = "/wireguard.Wireguard/DeleteClients"
-	Wireguard_BanClients_FullMethodName      = "/wireguard.Wireguard/BanClients"
-	Wireguard_UnBanClients_FullMethodName    = "/wireguard.Wireguard/UnBanClients"
+	Wireguard_Ping_FullMethodName              = "/wireguard.Wireguard/Ping"
+	Wireguard_Monitoring_FullMethodName        = "/wireguard.Wireguard/Monitoring"
+	Wireguard_GetClients_FullMethodName        = "/wireguard.Wireguard/GetClients"
+	Wireguard_GetClient_FullMethodName         = "/wireguard.Wireguard/GetClient"
+	Wireguard_GetClientConfig_FullMethodName   = "/wireguard.Wireguard/GetClientConfig"
+	Wireguard_ProvisionClients_FullMethodName  = "/wireguard.Wireguard/ProvisionClients"
+	Wireguard_DeleteClients_FullMethodName     = "/wireguard.Wireguard/DeleteClients"
+	Wireguard_BanClients_FullMethodName        = "/wireguard.Wireguard/BanClients"
+	Wireguard_UnBanClients_FullMethodName      = "/wireguard.Wireguard/UnBanClients"
+	Wireguard_SetClientLimits_FullMethodName   = "/wireguard.Wireguard/SetClientLimits"
+	Wireguard_GetAuditLog_FullMethodName       = "/wireguard.Wireguard/GetAuditLog"
+	Wireguard_CreateDNSRecord_FullMethodName   = "/wireguard.Wireguard/CreateDNSRecord"
+	Wireguard_ListDNSRecords_FullMethodName    = "/wireguard.Wireguard/ListDNSRecords"
+	Wireguard_DeleteDNSRecord_FullMethodName   = "/wireguard.Wireguard/DeleteDNSRecord"
+	Wireguard_GetClientSessions_FullMethodName = "/wireguard.Wireguard/GetClientSessions"
+	Wireguard_ReplayWebhooks_FullMethodName    = "/wireguard.Wireguard/ReplayWebhooks"
 )
 
 // WireguardClient is the client API for Wireguard service.
@@ -35,11 +44,24 @@ type WireguardClient interface {
 	// metrics
 	Ping(ctx context.Context, in *EmptyRequest, opts ...grpc.CallOption) (*EmptyResponse, error)
 	Monitoring(ctx context.Context, opts ...grpc.CallOption) (Wireguard_MonitoringClient, error)
-	GetClients(ctx context.Context, in *ClientsRequest, opts ...grpc.CallOption) (*ClientsResponse, error)
+	GetClients(ctx context.Context, in *GetClientsRequest, opts ...grpc.CallOption) (*ClientsResponse, error)
+	GetClient(ctx context.Context, in *ClientRequest, opts ...grpc.CallOption) (*ClientDetailsResponse, error)
 	GetClientConfig(ctx context.Context, in *ClientConfigRequest, opts ...grpc.CallOption) (*ConfigResponse, error)
+	ProvisionClients(ctx context.Context, in *ProvisionClientsRequest, opts ...grpc.CallOption) (*ProvisionClientsResponse, error)
 	DeleteClients(ctx context.Context, in *ClientsRequest, opts ...grpc.CallOption) (*ClientsAffectedResponse, error)
 	BanClients(ctx context.Context, in *ClientsRequest, opts ...grpc.CallOption) (*ClientsAffectedResponse, error)
 	UnBanClients(ctx context.Context, in *ClientsRequest, opts ...grpc.CallOption) (*ClientsAffectedResponse, error)
+	SetClientLimits(ctx context.Context, in *ClientsRequest, opts ...grpc.CallOption) (*ClientsAffectedResponse, error)
+	// audit
+	GetAuditLog(ctx context.Context, in *AuditLogRequest, opts ...grpc.CallOption) (*AuditLogResponse, error)
+	// lab hostnames
+	CreateDNSRecord(ctx context.Context, in *CreateDNSRecordRequest, opts ...grpc.CallOption) (*DNSRecordResponse, error)
+	ListDNSRecords(ctx context.Context, in *DNSRecordsRequest, opts ...grpc.CallOption) (*DNSRecordsResponse, error)
+	DeleteDNSRecord(ctx context.Context, in *DNSRecordRequest, opts ...grpc.CallOption) (*EmptyResponse, error)
+	// connection history
+	GetClientSessions(ctx context.Context, in *ClientSessionsRequest, opts ...grpc.CallOption) (*ClientSessionsResponse, error)
+	// webhooks
+	ReplayWebhooks(ctx context.Context, in *ReplayWebhooksRequest, opts ...grpc.CallOption) (*ReplayWebhooksResponse, error)
 }
 
 type wireguardClient struct {
@@ -92,7 +114,7 @@ func (x *wireguardMonitoringClient) Recv() (*MonitoringResponse, error) {
 	return m, nil
 }
 
-func (c *wireguardClient) GetClients(ctx context.Context, in *ClientsRequest, opts ...grpc.CallOption) (*ClientsResponse, error) {
+func (c *wireguardClient) GetClients(ctx context.Context, in *GetClientsRequest, opts ...grpc.CallOption) (*ClientsResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(ClientsResponse)
 	err := c.cc.Invoke(ctx, Wireguard_GetClients_FullMethodName, in, out, cOpts...)
@@ -102,6 +124,16 @@ func (c *wireguardClient) GetClients(ctx context.Context, in *ClientsRequest, op
 	return out, nil
 }
 
+func (c *wireguardClient) GetClient(ctx context.Context, in *ClientRequest, opts ...grpc.CallOption) (*ClientDetailsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ClientDetailsResponse)
+	err := c.cc.Invoke(ctx, Wireguard_GetClient_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *wireguardClient) GetClientConfig(ctx context.Context, in *ClientConfigRequest, opts ...grpc.CallOption) (*ConfigResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(ConfigResponse)
@@ -112,6 +144,16 @@ func (c *wireguardClient) GetClientConfig(ctx context.Context, in *ClientConfigR
 	return out, nil
 }
 
+func (c *wireguardClient) ProvisionClients(ctx context.Context, in *ProvisionClientsRequest, opts ...grpc.CallOption) (*ProvisionClientsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ProvisionClientsResponse)
+	err := c.cc.Invoke(ctx, Wireguard_ProvisionClients_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *wireguardClient) DeleteClients(ctx context.Context, in *ClientsRequest, opts ...grpc.CallOption) (*ClientsAffectedResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(ClientsAffectedResponse)
@@ -142,6 +184,76 @@ func (c *wireguardClient) UnBanClients(ctx context.Context, in *ClientsRequest,
 	return out, nil
 }
 
+func (c *wireguardClient) SetClientLimits(ctx context.Context, in *ClientsRequest, opts ...grpc.CallOption) (*ClientsAffectedResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ClientsAffectedResponse)
+	err := c.cc.Invoke(ctx, Wireguard_SetClientLimits_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *wireguardClient) GetAuditLog(ctx context.Context, in *AuditLogRequest, opts ...grpc.CallOption) (*AuditLogResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AuditLogResponse)
+	err := c.cc.Invoke(ctx, Wireguard_GetAuditLog_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *wireguardClient) CreateDNSRecord(ctx context.Context, in *CreateDNSRecordRequest, opts ...grpc.CallOption) (*DNSRecordResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DNSRecordResponse)
+	err := c.cc.Invoke(ctx, Wireguard_CreateDNSRecord_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *wireguardClient) ListDNSRecords(ctx context.Context, in *DNSRecordsRequest, opts ...grpc.CallOption) (*DNSRecordsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DNSRecordsResponse)
+	err := c.cc.Invoke(ctx, Wireguard_ListDNSRecords_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *wireguardClient) DeleteDNSRecord(ctx context.Context, in *DNSRecordRequest, opts ...grpc.CallOption) (*EmptyResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(EmptyResponse)
+	err := c.cc.Invoke(ctx, Wireguard_DeleteDNSRecord_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *wireguardClient) GetClientSessions(ctx context.Context, in *ClientSessionsRequest, opts ...grpc.CallOption) (*ClientSessionsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ClientSessionsResponse)
+	err := c.cc.Invoke(ctx, Wireguard_GetClientSessions_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *wireguardClient) ReplayWebhooks(ctx context.Context, in *ReplayWebhooksRequest, opts ...grpc.CallOption) (*ReplayWebhooksResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ReplayWebhooksResponse)
+	err := c.cc.Invoke(ctx, Wireguard_ReplayWebhooks_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // WireguardServer is the server API for Wireguard service.
 // All implementations must embed UnimplementedWireguardServer
 // for forward compatibility
@@ -149,11 +261,24 @@ type WireguardServer interface {
 	// metrics
 	Ping(context.Context, *EmptyRequest) (*EmptyResponse, error)
 	Monitoring(Wireguard_MonitoringServer) error
-	GetClients(context.Context, *ClientsRequest) (*ClientsResponse, error)
+	GetClients(context.Context, *GetClientsRequest) (*ClientsResponse, error)
+	GetClient(context.Context, *ClientRequest) (*ClientDetailsResponse, error)
 	GetClientConfig(context.Context, *ClientConfigRequest) (*ConfigResponse, error)
+	ProvisionClients(context.Context, *ProvisionClientsRequest) (*ProvisionClientsResponse, error)
 	DeleteClients(context.Context, *ClientsRequest) (*ClientsAffectedResponse, error)
 	BanClients(context.Context, *ClientsRequest) (*ClientsAffectedResponse, error)
 	UnBanClients(context.Context, *ClientsRequest) (*ClientsAffectedResponse, error)
+	SetClientLimits(context.Context, *ClientsRequest) (*ClientsAffectedResponse, error)
+	// audit
+	GetAuditLog(context.Context, *AuditLogRequest) (*AuditLogResponse, error)
+	// lab hostnames
+	CreateDNSRecord(context.Context, *CreateDNSRecordRequest) (*DNSRecordResponse, error)
+	ListDNSRecords(context.Context, *DNSRecordsRequest) (*DNSRecordsResponse, error)
+	DeleteDNSRecord(context.Context, *DNSRecordRequest) (*EmptyResponse, error)
+	// connection history
+	GetClientSessions(context.Context, *ClientSessionsRequest) (*ClientSessionsResponse, error)
+	// webhooks
+	ReplayWebhooks(context.Context, *ReplayWebhooksRequest) (*ReplayWebhooksResponse, error)
 	mustEmbedUnimplementedWireguardServer()
 }
 
@@ -167,12 +292,18 @@ func (UnimplementedWireguardServer) Ping(context.Context, *EmptyRequest) (*Empty
 func (UnimplementedWireguardServer) Monitoring(Wireguard_MonitoringServer) error {
 	return status.Errorf(codes.Unimplemented, "method Monitoring not implemented")
 }
-func (UnimplementedWireguardServer) GetClients(context.Context, *ClientsRequest) (*ClientsResponse, error) {
+func (UnimplementedWireguardServer) GetClients(context.Context, *GetClientsRequest) (*ClientsResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetClients not implemented")
 }
+func (UnimplementedWireguardServer) GetClient(context.Context, *ClientRequest) (*ClientDetailsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetClient not implemented")
+}
 func (UnimplementedWireguardServer) GetClientConfig(context.Context, *ClientConfigRequest) (*ConfigResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetClientConfig not implemented")
 }
+func (UnimplementedWireguardServer) ProvisionClients(context.Context, *ProvisionClientsRequest) (*ProvisionClientsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ProvisionClients not implemented")
+}
 func (UnimplementedWireguardServer) DeleteClients(context.Context, *ClientsRequest) (*ClientsAffectedResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method DeleteClients not implemented")
 }
@@ -182,6 +313,27 @@ func (UnimplementedWireguardServer) BanClients(context.Context, *ClientsRequest)
 func (UnimplementedWireguardServer) UnBanClients(context.Context, *ClientsRequest) (*ClientsAffectedResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method UnBanClients not implemented")
 }
+func (UnimplementedWireguardServer) SetClientLimits(context.Context, *ClientsRequest) (*ClientsAffectedResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetClientLimits not implemented")
+}
+func (UnimplementedWireguardServer) GetAuditLog(context.Context, *AuditLogRequest) (*AuditLogResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetAuditLog not implemented")
+}
+func (UnimplementedWireguardServer) CreateDNSRecord(context.Context, *CreateDNSRecordRequest) (*DNSRecordResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateDNSRecord not implemented")
+}
+func (UnimplementedWireguardServer) ListDNSRecords(context.Context, *DNSRecordsRequest) (*DNSRecordsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListDNSRecords not implemented")
+}
+func (UnimplementedWireguardServer) DeleteDNSRecord(context.Context, *DNSRecordRequest) (*EmptyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteDNSRecord not implemented")
+}
+func (UnimplementedWireguardServer) GetClientSessions(context.Context, *ClientSessionsRequest) (*ClientSessionsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetClientSessions not implemented")
+}
+func (UnimplementedWireguardServer) ReplayWebhooks(context.Context, *ReplayWebhooksRequest) (*ReplayWebhooksResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReplayWebhooks not implemented")
+}
 func (UnimplementedWireguardServer) mustEmbedUnimplementedWireguardServer() {}
 
 // UnsafeWireguardServer may be embedded to opt out of forward compatibility for this service.
@@ -240,7 +392,7 @@ func (x *wireguardMonitoringServer) Recv() (*EmptyRequest, error) {
 }
 
 func _Wireguard_GetClients_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(ClientsRequest)
+	in := new(GetClientsRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
@@ -252,7 +404,25 @@ func _Wireguard_GetClients_Handler(srv interface{}, ctx context.Context, dec fun
 		FullMethod: Wireguard_GetClients_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(WireguardServer).GetClients(ctx, req.(*ClientsRequest))
+		return srv.(WireguardServer).GetClients(ctx, req.(*GetClientsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Wireguard_GetClient_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ClientRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WireguardServer).GetClient(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Wireguard_GetClient_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WireguardServer).GetClient(ctx, req.(*ClientRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
@@ -275,6 +445,24 @@ func _Wireguard_GetClientConfig_Handler(srv interface{}, ctx context.Context, de
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Wireguard_ProvisionClients_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ProvisionClientsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WireguardServer).ProvisionClients(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Wireguard_ProvisionClients_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WireguardServer).ProvisionClients(ctx, req.(*ProvisionClientsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _Wireguard_DeleteClients_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(ClientsRequest)
 	if err := dec(in); err != nil {
@@ -329,6 +517,132 @@ func _Wireguard_UnBanClients_Handler(srv interface{}, ctx context.Context, dec f
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Wireguard_SetClientLimits_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ClientsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WireguardServer).SetClientLimits(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Wireguard_SetClientLimits_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WireguardServer).SetClientLimits(ctx, req.(*ClientsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Wireguard_GetAuditLog_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AuditLogRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WireguardServer).GetAuditLog(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Wireguard_GetAuditLog_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WireguardServer).GetAuditLog(ctx, req.(*AuditLogRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Wireguard_CreateDNSRecord_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateDNSRecordRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WireguardServer).CreateDNSRecord(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Wireguard_CreateDNSRecord_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WireguardServer).CreateDNSRecord(ctx, req.(*CreateDNSRecordRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Wireguard_ListDNSRecords_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DNSRecordsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WireguardServer).ListDNSRecords(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Wireguard_ListDNSRecords_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WireguardServer).ListDNSRecords(ctx, req.(*DNSRecordsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Wireguard_DeleteDNSRecord_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DNSRecordRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WireguardServer).DeleteDNSRecord(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Wireguard_DeleteDNSRecord_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WireguardServer).DeleteDNSRecord(ctx, req.(*DNSRecordRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Wireguard_GetClientSessions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ClientSessionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WireguardServer).GetClientSessions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Wireguard_GetClientSessions_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WireguardServer).GetClientSessions(ctx, req.(*ClientSessionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Wireguard_ReplayWebhooks_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReplayWebhooksRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WireguardServer).ReplayWebhooks(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Wireguard_ReplayWebhooks_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WireguardServer).ReplayWebhooks(ctx, req.(*ReplayWebhooksRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // Wireguard_ServiceDesc is the grpc.ServiceDesc for Wireguard service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -344,10 +658,18 @@ var Wireguard_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetClients",
 			Handler:    _Wireguard_GetClients_Handler,
 		},
+		{
+			MethodName: "GetClient",
+			Handler:    _Wireguard_GetClient_Handler,
+		},
 		{
 			MethodName: "GetClientConfig",
 			Handler:    _Wireguard_GetClientConfig_Handler,
 		},
+		{
+			MethodName: "ProvisionClients",
+			Handler:    _Wireguard_ProvisionClients_Handler,
+		},
 		{
 			MethodName: "DeleteClients",
 			Handler:    _Wireguard_DeleteClients_Handler,
@@ -360,6 +682,34 @@ var Wireguard_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "UnBanClients",
 			Handler:    _Wireguard_UnBanClients_Handler,
 		},
+		{
+			MethodName: "SetClientLimits",
+			Handler:    _Wireguard_SetClientLimits_Handler,
+		},
+		{
+			MethodName: "GetAuditLog",
+			Handler:    _Wireguard_GetAuditLog_Handler,
+		},
+		{
+			MethodName: "CreateDNSRecord",
+			Handler:    _Wireguard_CreateDNSRecord_Handler,
+		},
+		{
+			MethodName: "ListDNSRecords",
+			Handler:    _Wireguard_ListDNSRecords_Handler,
+		},
+		{
+			MethodName: "DeleteDNSRecord",
+			Handler:    _Wireguard_DeleteDNSRecord_Handler,
+		},
+		{
+			MethodName: "GetClientSessions",
+			Handler:    _Wireguard_GetClientSessions_Handler,
+		},
+		{
+			MethodName: "ReplayWebhooks",
+			Handler:    _Wireguard_ReplayWebhooks_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
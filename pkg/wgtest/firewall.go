@@ -0,0 +1,217 @@
+package wgtest
+
+import (
+	"context"
+	"fmt"
+	"github.com/cybericebox/wireguard/internal/model"
+	"github.com/cybericebox/wireguard/pkg/appError"
+	"net/netip"
+	"slices"
+	"sync"
+)
+
+type (
+	// Firewall is the in-memory firewall with the forward rules of the interface and the NAT and blocking rules of the clients.
+	// The errors set by SetError are returned by the methods of the same name, so the failures of the kernel can be tested.
+	Firewall struct {
+		m       sync.Mutex
+		forward bool
+		nat     []model.FirewallRule
+		block   []model.FirewallRule
+		errs    map[string]error
+	}
+)
+
+func NewFirewall() *Firewall {
+	return &Firewall{
+		errs: make(map[string]error),
+	}
+}
+
+// SetError makes the method fail with the error, nil makes it succeed again
+func (f *Firewall) SetError(method string, err error) {
+	f.m.Lock()
+	defer f.m.Unlock()
+
+	f.errs[method] = err
+}
+
+// Rules returns the current NAT and blocking rules
+func (f *Firewall) Rules() *model.FirewallRules {
+	f.m.Lock()
+	defer f.m.Unlock()
+
+	return &model.FirewallRules{
+		NAT:   slices.Clone(f.nat),
+		Block: slices.Clone(f.block),
+	}
+}
+
+// SetForwardRules adds or removes the forward rules of the interface
+func (f *Firewall) SetForwardRules(exist bool) {
+	f.m.Lock()
+	defer f.m.Unlock()
+
+	f.forward = exist
+}
+
+func (f *Firewall) EnsureForwardRules() error {
+	f.m.Lock()
+	defer f.m.Unlock()
+
+	if err := f.errs["EnsureForwardRules"]; err != nil {
+		return err
+	}
+	f.forward = true
+	return nil
+}
+
+func (f *Firewall) CheckForwardRules() error {
+	f.m.Lock()
+	defer f.m.Unlock()
+
+	if err := f.errs["CheckForwardRules"]; err != nil {
+		return err
+	}
+	if !f.forward {
+		return appError.ErrIptables.WithMessage("Forward rule is missing").Err()
+	}
+	return nil
+}
+
+func (f *Firewall) AddNATRule(id, ip, destCidr string) error {
+	return f.AddNATRules([]model.FirewallRule{{ID: id, Source: ip, Destination: destCidr}})
+}
+
+// AddNATRules appends the rules with the source and destination as the firewall lists them
+func (f *Firewall) AddNATRules(rules []model.FirewallRule) error {
+	f.m.Lock()
+	defer f.m.Unlock()
+
+	if err := f.errs["AddNATRules"]; err != nil {
+		return err
+	}
+	for _, r := range rules {
+		f.nat = append(f.nat, natRule(r.ID, r.Source, r.Destination))
+	}
+	return nil
+}
+
+func (f *Firewall) DeleteNATRule(id, ip, destCidr string) error {
+	f.m.Lock()
+	defer f.m.Unlock()
+
+	if err := f.errs["DeleteNATRule"]; err != nil {
+		return err
+	}
+	return deleteRule(&f.nat, natRule(id, ip, destCidr))
+}
+
+func (f *Firewall) AddBlockRule(id, ip string) error {
+	f.m.Lock()
+	defer f.m.Unlock()
+
+	if err := f.errs["AddBlockRule"]; err != nil {
+		return err
+	}
+	f.block = append(f.block, blockRule(id, ip))
+	return nil
+}
+
+func (f *Firewall) DeleteBlockRule(id, ip string) error {
+	f.m.Lock()
+	defer f.m.Unlock()
+
+	if err := f.errs["DeleteBlockRule"]; err != nil {
+		return err
+	}
+	return deleteRule(&f.block, blockRule(id, ip))
+}
+
+func (f *Firewall) GetRules() (*model.FirewallRules, error) {
+	f.m.Lock()
+	err := f.errs["GetRules"]
+	f.m.Unlock()
+
+	if err != nil {
+		return nil, err
+	}
+	return f.Rules(), nil
+}
+
+func (f *Firewall) DeleteNATRuleSpec(r model.FirewallRule) error {
+	f.m.Lock()
+	defer f.m.Unlock()
+
+	if err := f.errs["DeleteNATRuleSpec"]; err != nil {
+		return err
+	}
+	return deleteRule(&f.nat, r)
+}
+
+func (f *Firewall) DeleteRules(ctx context.Context) error {
+	f.m.Lock()
+	defer f.m.Unlock()
+
+	if err := f.errs["DeleteRules"]; err != nil {
+		return err
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	f.nat, f.block = nil, nil
+	return nil
+}
+
+func (f *Firewall) DeleteBlockRuleSpec(r model.FirewallRule) error {
+	f.m.Lock()
+	defer f.m.Unlock()
+
+	if err := f.errs["DeleteBlockRuleSpec"]; err != nil {
+		return err
+	}
+	return deleteRule(&f.block, r)
+}
+
+func (f *Firewall) DescribeNATRule(action, id, ip, destCidr string) string {
+	return fmt.Sprintf("%s nat %s -> %s client %s", action, ip, destCidr, id)
+}
+
+func (f *Firewall) DescribeBlockRule(action, id, ip string) string {
+	return fmt.Sprintf("%s block %s client %s", action, ip, id)
+}
+
+// natRule returns the rule as the firewall lists it, the addresses are listed as the masked prefixes
+func natRule(id, ip, destCidr string) model.FirewallRule {
+	r := model.FirewallRule{ID: id, Source: normalizePrefix(ip), Destination: normalizePrefix(destCidr)}
+	r.Spec = fmt.Sprintf("POSTROUTING -s %s -d %s client %s", r.Source, r.Destination, r.ID)
+	return r
+}
+
+func blockRule(id, ip string) model.FirewallRule {
+	r := model.FirewallRule{ID: id, Source: normalizePrefix(ip)}
+	r.Spec = fmt.Sprintf("FORWARD -s %s ban client %s", r.Source, r.ID)
+	return r
+}
+
+// deleteRule deletes the first rule with the same spec, the missing rule fails as iptables -D does
+func deleteRule(rules *[]model.FirewallRule, r model.FirewallRule) error {
+	i := slices.IndexFunc(*rules, func(e model.FirewallRule) bool { return e.Spec == r.Spec })
+	if i < 0 {
+		return appError.ErrIptables.WithMessage("Rule does not exist").WithContext("rule", r.Spec).Err()
+	}
+	*rules = slices.Delete(*rules, i, i+1)
+	return nil
+}
+
+func normalizePrefix(prefix string) string {
+	p, err := netip.ParsePrefix(prefix)
+	if err != nil {
+		if addr, err := netip.ParseAddr(prefix); err == nil {
+			return netip.PrefixFrom(addr, addr.BitLen()).String()
+		}
+		return prefix
+	}
+	return p.Masked().String()
+}
@@ -0,0 +1,183 @@
+package wgtest
+
+import (
+	"context"
+	"github.com/cybericebox/lib/pkg/wgKeyGen"
+	"github.com/cybericebox/wireguard/internal/config"
+	grpcController "github.com/cybericebox/wireguard/internal/delivery/controller/grpc"
+	"github.com/cybericebox/wireguard/internal/service"
+	"github.com/cybericebox/wireguard/pkg/controller/grpc/client"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	"google.golang.org/grpc/test/bufconn"
+	"net"
+	"testing"
+	"time"
+)
+
+const (
+	// AuthKey and SignKey are the keys of the harness server, the clients of Harness.Client are authenticated with them
+	AuthKey = "wgtest-auth-key"
+	SignKey = "wgtest-sign-key"
+	// NodeID is the ID of the harness node, it owns all clients
+	NodeID = "wgtest"
+
+	bufferSize = 1 << 20
+)
+
+type (
+	// Harness is the gRPC server of the real service over the in-memory listener.
+	// The service runs on the in-memory repository, IPAM, peers and firewall, so no root, kernel module or Postgres is needed.
+	Harness struct {
+		Repository  *Repository
+		IPAManager  *IPAManager
+		PeerBackend *PeerBackend
+		Firewall    *Firewall
+		Webhooks    *WebhookSender
+		service     *service.Service
+		config      *config.Config
+
+		listener *bufconn.Listener
+		server   *grpc.Server
+	}
+)
+
+// NewHarness initializes the service as the application does and serves it until the test ends
+func NewHarness(t testing.TB) *Harness {
+	t.Helper()
+
+	cfg := &config.Config{
+		Service: config.ServiceConfig{
+			VPN: config.VPNConfig{
+				Endpoint:              "vpn.wgtest:51820",
+				CIDR:                  "10.128.0.0/16",
+				Port:                  "51820",
+				ReconcileInterval:     time.Minute,
+				HandshakeSyncInterval: 30 * time.Second,
+				SessionSampleInterval: 30 * time.Second,
+				SessionTimeout:        3 * time.Minute,
+				// the webhooks are disabled until a test sets the URLs
+				Webhooks: config.WebhooksConfig{
+					Secret:       "wgtest-webhook-secret",
+					Interval:     time.Second,
+					Timeout:      time.Second,
+					BatchSize:    100,
+					MaxAttempts:  3,
+					RetryBackoff: time.Second,
+					MaxBackoff:   time.Minute,
+				},
+				KeyPair: &wgKeyGen.KeyPair{},
+				Node: config.NodeConfig{
+					ID:                NodeID,
+					Assignment:        config.NodeAssignmentGroup,
+					HeartbeatInterval: 10 * time.Second,
+					HeartbeatTimeout:  30 * time.Second,
+				},
+			},
+		},
+		Controller: config.ControllerConfig{
+			GRPC: config.GRPCConfig{
+				ShutdownTimeout: time.Second,
+				Auth: config.AuthConfig{
+					Mode:        config.AuthModeJWT,
+					AuthKey:     AuthKey,
+					SignKey:     SignKey,
+					Audience:    client.DefaultAudience,
+					MaxTokenTTL: time.Hour,
+				},
+			},
+		},
+	}
+
+	ipaManager, err := NewIPAManager(cfg.Service.VPN.CIDR)
+	if err != nil {
+		t.Fatalf("create IPAManager: %v", err)
+	}
+
+	h := &Harness{
+		Repository:  NewRepository(),
+		IPAManager:  ipaManager,
+		PeerBackend: NewPeerBackend(),
+		Firewall:    NewFirewall(),
+		Webhooks:    NewWebhookSender(),
+		config:      cfg,
+		listener:    bufconn.Listen(bufferSize),
+	}
+
+	h.service = service.NewService(service.Dependencies{
+		Repository:    h.Repository,
+		IPAManager:    h.IPAManager,
+		PeerBackend:   h.PeerBackend,
+		Firewall:      h.Firewall,
+		WebhookSender: h.Webhooks,
+		KeyGenerator:  wgKeyGen.NewKeyGenerator(),
+		Config:        &cfg.Service.VPN,
+	})
+
+	ctx := context.Background()
+	if err = h.service.InitServer(ctx); err != nil {
+		t.Fatalf("init server: %v", err)
+	}
+	if err = h.service.InitServerClients(ctx); err != nil {
+		t.Fatalf("init server clients: %v", err)
+	}
+	if err = h.service.InitNode(ctx); err != nil {
+		t.Fatalf("init node: %v", err)
+	}
+
+	if h.server, err = grpcController.New(grpcController.Dependencies{
+		Config:  &cfg.Controller.GRPC,
+		Service: h.service,
+		Health:  health.NewServer(),
+	}); err != nil {
+		t.Fatalf("create gRPC server: %v", err)
+	}
+
+	go func() {
+		// Serve returns after the server is stopped
+		_ = h.server.Serve(h.listener)
+	}()
+	t.Cleanup(h.server.Stop)
+
+	return h
+}
+
+// SetWebhookURLs enables the webhooks of the URLs, it must be called before the service is used
+func (h *Harness) SetWebhookURLs(urls ...string) {
+	h.config.Service.VPN.Webhooks.URLs = urls
+}
+
+// Sample runs one sample of the session sampler, it samples the sessions of the peers and enforces the client limits
+func (h *Harness) Sample(ctx context.Context) error {
+	if err := h.service.SampleSessions(ctx); err != nil {
+		return err
+	}
+	return h.service.EnforceClientLimits(ctx)
+}
+
+// Client returns the client of the harness server authenticated with the roles, it is scoped to the group if groupID is set
+func (h *Harness) Client(t testing.TB, roles []string, groupID string) client.WireguardClient {
+	t.Helper()
+
+	c, err := client.NewWireguardConnection(client.Config{
+		Endpoint: "passthrough:///bufnet",
+		Auth: client.Auth{
+			AuthKey: AuthKey,
+			SignKey: SignKey,
+			Subject: t.Name(),
+			Roles:   roles,
+			GroupID: groupID,
+		},
+		DialOptions: []grpc.DialOption{
+			grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+				return h.listener.DialContext(ctx)
+			}),
+		},
+	})
+	if err != nil {
+		t.Fatalf("connect to harness: %v", err)
+	}
+	t.Cleanup(func() { _ = c.Close() })
+
+	return c
+}
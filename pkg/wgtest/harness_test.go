@@ -0,0 +1,138 @@
+package wgtest_test
+
+import (
+	"context"
+	"github.com/cybericebox/wireguard/pkg/controller/grpc/client"
+	"github.com/cybericebox/wireguard/pkg/controller/grpc/protobuf"
+	"github.com/cybericebox/wireguard/pkg/wgtest"
+	"github.com/gofrs/uuid"
+	"os"
+	"testing"
+)
+
+// TestMain runs the tests from the module root as the application runs,
+// the errors trim the working directory from the paths of the files they are created in
+func TestMain(m *testing.M) {
+	if err := os.Chdir("../.."); err != nil {
+		panic(err)
+	}
+	os.Exit(m.Run())
+}
+
+func TestHarnessClientLifecycle(t *testing.T) {
+	h := wgtest.NewHarness(t)
+	c := h.Client(t, []string{client.RoleAdmin}, "")
+	ctx := context.Background()
+
+	groupID := uuid.Must(uuid.NewV4()).String()
+	first, second := uuid.Must(uuid.NewV4()).String(), uuid.Must(uuid.NewV4()).String()
+
+	resp, err := c.ProvisionClients(ctx, &protobuf.ProvisionClientsRequest{Clients: []*protobuf.ProvisionClient{
+		{UserID: first, GroupID: groupID, DestCIDR: "10.0.0.0/24"},
+		{UserID: second, GroupID: groupID, DestCIDR: "10.0.0.0/24"},
+	}})
+	if err != nil {
+		t.Fatalf("provision clients: %v", err)
+	}
+	for _, r := range resp.GetClients() {
+		if r.GetError() != "" || r.GetConfig() == "" {
+			t.Fatalf("client %s is not provisioned: %s", r.GetUserID(), r.GetError())
+		}
+	}
+	if peers := h.PeerBackend.Peers(); len(peers) != 2 {
+		t.Fatalf("expected 2 peers, got %d", len(peers))
+	}
+	if rows := h.Repository.Clients(); len(rows) != 2 {
+		t.Fatalf("expected 2 stored clients, got %d", len(rows))
+	}
+
+	banned, err := c.BanClients(ctx, &protobuf.ClientsRequest{UserID: first, GroupID: groupID, BanReason: "cheating"})
+	if err != nil {
+		t.Fatalf("ban client: %v", err)
+	}
+	if banned.GetClientsAffected() != 1 {
+		t.Fatalf("expected 1 banned client, got %d", banned.GetClientsAffected())
+	}
+	if rules := h.Firewall.Rules(); len(rules.Block) != 1 {
+		t.Fatalf("expected 1 block rule, got %d", len(rules.Block))
+	}
+	for _, row := range h.Repository.Clients() {
+		if row.Banned != (row.UserID.String() == first) {
+			t.Fatalf("client %s has ban status %t", row.UserID, row.Banned)
+		}
+	}
+
+	unbanned, err := c.UnBanClients(ctx, &protobuf.ClientsRequest{UserID: first, GroupID: groupID})
+	if err != nil {
+		t.Fatalf("unban client: %v", err)
+	}
+	if unbanned.GetClientsAffected() != 1 {
+		t.Fatalf("expected 1 unbanned client, got %d", unbanned.GetClientsAffected())
+	}
+	if rules := h.Firewall.Rules(); len(rules.Block) != 0 {
+		t.Fatalf("expected no block rules, got %d", len(rules.Block))
+	}
+
+	deleted, err := c.DeleteClients(ctx, &protobuf.ClientsRequest{GroupID: groupID})
+	if err != nil {
+		t.Fatalf("delete clients: %v", err)
+	}
+	if deleted.GetClientsAffected() != 2 {
+		t.Fatalf("expected 2 deleted clients, got %d", deleted.GetClientsAffected())
+	}
+	if peers := h.PeerBackend.Peers(); len(peers) != 0 {
+		t.Fatalf("expected no peers, got %d", len(peers))
+	}
+	if rules := h.Firewall.Rules(); len(rules.NAT) != 0 {
+		t.Fatalf("expected no NAT rules, got %d", len(rules.NAT))
+	}
+	if rows := h.Repository.Clients(); len(rows) != 0 {
+		t.Fatalf("expected no stored clients, got %d", len(rows))
+	}
+	// only the server address stays acquired
+	if acquired := h.IPAManager.Acquired(); len(acquired) != 1 {
+		t.Fatalf("expected 1 acquired address, got %d", len(acquired))
+	}
+}
+
+func TestHarnessClientRoles(t *testing.T) {
+	h := wgtest.NewHarness(t)
+	ctx := context.Background()
+
+	groupID, otherGroupID := uuid.Must(uuid.NewV4()).String(), uuid.Must(uuid.NewV4()).String()
+	userID := uuid.Must(uuid.NewV4()).String()
+
+	admin := h.Client(t, []string{client.RoleAdmin}, "")
+	if _, err := admin.GetClientConfig(ctx, &protobuf.ClientConfigRequest{UserID: userID, GroupID: groupID, DestCIDR: "10.0.0.0/24"}); err != nil {
+		t.Fatalf("get client config: %v", err)
+	}
+
+	viewer := h.Client(t, []string{client.RoleViewer}, "")
+	operator := h.Client(t, []string{client.RoleOperator}, groupID)
+	request := &protobuf.ClientsRequest{UserID: userID, GroupID: groupID}
+
+	if _, err := viewer.GetClients(ctx, &protobuf.GetClientsRequest{GroupID: groupID}); err != nil {
+		t.Fatalf("viewer gets clients: %v", err)
+	}
+	if _, err := viewer.BanClients(ctx, request); err == nil {
+		t.Fatal("viewer banned the client")
+	}
+	if _, err := operator.BanClients(ctx, request); err != nil {
+		t.Fatalf("operator bans the client of its group: %v", err)
+	}
+	if _, err := operator.UnBanClients(ctx, &protobuf.ClientsRequest{UserID: userID, GroupID: otherGroupID}); err == nil {
+		t.Fatal("operator unbanned the client of another group")
+	}
+	if _, err := operator.DeleteClients(ctx, request); err == nil {
+		t.Fatal("operator deleted the client")
+	}
+	if _, err := operator.GetAuditLog(ctx, &protobuf.AuditLogRequest{UserID: userID, GroupID: groupID}); err == nil {
+		t.Fatal("operator read the audit log")
+	}
+
+	// the rejected requests leave the client as the operator banned it
+	rows := h.Repository.Clients()
+	if len(rows) != 1 || !rows[0].Banned {
+		t.Fatalf("expected the banned client, got %+v", rows)
+	}
+}
@@ -0,0 +1,130 @@
+package wgtest
+
+import (
+	"context"
+	"github.com/cybericebox/wireguard/pkg/appError"
+	"net/netip"
+	"slices"
+	"sync"
+)
+
+type (
+	// IPAManager is the in-memory manager of the addresses of the IPv4 CIDR.
+	// The free addresses are acquired from the lowest one, the network and broadcast addresses are never acquired.
+	IPAManager struct {
+		m        sync.Mutex
+		prefix   netip.Prefix
+		acquired map[netip.Addr]struct{}
+	}
+)
+
+func NewIPAManager(cidr string) (*IPAManager, error) {
+	prefix, err := netip.ParsePrefix(cidr)
+	if err != nil {
+		return nil, appError.ErrIPAM.WithError(err).WithMessage("Failed to parse CIDR").Err()
+	}
+	if !prefix.Addr().Is4() {
+		return nil, appError.ErrIPAM.WithMessage("Only IPv4 CIDR is supported").WithContext("cidr", cidr).Err()
+	}
+
+	return &IPAManager{
+		prefix:   prefix.Masked(),
+		acquired: make(map[netip.Addr]struct{}),
+	}, nil
+}
+
+// Acquired returns the acquired addresses in ascending order
+func (m *IPAManager) Acquired() []string {
+	m.m.Lock()
+	defer m.m.Unlock()
+
+	addrs := make([]netip.Addr, 0, len(m.acquired))
+	for addr := range m.acquired {
+		addrs = append(addrs, addr)
+	}
+	slices.SortFunc(addrs, netip.Addr.Compare)
+
+	acquired := make([]string, 0, len(addrs))
+	for _, addr := range addrs {
+		acquired = append(acquired, addr.String())
+	}
+	return acquired
+}
+
+func (m *IPAManager) AcquireSingleIP(_ context.Context, specificIP ...string) (string, error) {
+	m.m.Lock()
+	defer m.m.Unlock()
+
+	if len(specificIP) > 0 {
+		addr, err := m.parse(specificIP[0])
+		if err != nil {
+			return "", err
+		}
+
+		// the acquiring of the acquired address succeeds, as in the postgres manager
+		m.acquired[addr] = struct{}{}
+		return specificIP[0], nil
+	}
+
+	return m.acquireLowest()
+}
+
+func (m *IPAManager) AcquireIPs(_ context.Context, count int) ([]string, error) {
+	m.m.Lock()
+	defer m.m.Unlock()
+
+	ips := make([]string, 0, count)
+	for range count {
+		ip, err := m.acquireLowest()
+		if err != nil {
+			return ips, err
+		}
+		ips = append(ips, ip)
+	}
+	return ips, nil
+}
+
+func (m *IPAManager) acquireLowest() (string, error) {
+	for addr := m.prefix.Addr().Next(); m.prefix.Contains(addr.Next()); addr = addr.Next() {
+		if _, ok := m.acquired[addr]; !ok {
+			m.acquired[addr] = struct{}{}
+			return addr.String(), nil
+		}
+	}
+
+	return "", appError.ErrIPAMNoFreeIP.WithContext("cidr", m.prefix.String()).Err()
+}
+
+func (m *IPAManager) ReleaseSingleIP(_ context.Context, ip string) error {
+	m.m.Lock()
+	defer m.m.Unlock()
+
+	addr, err := m.parse(ip)
+	if err != nil {
+		return err
+	}
+
+	delete(m.acquired, addr)
+	return nil
+}
+
+func (m *IPAManager) GetFirstIP() (string, error) {
+	return m.prefix.Addr().Next().String(), nil
+}
+
+func (m *IPAManager) GetCIDR() string {
+	return m.prefix.String()
+}
+
+func (m *IPAManager) parse(ip string) (netip.Addr, error) {
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return netip.Addr{}, appError.ErrIPAM.WithError(err).WithMessage("Failed to parse IP").Err()
+	}
+
+	addr = addr.Unmap()
+	if !m.prefix.Contains(addr) {
+		return netip.Addr{}, appError.ErrIPAMIPOutOfRange.WithContext("ip", ip).WithContext("cidr", m.prefix.String()).Err()
+	}
+	return addr, nil
+}
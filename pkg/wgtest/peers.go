@@ -0,0 +1,188 @@
+package wgtest
+
+import (
+	"github.com/cybericebox/wireguard/internal/model"
+	"github.com/cybericebox/wireguard/pkg/appError"
+	"maps"
+	"sync"
+	"time"
+)
+
+type (
+	// PeerBackend is the in-memory interface with its peers.
+	// The errors set by SetError are returned by the methods of the same name, so the failures of the kernel can be tested.
+	PeerBackend struct {
+		m      sync.Mutex
+		exists bool
+		up     bool
+		peers  map[string]model.Peer
+		errs   map[string]error
+	}
+)
+
+func NewPeerBackend() *PeerBackend {
+	return &PeerBackend{
+		peers: make(map[string]model.Peer),
+		errs:  make(map[string]error),
+	}
+}
+
+// SetError makes the method fail with the error, nil makes it succeed again
+func (b *PeerBackend) SetError(method string, err error) {
+	b.m.Lock()
+	defer b.m.Unlock()
+
+	b.errs[method] = err
+}
+
+// Peers returns the current peers of the interface map[publicKey]peer
+func (b *PeerBackend) Peers() map[string]model.Peer {
+	b.m.Lock()
+	defer b.m.Unlock()
+
+	return maps.Clone(b.peers)
+}
+
+// SetHandshake records the handshake and the traffic of the peer, as if the client connected from the endpoint
+func (b *PeerBackend) SetHandshake(publicKey, endpoint string, handshake time.Time, rx, tx int64) {
+	b.m.Lock()
+	defer b.m.Unlock()
+
+	p, ok := b.peers[publicKey]
+	if !ok {
+		return
+	}
+	p.Endpoint = endpoint
+	p.LatestHandshake = int(handshake.Unix())
+	p.TransferRx, p.TransferTx = rx, tx
+	b.peers[publicKey] = p
+}
+
+// SetInterfaceUp brings the interface up or down without removing it
+func (b *PeerBackend) SetInterfaceUp(up bool) {
+	b.m.Lock()
+	defer b.m.Unlock()
+
+	b.up = up
+}
+
+func (b *PeerBackend) InterfaceExists() (bool, error) {
+	b.m.Lock()
+	defer b.m.Unlock()
+
+	return b.exists, b.errs["InterfaceExists"]
+}
+
+func (b *PeerBackend) CreateInterface() error {
+	b.m.Lock()
+	defer b.m.Unlock()
+
+	if err := b.errs["CreateInterface"]; err != nil {
+		return err
+	}
+	b.exists, b.up = true, true
+	return nil
+}
+
+func (b *PeerBackend) AdoptInterface() error {
+	b.m.Lock()
+	defer b.m.Unlock()
+
+	if err := b.errs["AdoptInterface"]; err != nil {
+		return err
+	}
+	b.up = true
+	return nil
+}
+
+// DownInterface removes the interface, the peers are removed with it
+func (b *PeerBackend) DownInterface() error {
+	b.m.Lock()
+	defer b.m.Unlock()
+
+	if err := b.errs["DownInterface"]; err != nil {
+		return err
+	}
+	b.exists, b.up = false, false
+	clear(b.peers)
+	return nil
+}
+
+func (b *PeerBackend) CheckInterface() error {
+	b.m.Lock()
+	defer b.m.Unlock()
+
+	if err := b.errs["CheckInterface"]; err != nil {
+		return err
+	}
+	if !b.exists || !b.up {
+		return appError.ErrWireguard.WithMessage("Interface is down").Err()
+	}
+	return nil
+}
+
+func (b *PeerBackend) CheckListenPort() error {
+	b.m.Lock()
+	defer b.m.Unlock()
+
+	return b.errs["CheckListenPort"]
+}
+
+func (b *PeerBackend) GetPeers() (map[string]model.Peer, error) {
+	b.m.Lock()
+	defer b.m.Unlock()
+
+	if err := b.errs["GetPeers"]; err != nil {
+		return nil, err
+	}
+	return maps.Clone(b.peers), nil
+}
+
+func (b *PeerBackend) AddPeer(ip, publicKey string) error {
+	return b.AddPeers([]model.Peer{{PublicKey: publicKey, AllowedIPs: ip}})
+}
+
+func (b *PeerBackend) AddPeers(peers []model.Peer) error {
+	b.m.Lock()
+	defer b.m.Unlock()
+
+	if err := b.errs["AddPeers"]; err != nil {
+		return err
+	}
+	if !b.exists {
+		return appError.ErrWireguard.WithMessage("Interface does not exist").Err()
+	}
+
+	for _, p := range peers {
+		// the existing peer keeps its handshake and traffic, as wg set does
+		existing, ok := b.peers[p.PublicKey]
+		if !ok {
+			existing = model.Peer{PublicKey: p.PublicKey, Endpoint: "(none)"}
+		}
+		existing.AllowedIPs = p.AllowedIPs
+		b.peers[p.PublicKey] = existing
+	}
+	return nil
+}
+
+func (b *PeerBackend) DeletePeer(_, publicKey string) error {
+	b.m.Lock()
+	defer b.m.Unlock()
+
+	if err := b.errs["DeletePeer"]; err != nil {
+		return err
+	}
+	delete(b.peers, publicKey)
+	return nil
+}
+
+func (b *PeerBackend) DeleteStalePeer(p model.Peer) error {
+	b.m.Lock()
+	defer b.m.Unlock()
+
+	if err := b.errs["DeleteStalePeer"]; err != nil {
+		return err
+	}
+	delete(b.peers, p.PublicKey)
+	return nil
+}
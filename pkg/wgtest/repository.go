@@ -0,0 +1,802 @@
+package wgtest
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"github.com/cybericebox/wireguard/internal/delivery/repository/postgres"
+	"github.com/cybericebox/wireguard/internal/model"
+	"github.com/gofrs/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"maps"
+	"net/netip"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+type (
+	// Repository is the in-memory repository of the service, it returns pgx.ErrNoRows for the missing rows as the postgres one does
+	Repository struct {
+		m        sync.Mutex
+		clients  map[clientKey]postgres.VpnClient
+		nodes    map[string]postgres.VpnNode
+		settings map[string][]byte
+		auditLog []postgres.AuditLog
+		records  map[uuid.UUID]postgres.VpnDnsRecord
+		sessions []postgres.VpnSession
+		// deliveries are the webhook deliveries by position, their IDs are the positions
+		deliveries []postgres.VpnWebhookDelivery
+		// pingErr is returned by Ping, it fails the database health check
+		pingErr error
+		// deliveryErr is returned by CreateVPNWebhookDelivery, it fails the transaction of the change the event is queued with
+		deliveryErr error
+		// tx serializes the transactions
+		tx sync.Mutex
+	}
+
+	clientKey struct {
+		userID  uuid.UUID
+		groupID uuid.UUID
+	}
+)
+
+func NewRepository() *Repository {
+	return &Repository{
+		clients:  make(map[clientKey]postgres.VpnClient),
+		nodes:    make(map[string]postgres.VpnNode),
+		settings: make(map[string][]byte),
+		records:  make(map[uuid.UUID]postgres.VpnDnsRecord),
+	}
+}
+
+// Clients returns all stored clients ordered by user and group
+func (r *Repository) Clients() []postgres.VpnClient {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	clients := make([]postgres.VpnClient, 0, len(r.clients))
+	for _, c := range r.clients {
+		clients = append(clients, c)
+	}
+	slices.SortFunc(clients, func(a, b postgres.VpnClient) int {
+		if c := bytes.Compare(a.UserID.Bytes(), b.UserID.Bytes()); c != 0 {
+			return c
+		}
+		return bytes.Compare(a.GroupID.Bytes(), b.GroupID.Bytes())
+	})
+	return clients
+}
+
+// AuditLog returns all audit log entries in the order they were created
+func (r *Repository) AuditLog() []postgres.AuditLog {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	return slices.Clone(r.auditLog)
+}
+
+// Sessions returns all stored sessions in the order they were started by the service
+func (r *Repository) Sessions() []postgres.VpnSession {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	return slices.Clone(r.sessions)
+}
+
+// WebhookDeliveries returns all webhook deliveries in the order they were queued
+func (r *Repository) WebhookDeliveries() []postgres.VpnWebhookDelivery {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	return slices.Clone(r.deliveries)
+}
+
+// SetPingError makes Ping fail with the error, nil makes it succeed again
+func (r *Repository) SetPingError(err error) {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	r.pingErr = err
+}
+
+// SetWebhookDeliveryError makes CreateVPNWebhookDelivery fail with the error, nil makes it succeed again
+func (r *Repository) SetWebhookDeliveryError(err error) {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	r.deliveryErr = err
+}
+
+// InTx runs the function with the repository and restores the clients, the sessions and the webhook deliveries if it fails.
+// The transactions are serialized, the changes outside them are not isolated from them.
+func (r *Repository) InTx(_ context.Context, fn func(q postgres.TxQuerier) error) error {
+	r.tx.Lock()
+	defer r.tx.Unlock()
+
+	r.m.Lock()
+	clients, sessions, deliveries := maps.Clone(r.clients), slices.Clone(r.sessions), slices.Clone(r.deliveries)
+	r.m.Unlock()
+
+	if err := fn(r); err != nil {
+		r.m.Lock()
+		r.clients, r.sessions, r.deliveries = clients, sessions, deliveries
+		r.m.Unlock()
+		return err
+	}
+	return nil
+}
+
+func (r *Repository) CreateVpnClient(ctx context.Context, arg postgres.CreateVpnClientParams) error {
+	_, err := r.CreateVpnClients(ctx, []postgres.CreateVpnClientsParams{postgres.CreateVpnClientsParams(arg)})
+	return err
+}
+
+// CreateVpnClients stores either all or none of the clients, as the postgres copy does
+func (r *Repository) CreateVpnClients(_ context.Context, arg []postgres.CreateVpnClientsParams) (int64, error) {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	for i, c := range arg {
+		key := clientKey{userID: c.UserID, groupID: c.GroupID}
+		if _, ok := r.clients[key]; ok || slices.ContainsFunc(arg[:i], func(p postgres.CreateVpnClientsParams) bool {
+			return p.UserID == c.UserID && p.GroupID == c.GroupID
+		}) {
+			return 0, fmt.Errorf("client %s-%s already exists", c.UserID, c.GroupID)
+		}
+	}
+
+	createdAt := time.Now()
+	for _, c := range arg {
+		r.clients[clientKey{userID: c.UserID, groupID: c.GroupID}] = postgres.VpnClient{
+			UserID:         c.UserID,
+			GroupID:        c.GroupID,
+			IpAddress:      c.IpAddress,
+			PublicKey:      c.PublicKey,
+			PrivateKey:     c.PrivateKey,
+			LaboratoryCidr: c.LaboratoryCidr,
+			CreatedAt:      createdAt,
+			NodeID:         c.NodeID,
+		}
+	}
+	return int64(len(arg)), nil
+}
+
+func (r *Repository) GetNodeVPNClients(_ context.Context, nodeID string) ([]postgres.VpnClient, error) {
+	var items []postgres.VpnClient
+	for _, c := range r.Clients() {
+		if c.NodeID == nodeID {
+			items = append(items, c)
+		}
+	}
+	return items, nil
+}
+
+func (r *Repository) GetVPNClient(_ context.Context, arg postgres.GetVPNClientParams) (postgres.VpnClient, error) {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	c, ok := r.clients[clientKey{userID: arg.UserID, groupID: arg.GroupID}]
+	if !ok {
+		return postgres.VpnClient{}, pgx.ErrNoRows
+	}
+	return c, nil
+}
+
+// ListVPNClients returns the page of the clients matching the filters in the order of the postgres query
+func (r *Repository) ListVPNClients(_ context.Context, arg postgres.ListVPNClientsParams) ([]postgres.ListVPNClientsRow, error) {
+	var items []postgres.ListVPNClientsRow
+	for _, c := range r.Clients() {
+		switch {
+		case arg.UserID.Valid && c.UserID != arg.UserID.UUID,
+			arg.GroupID.Valid && c.GroupID != arg.GroupID.UUID,
+			arg.Banned.Valid && c.Banned != arg.Banned.Bool,
+			arg.OnlineSince.Valid && (!c.LastHandshakeAt.Valid || c.LastHandshakeAt.Time.Before(arg.OnlineSince.Time)),
+			arg.AddressCidr != nil && !arg.AddressCidr.Masked().Contains(c.IpAddress.Addr()),
+			arg.CreatedAfter.Valid && !c.CreatedAt.After(arg.CreatedAfter.Time):
+			continue
+		}
+
+		items = append(items, postgres.ListVPNClientsRow{
+			UserID:          c.UserID,
+			GroupID:         c.GroupID,
+			IpAddress:       c.IpAddress,
+			Banned:          c.Banned,
+			CreatedAt:       c.CreatedAt,
+			NodeID:          c.NodeID,
+			LastHandshakeAt: c.LastHandshakeAt,
+			BanReason:       c.BanReason,
+		})
+	}
+
+	// the clients are already ordered by user and group, the stable sort keeps it for the equal keys
+	compare := func(a, b postgres.ListVPNClientsRow) int { return 0 }
+	switch arg.SortBy {
+	case model.ClientsSortByLastSeen:
+		// the missing handshakes are first in the ascending order and last in the descending one
+		compare = func(a, b postgres.ListVPNClientsRow) int {
+			return compareTimestamptz(a.LastHandshakeAt, b.LastHandshakeAt)
+		}
+	case model.ClientsSortByAddress:
+		compare = func(a, b postgres.ListVPNClientsRow) int {
+			return a.IpAddress.Addr().Compare(b.IpAddress.Addr())
+		}
+	case model.ClientsSortByCreated:
+		compare = func(a, b postgres.ListVPNClientsRow) int {
+			return a.CreatedAt.Compare(b.CreatedAt)
+		}
+	}
+	slices.SortStableFunc(items, func(a, b postgres.ListVPNClientsRow) int {
+		if arg.SortDesc {
+			return compare(b, a)
+		}
+		return compare(a, b)
+	})
+
+	offset := min(int(arg.PageOffset), len(items))
+	end := min(offset+int(arg.PageSize), len(items))
+	return items[offset:end], nil
+}
+
+func compareTimestamptz(a, b pgtype.Timestamptz) int {
+	switch {
+	case !a.Valid && !b.Valid:
+		return 0
+	case !a.Valid:
+		return -1
+	case !b.Valid:
+		return 1
+	}
+	return a.Time.Compare(b.Time)
+}
+
+func (r *Repository) GetVPNClientNodeID(ctx context.Context, arg postgres.GetVPNClientNodeIDParams) (string, error) {
+	c, err := r.GetVPNClient(ctx, postgres.GetVPNClientParams(arg))
+	if err != nil {
+		return "", err
+	}
+	return c.NodeID, nil
+}
+
+// GetVPNGroupNodeID returns the node with the most clients of the group
+func (r *Repository) GetVPNGroupNodeID(_ context.Context, groupID uuid.UUID) (string, error) {
+	counts := make(map[string]int)
+	for _, c := range r.Clients() {
+		if c.GroupID == groupID {
+			counts[c.NodeID]++
+		}
+	}
+
+	nodeID, most := "", 0
+	for id, count := range counts {
+		if count > most || count == most && id < nodeID {
+			nodeID, most = id, count
+		}
+	}
+	if most == 0 {
+		return "", pgx.ErrNoRows
+	}
+	return nodeID, nil
+}
+
+func (r *Repository) GetVPNGroupLaboratoryCIDRs(_ context.Context, groupID uuid.UUID) ([]netip.Prefix, error) {
+	var items []netip.Prefix
+	for _, c := range r.Clients() {
+		if c.GroupID == groupID && !slices.Contains(items, c.LaboratoryCidr) {
+			items = append(items, c.LaboratoryCidr)
+		}
+	}
+	return items, nil
+}
+
+func (r *Repository) UpdateVPNClientsBanStatus(_ context.Context, arg postgres.UpdateVPNClientsBanStatusParams) (int64, error) {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	var updated int64
+	for key, c := range r.clients {
+		if !matchClient(c, arg.NodeID, arg.UserID, arg.GroupID) || c.Banned == arg.Banned {
+			continue
+		}
+		c.Banned = arg.Banned
+		c.BanReason = arg.BanReason
+		c.UpdatedAt = pgtype.Timestamptz{Time: time.Now(), Valid: true}
+		r.clients[key] = c
+		updated++
+	}
+	return updated, nil
+}
+
+func (r *Repository) UpdateVPNClientsLimits(_ context.Context, arg postgres.UpdateVPNClientsLimitsParams) (int64, error) {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	var updated int64
+	for key, c := range r.clients {
+		if !matchClient(c, arg.NodeID, arg.UserID, arg.GroupID) {
+			continue
+		}
+		c.ExpiresAt = arg.ExpiresAt
+		c.TransferQuota = arg.TransferQuota
+		c.UpdatedAt = pgtype.Timestamptz{Time: time.Now(), Valid: true}
+		r.clients[key] = c
+		updated++
+	}
+	return updated, nil
+}
+
+// GetVPNClientsTransfer returns the transfer of the node clients with the quota, it is the sum of their sessions since they were created
+func (r *Repository) GetVPNClientsTransfer(_ context.Context, nodeID string) ([]postgres.GetVPNClientsTransferRow, error) {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	var items []postgres.GetVPNClientsTransferRow
+	for key, c := range r.clients {
+		if c.NodeID != nodeID || c.TransferQuota <= 0 {
+			continue
+		}
+
+		row := postgres.GetVPNClientsTransferRow{UserID: key.userID, GroupID: key.groupID}
+		sessions := 0
+		for _, s := range r.sessions {
+			if s.UserID == key.userID && s.GroupID == key.groupID && !s.StartedAt.Before(c.CreatedAt.Truncate(time.Second)) {
+				row.Transfer += s.TransferRx + s.TransferTx
+				sessions++
+			}
+		}
+		// the clients without sessions are not joined
+		if sessions > 0 {
+			items = append(items, row)
+		}
+	}
+	return items, nil
+}
+
+func (r *Repository) UpdateVPNClientsLastHandshake(_ context.Context, arg postgres.UpdateVPNClientsLastHandshakeParams) (int64, error) {
+	if len(arg.PublicKeys) != len(arg.LastHandshakes) {
+		return 0, fmt.Errorf("got %d public keys and %d last handshakes", len(arg.PublicKeys), len(arg.LastHandshakes))
+	}
+
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	handshakes := make(map[string]time.Time, len(arg.PublicKeys))
+	for i, publicKey := range arg.PublicKeys {
+		handshakes[publicKey] = arg.LastHandshakes[i]
+	}
+
+	var updated int64
+	for key, c := range r.clients {
+		handshake, ok := handshakes[c.PublicKey]
+		if !ok || c.NodeID != arg.NodeID {
+			continue
+		}
+		c.LastHandshakeAt = pgtype.Timestamptz{Time: handshake, Valid: true}
+		r.clients[key] = c
+		updated++
+	}
+	return updated, nil
+}
+
+func (r *Repository) GetVPNClientsWithoutHandshake(_ context.Context, arg postgres.GetVPNClientsWithoutHandshakeParams) ([]postgres.GetVPNClientsWithoutHandshakeRow, error) {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	var items []postgres.GetVPNClientsWithoutHandshakeRow
+	for _, c := range r.clients {
+		if c.NodeID == arg.NodeID && !c.LastHandshakeAt.Valid && slices.Contains(arg.PublicKeys, c.PublicKey) {
+			items = append(items, postgres.GetVPNClientsWithoutHandshakeRow{
+				UserID:    c.UserID,
+				GroupID:   c.GroupID,
+				PublicKey: c.PublicKey,
+			})
+		}
+	}
+	return items, nil
+}
+
+func (r *Repository) DeleteVPNClients(_ context.Context, arg postgres.DeleteVPNClientsParams) (int64, error) {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	var deleted int64
+	for key, c := range r.clients {
+		if matchClient(c, arg.NodeID, arg.UserID, arg.GroupID) {
+			delete(r.clients, key)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+// matchClient reports whether the client of the node matches the user and group, the missing ones match any
+func matchClient(c postgres.VpnClient, nodeID string, userID, groupID uuid.NullUUID) bool {
+	return c.NodeID == nodeID &&
+		(!userID.Valid || c.UserID == userID.UUID) &&
+		(!groupID.Valid || c.GroupID == groupID.UUID)
+}
+
+func (r *Repository) Ping(_ context.Context) error {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	return r.pingErr
+}
+
+func (r *Repository) GetPlatformSettings(_ context.Context, key string) ([]byte, error) {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	value, ok := r.settings[key]
+	if !ok {
+		return nil, pgx.ErrNoRows
+	}
+	return slices.Clone(value), nil
+}
+
+func (r *Repository) CreatePlatformSettings(_ context.Context, arg postgres.CreatePlatformSettingsParams) error {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	if _, ok := r.settings[arg.Key]; ok {
+		return fmt.Errorf("platform settings %s already exist", arg.Key)
+	}
+	r.settings[arg.Key] = slices.Clone(arg.Value)
+	return nil
+}
+
+func (r *Repository) UpsertVPNNode(_ context.Context, arg postgres.UpsertVPNNodeParams) error {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	heartbeatAt := time.Now()
+	node, ok := r.nodes[arg.ID]
+	if !ok {
+		node.CreatedAt = heartbeatAt
+	}
+	node.ID = arg.ID
+	node.Endpoint = arg.Endpoint
+	node.PublicKey = arg.PublicKey
+	node.GrpcEndpoint = arg.GrpcEndpoint
+	node.HeartbeatAt = heartbeatAt
+	r.nodes[arg.ID] = node
+	return nil
+}
+
+func (r *Repository) UpdateVPNNodeHeartbeat(_ context.Context, id string) (int64, error) {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	node, ok := r.nodes[id]
+	if !ok {
+		return 0, nil
+	}
+	node.HeartbeatAt = time.Now()
+	r.nodes[id] = node
+	return 1, nil
+}
+
+func (r *Repository) GetVPNNode(_ context.Context, id string) (postgres.VpnNode, error) {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	node, ok := r.nodes[id]
+	if !ok {
+		return postgres.VpnNode{}, pgx.ErrNoRows
+	}
+	return node, nil
+}
+
+func (r *Repository) GetAliveVPNNodes(_ context.Context, heartbeatAt time.Time) ([]postgres.VpnNode, error) {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	var items []postgres.VpnNode
+	for _, node := range r.nodes {
+		if node.HeartbeatAt.After(heartbeatAt) {
+			items = append(items, node)
+		}
+	}
+	slices.SortFunc(items, func(a, b postgres.VpnNode) int {
+		return strings.Compare(a.ID, b.ID)
+	})
+	return items, nil
+}
+
+func (r *Repository) GetAliveVPNNodesLoad(ctx context.Context, heartbeatAt time.Time) ([]postgres.GetAliveVPNNodesLoadRow, error) {
+	nodes, err := r.GetAliveVPNNodes(ctx, heartbeatAt)
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[string]int64)
+	for _, c := range r.Clients() {
+		counts[c.NodeID]++
+	}
+
+	items := make([]postgres.GetAliveVPNNodesLoadRow, 0, len(nodes))
+	for _, node := range nodes {
+		items = append(items, postgres.GetAliveVPNNodesLoadRow{ID: node.ID, Clients: counts[node.ID]})
+	}
+	// the nodes are already ordered by ID
+	slices.SortStableFunc(items, func(a, b postgres.GetAliveVPNNodesLoadRow) int {
+		return int(a.Clients - b.Clients)
+	})
+	return items, nil
+}
+
+func (r *Repository) CreateAuditLogEntry(_ context.Context, arg postgres.CreateAuditLogEntryParams) error {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	parameters := slices.Clone(arg.Parameters)
+	if len(parameters) == 0 {
+		parameters = []byte("{}")
+	}
+
+	r.auditLog = append(r.auditLog, postgres.AuditLog{
+		ID:         int64(len(r.auditLog) + 1),
+		Subject:    arg.Subject,
+		Action:     arg.Action,
+		UserID:     arg.UserID,
+		GroupID:    arg.GroupID,
+		Parameters: parameters,
+		Outcome:    arg.Outcome,
+		Error:      arg.Error,
+		Affected:   arg.Affected,
+		NodeID:     arg.NodeID,
+		CreatedAt:  time.Now(),
+	})
+	return nil
+}
+
+// GetAuditLog returns the newest entries matching the filters first
+func (r *Repository) GetAuditLog(_ context.Context, arg postgres.GetAuditLogParams) ([]postgres.AuditLog, error) {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	var items []postgres.AuditLog
+	for i := len(r.auditLog) - 1; i >= 0 && len(items) < int(arg.RowLimit); i-- {
+		e := r.auditLog[i]
+		switch {
+		case arg.Subject != "" && e.Subject != arg.Subject,
+			arg.Action != "" && e.Action != arg.Action,
+			arg.UserID.Valid && e.UserID != arg.UserID,
+			arg.GroupID.Valid && e.GroupID != arg.GroupID,
+			e.CreatedAt.Before(arg.Since),
+			e.CreatedAt.After(arg.Until):
+			continue
+		}
+		items = append(items, e)
+	}
+	return items, nil
+}
+
+// CreateVPNDNSRecord stores the record, the existing record of the group, name and address is not stored again
+func (r *Repository) CreateVPNDNSRecord(_ context.Context, arg postgres.CreateVPNDNSRecordParams) (postgres.VpnDnsRecord, error) {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	for _, record := range r.records {
+		if record.GroupID == arg.GroupID && record.Name == arg.Name && record.Address == arg.Address {
+			return postgres.VpnDnsRecord{}, pgx.ErrNoRows
+		}
+	}
+
+	record := postgres.VpnDnsRecord{
+		ID:             arg.ID,
+		GroupID:        arg.GroupID,
+		Name:           arg.Name,
+		Address:        arg.Address,
+		LaboratoryCidr: arg.LaboratoryCidr,
+		CreatedAt:      time.Now(),
+	}
+	r.records[arg.ID] = record
+	return record, nil
+}
+
+// ListVPNDNSRecords returns the records of the group or all records if the group is not set, ordered by name and address
+func (r *Repository) ListVPNDNSRecords(_ context.Context, groupID uuid.NullUUID) ([]postgres.VpnDnsRecord, error) {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	var items []postgres.VpnDnsRecord
+	for _, record := range r.records {
+		if !groupID.Valid || record.GroupID == groupID.UUID {
+			items = append(items, record)
+		}
+	}
+	slices.SortFunc(items, func(a, b postgres.VpnDnsRecord) int {
+		if c := strings.Compare(a.Name, b.Name); c != 0 {
+			return c
+		}
+		return a.Address.Compare(b.Address)
+	})
+	return items, nil
+}
+
+func (r *Repository) DeleteVPNDNSRecord(_ context.Context, arg postgres.DeleteVPNDNSRecordParams) (postgres.VpnDnsRecord, error) {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	record, ok := r.records[arg.ID]
+	if !ok || record.GroupID != arg.GroupID {
+		return postgres.VpnDnsRecord{}, pgx.ErrNoRows
+	}
+	delete(r.records, arg.ID)
+	return record, nil
+}
+
+func (r *Repository) CreateVPNSession(_ context.Context, arg postgres.CreateVPNSessionParams) (int64, error) {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	id := int64(len(r.sessions) + 1)
+	r.sessions = append(r.sessions, postgres.VpnSession{
+		ID:           id,
+		UserID:       arg.UserID,
+		GroupID:      arg.GroupID,
+		NodeID:       arg.NodeID,
+		Endpoint:     arg.Endpoint,
+		TransferRx:   arg.TransferRx,
+		TransferTx:   arg.TransferTx,
+		StartedAt:    arg.StartedAt,
+		LastActiveAt: arg.LastActiveAt,
+	})
+	return id, nil
+}
+
+func (r *Repository) UpdateVPNSession(_ context.Context, arg postgres.UpdateVPNSessionParams) error {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	// the IDs are the positions of the sessions
+	if arg.ID < 1 || arg.ID > int64(len(r.sessions)) {
+		return nil
+	}
+
+	session := &r.sessions[arg.ID-1]
+	session.Endpoint = arg.Endpoint
+	session.TransferRx = arg.TransferRx
+	session.TransferTx = arg.TransferTx
+	session.LastActiveAt = arg.LastActiveAt
+	session.EndedAt = arg.EndedAt
+	return nil
+}
+
+func (r *Repository) EndVPNNodeSessions(_ context.Context, nodeID string) (int64, error) {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	var affected int64
+	for i := range r.sessions {
+		if s := &r.sessions[i]; s.NodeID == nodeID && !s.EndedAt.Valid {
+			s.EndedAt = pgtype.Timestamptz{Time: s.LastActiveAt, Valid: true}
+			affected++
+		}
+	}
+	return affected, nil
+}
+
+// GetVPNClientSessions returns the sessions of the client, the latest started first
+func (r *Repository) GetVPNClientSessions(_ context.Context, arg postgres.GetVPNClientSessionsParams) ([]postgres.VpnSession, error) {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	var items []postgres.VpnSession
+	for _, s := range r.sessions {
+		if s.UserID == arg.UserID && s.GroupID == arg.GroupID && !s.StartedAt.Before(arg.Since) && !s.StartedAt.After(arg.Until) {
+			items = append(items, s)
+		}
+	}
+	slices.SortFunc(items, func(a, b postgres.VpnSession) int {
+		if c := b.StartedAt.Compare(a.StartedAt); c != 0 {
+			return c
+		}
+		return int(b.ID - a.ID)
+	})
+	if len(items) > int(arg.RowLimit) {
+		items = items[:arg.RowLimit]
+	}
+	return items, nil
+}
+
+func (r *Repository) CreateVPNWebhookDelivery(_ context.Context, arg postgres.CreateVPNWebhookDeliveryParams) error {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	if r.deliveryErr != nil {
+		return r.deliveryErr
+	}
+
+	now := time.Now()
+	r.deliveries = append(r.deliveries, postgres.VpnWebhookDelivery{
+		ID:            int64(len(r.deliveries) + 1),
+		EventID:       arg.EventID,
+		EventType:     arg.EventType,
+		Url:           arg.Url,
+		Payload:       arg.Payload,
+		NextAttemptAt: now,
+		CreatedAt:     now,
+	})
+	return nil
+}
+
+// ClaimVPNWebhookDeliveries claims the due deliveries, the earliest due first
+func (r *Repository) ClaimVPNWebhookDeliveries(_ context.Context, arg postgres.ClaimVPNWebhookDeliveriesParams) ([]postgres.VpnWebhookDelivery, error) {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	var due []*postgres.VpnWebhookDelivery
+	for i := range r.deliveries {
+		if d := &r.deliveries[i]; !d.DeliveredAt.Valid && d.Attempts < arg.MaxAttempts && !d.NextAttemptAt.After(arg.Now) {
+			due = append(due, d)
+		}
+	}
+	slices.SortStableFunc(due, func(a, b *postgres.VpnWebhookDelivery) int {
+		return a.NextAttemptAt.Compare(b.NextAttemptAt)
+	})
+	if len(due) > int(arg.RowLimit) {
+		due = due[:arg.RowLimit]
+	}
+
+	items := make([]postgres.VpnWebhookDelivery, 0, len(due))
+	for _, d := range due {
+		d.NextAttemptAt = arg.LeaseUntil
+		items = append(items, *d)
+	}
+	return items, nil
+}
+
+func (r *Repository) MarkVPNWebhookDelivered(_ context.Context, arg postgres.MarkVPNWebhookDeliveredParams) error {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	if arg.ID < 1 || arg.ID > int64(len(r.deliveries)) {
+		return nil
+	}
+
+	d := &r.deliveries[arg.ID-1]
+	d.Attempts++
+	d.LastError = ""
+	d.DeliveredAt = pgtype.Timestamptz{Time: arg.DeliveredAt, Valid: true}
+	return nil
+}
+
+func (r *Repository) FailVPNWebhookDelivery(_ context.Context, arg postgres.FailVPNWebhookDeliveryParams) error {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	if arg.ID < 1 || arg.ID > int64(len(r.deliveries)) {
+		return nil
+	}
+
+	d := &r.deliveries[arg.ID-1]
+	d.Attempts++
+	d.NextAttemptAt = arg.NextAttemptAt
+	d.LastError = arg.LastError
+	return nil
+}
+
+func (r *Repository) ReplayVPNWebhookDeliveries(_ context.Context, arg postgres.ReplayVPNWebhookDeliveriesParams) (int64, error) {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	var affected int64
+	for i := range r.deliveries {
+		d := &r.deliveries[i]
+		if d.CreatedAt.Before(arg.Since) || d.CreatedAt.After(arg.Until) ||
+			(arg.EventType != "" && d.EventType != arg.EventType) ||
+			(arg.UndeliveredOnly && d.DeliveredAt.Valid) {
+			continue
+		}
+		d.Attempts = 0
+		d.NextAttemptAt = arg.Now
+		d.LastError = ""
+		d.DeliveredAt = pgtype.Timestamptz{}
+		affected++
+	}
+	return affected, nil
+}
@@ -0,0 +1,49 @@
+package wgtest
+
+import (
+	"context"
+	"github.com/cybericebox/wireguard/internal/model"
+	"slices"
+	"sync"
+)
+
+type (
+	// WebhookSender records the events sent to the webhook URLs instead of posting them.
+	// The error set by SetError is returned by Send, so the rejecting receivers can be tested.
+	WebhookSender struct {
+		m    sync.Mutex
+		sent []model.WebhookDelivery
+		err  error
+	}
+)
+
+func NewWebhookSender() *WebhookSender {
+	return &WebhookSender{}
+}
+
+// SetError makes Send fail with the error, nil makes it succeed again
+func (w *WebhookSender) SetError(err error) {
+	w.m.Lock()
+	defer w.m.Unlock()
+
+	w.err = err
+}
+
+// Sent returns the events sent successfully in the order they were sent
+func (w *WebhookSender) Sent() []model.WebhookDelivery {
+	w.m.Lock()
+	defer w.m.Unlock()
+
+	return slices.Clone(w.sent)
+}
+
+func (w *WebhookSender) Send(_ context.Context, delivery model.WebhookDelivery) error {
+	w.m.Lock()
+	defer w.m.Unlock()
+
+	if w.err != nil {
+		return w.err
+	}
+	w.sent = append(w.sent, delivery)
+	return nil
+}